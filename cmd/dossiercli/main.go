@@ -7,6 +7,11 @@ import (
 
 	"github.com/google/subcommands"
 	"github.com/hansmi/dossier/internal/clianalyzesketch"
+	"github.com/hansmi/dossier/internal/clidiffreport"
+	"github.com/hansmi/dossier/internal/clifmtsketch"
+	"github.com/hansmi/dossier/internal/clilintsketch"
+	"github.com/hansmi/dossier/internal/clirendersketch"
+	"github.com/hansmi/dossier/internal/clitext"
 	"github.com/hansmi/dossier/internal/webui"
 )
 
@@ -16,6 +21,11 @@ func main() {
 		subcommands.FlagsCommand(),
 		subcommands.CommandsCommand(),
 		&clianalyzesketch.Command{},
+		&clidiffreport.Command{},
+		&clifmtsketch.Command{},
+		&clilintsketch.Command{},
+		&clirendersketch.Command{},
+		&clitext.Command{},
 		&webui.Command{},
 	} {
 		subcommands.Register(cmd, "")
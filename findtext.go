@@ -0,0 +1,222 @@
+package dossier
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/pkg/pagerange"
+)
+
+type TextSearchScope int
+
+const (
+	// Search within single lines.
+	TextSearchLines TextSearchScope = iota
+
+	// Search within blocks. Matches may span multiple lines of the same
+	// block. Lines outside of any block are searched individually.
+	TextSearchBlocks
+
+	// Search across all lines of a page. Lines are joined using newlines.
+	// Blocks are ordered in rows and columns like [Page.Text].
+	TextSearchPage
+)
+
+type FindTextOptions struct {
+	Scope TextSearchScope
+}
+
+type TextSearchGroup struct {
+	// Capture group name. Empty if no name is set using (?P<...>).
+	Name string
+
+	// Zero-based start and end offset of the group in the searched text. Both
+	// are -1 if the group did not participate in the match.
+	Start int
+	End   int
+
+	// Text captured by the group.
+	Text string
+
+	// Bounds of the captured characters. Empty if the group did not
+	// participate in the match or captured no characters.
+	Bounds geometry.Rect
+}
+
+type TextSearchMatch struct {
+	// 1-based page number.
+	Page int
+
+	// Element containing the match. Nil when searching whole pages.
+	Element content.TextElement
+
+	// Complete searched text.
+	Text string
+
+	// All subgroups of the match. The first group is the complete match.
+	Groups []TextSearchGroup
+}
+
+// Bounds returns the bounds of the complete match.
+func (m *TextSearchMatch) Bounds() geometry.Rect {
+	return m.Groups[0].Bounds
+}
+
+type joinedTextPart struct {
+	elem  content.TextElement
+	start int
+	end   int
+}
+
+// joinedText is the concatenation of the text from multiple elements.
+type joinedText struct {
+	text  string
+	parts []joinedTextPart
+}
+
+func newJoinedText[T content.TextElement](elems []T, sep string) *joinedText {
+	var buf strings.Builder
+
+	result := &joinedText{}
+
+	for idx, elem := range elems {
+		if idx > 0 {
+			buf.WriteString(sep)
+		}
+
+		start := buf.Len()
+
+		buf.WriteString(elem.Text())
+
+		result.parts = append(result.parts, joinedTextPart{
+			elem:  elem,
+			start: start,
+			end:   buf.Len(),
+		})
+	}
+
+	result.text = buf.String()
+
+	return result
+}
+
+// Text returns the joined text.
+func (t *joinedText) Text() string {
+	return t.text
+}
+
+// RangeBounds returns the union of the bounds of all characters between start
+// and end. Separators don't have bounds.
+func (t *joinedText) RangeBounds(start, end int) geometry.Rect {
+	var started bool
+	var bounds geometry.Rect
+
+	for _, p := range t.parts {
+		first := max(start, p.start)
+		last := min(end, p.end)
+
+		if first < last {
+			if rbounds := p.elem.RangeBounds(first-p.start, last-p.start); started {
+				bounds = bounds.Union(rbounds)
+			} else {
+				started = true
+				bounds = rbounds
+			}
+		}
+	}
+
+	return bounds
+}
+
+func findTextIn(re *regexp.Regexp, page int, elem content.TextElement, text string, rangeBounds func(int, int) geometry.Rect) []TextSearchMatch {
+	var result []TextSearchMatch
+
+	names := re.SubexpNames()
+
+	for _, indexes := range re.FindAllStringSubmatchIndex(text, -1) {
+		m := TextSearchMatch{
+			Page:    page,
+			Element: elem,
+			Text:    text,
+			Groups:  make([]TextSearchGroup, len(indexes)/2),
+		}
+
+		for idx := range m.Groups {
+			g := &m.Groups[idx]
+			g.Name = names[idx]
+			g.Start = indexes[idx*2]
+			g.End = indexes[idx*2+1]
+
+			if g.Start >= 0 && g.End >= 0 {
+				g.Text = text[g.Start:g.End]
+
+				// Empty ranges, e.g. from optional groups, have no bounds.
+				if g.Start < g.End {
+					g.Bounds = rangeBounds(g.Start, g.End)
+				}
+			}
+		}
+
+		result = append(result, m)
+	}
+
+	return result
+}
+
+// FindText returns all non-overlapping matches of a regular expression on the
+// page. When searching lines or blocks the order of matches follows the order
+// of elements given by the parser.
+func (p *Page) FindText(re *regexp.Regexp, opts FindTextOptions) []TextSearchMatch {
+	var result []TextSearchMatch
+
+	switch opts.Scope {
+	case TextSearchPage:
+		var lines []content.Line
+
+		for _, elem := range p.orderedTextParagraphs() {
+			switch elem := elem.(type) {
+			case content.Block:
+				lines = append(lines, elem.Lines()...)
+
+			case content.Line:
+				lines = append(lines, elem)
+			}
+		}
+
+		joined := newJoinedText(lines, "\n")
+
+		result = findTextIn(re, p.num, nil, joined.Text(), joined.RangeBounds)
+
+	case TextSearchBlocks:
+		for _, elem := range p.textParagraphs() {
+			result = append(result, findTextIn(re, p.num, elem, elem.Text(), elem.RangeBounds)...)
+		}
+
+	default:
+		for _, l := range p.textLines() {
+			result = append(result, findTextIn(re, p.num, l, l.Text(), l.RangeBounds)...)
+		}
+	}
+
+	return result
+}
+
+// FindText parses the pages in the given range and returns all matches of
+// a regular expression. See [Page.FindText].
+func (d *Document) FindText(ctx context.Context, r pagerange.Range, re *regexp.Regexp, opts FindTextOptions) ([]TextSearchMatch, error) {
+	pages, err := d.ParsePages(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []TextSearchMatch
+
+	for _, p := range pages {
+		result = append(result, p.FindText(re, opts)...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,259 @@
+package dossier
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/pkg/pagerange"
+	"github.com/hansmi/dossier/pkg/parsertest"
+)
+
+type textSearchResult struct {
+	Page   int
+	Groups []string
+}
+
+func summarizeTextSearch(t *testing.T, matches []TextSearchMatch) []textSearchResult {
+	t.Helper()
+
+	var result []textSearchResult
+
+	for _, m := range matches {
+		r := textSearchResult{
+			Page: m.Page,
+		}
+
+		for _, g := range m.Groups {
+			r.Groups = append(r.Groups, g.Text)
+
+			if g.Start < 0 {
+				continue
+			}
+
+			if g.Start == g.End {
+				if !g.Bounds.IsEmpty() {
+					t.Errorf("Empty group at %d has bounds %v", g.Start, g.Bounds)
+				}
+
+				continue
+			}
+
+			if g.Bounds.IsEmpty() {
+				t.Errorf("Group %q has empty bounds", g.Text)
+			}
+
+			if m.Element != nil && !m.Element.Bounds().Contains(g.Bounds) {
+				t.Errorf("Bounds %v of group %q outside of element bounds %v", g.Bounds, g.Text, m.Element.Bounds())
+			}
+		}
+
+		result = append(result, r)
+	}
+
+	return result
+}
+
+func TestPageFindText(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		page content.Page
+		re   *regexp.Regexp
+		opts FindTextOptions
+		want []textSearchResult
+	}{
+		{
+			name: "empty",
+			page: &fakePage{},
+			re:   regexp.MustCompile(`.*`),
+			opts: FindTextOptions{Scope: TextSearchPage},
+			want: []textSearchResult{
+				{Groups: []string{""}},
+			},
+		},
+		{
+			name: "corners lines",
+			page: mustReadPages(t, "corners.xml")[0],
+			re:   regexp.MustCompile(`(?P<vert>[TB])(R)`),
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{"TR", "T", "R"}},
+				{Page: 1, Groups: []string{"BR", "B", "R"}},
+			},
+		},
+		{
+			name: "optional group",
+			page: mustReadPages(t, "corners.xml")[0],
+			re:   regexp.MustCompile(`T(R)?`),
+			opts: FindTextOptions{Scope: TextSearchBlocks},
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{"T", ""}},
+				{Page: 1, Groups: []string{"TR", "R"}},
+			},
+		},
+		{
+			name: "empty group in lines",
+			page: mustReadPages(t, "corners.xml")[0],
+			re:   regexp.MustCompile(`T(x?)`),
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{"T", ""}},
+				{Page: 1, Groups: []string{"T", ""}},
+			},
+		},
+		{
+			name: "empty group in blocks",
+			page: mustReadPages(t, "corners.xml")[0],
+			re:   regexp.MustCompile(`T(x?)`),
+			opts: FindTextOptions{Scope: TextSearchBlocks},
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{"T", ""}},
+				{Page: 1, Groups: []string{"T", ""}},
+			},
+		},
+		{
+			name: "empty group in page",
+			page: mustReadPages(t, "corners.xml")[0],
+			re:   regexp.MustCompile(`T(x?)`),
+			opts: FindTextOptions{Scope: TextSearchPage},
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{"T", ""}},
+				{Page: 1, Groups: []string{"T", ""}},
+			},
+		},
+		{
+			name: "empty match in lines",
+			page: mustReadPages(t, "corners.xml")[0],
+			re:   regexp.MustCompile(`^x*`),
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{""}},
+				{Page: 1, Groups: []string{""}},
+				{Page: 1, Groups: []string{""}},
+				{Page: 1, Groups: []string{""}},
+			},
+		},
+		{
+			name: "empty match in blocks",
+			page: mustReadPages(t, "corners.xml")[0],
+			re:   regexp.MustCompile(`^x*`),
+			opts: FindTextOptions{Scope: TextSearchBlocks},
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{""}},
+				{Page: 1, Groups: []string{""}},
+				{Page: 1, Groups: []string{""}},
+				{Page: 1, Groups: []string{""}},
+			},
+		},
+		{
+			name: "empty match in page",
+			page: mustReadPages(t, "corners.xml")[0],
+			re:   regexp.MustCompile(`^x*`),
+			opts: FindTextOptions{Scope: TextSearchPage},
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{""}},
+			},
+		},
+		{
+			name: "line outside of block",
+			page: &fakePage{
+				elems: []content.Element{
+					&fakeLine{
+						text:   "right",
+						bounds: geometry.RectFromCentimeters(5, 1, 7, 1.5),
+					},
+					&fakeLine{
+						text:   "left",
+						bounds: geometry.RectFromCentimeters(1, 1, 3, 1.5),
+					},
+				},
+			},
+			re:   regexp.MustCompile(`^\w+`),
+			opts: FindTextOptions{Scope: TextSearchBlocks},
+			want: []textSearchResult{
+				{Groups: []string{"right"}},
+				{Groups: []string{"left"}},
+			},
+		},
+		{
+			name: "page in rows and columns",
+			page: &fakePage{
+				elems: []content.Element{
+					&fakeLine{
+						text:   "right",
+						bounds: geometry.RectFromCentimeters(5, 1, 7, 1.5),
+					},
+					&fakeLine{
+						text:   "left",
+						bounds: geometry.RectFromCentimeters(1, 1, 3, 1.5),
+					},
+				},
+			},
+			re:   regexp.MustCompile(`(?s)left\s+right`),
+			opts: FindTextOptions{Scope: TextSearchPage},
+			want: []textSearchResult{
+				{Groups: []string{"left\nright"}},
+			},
+		},
+		{
+			name: "lines do not span",
+			page: mustReadPages(t, "lorem-mixed.xml")[0],
+			re:   regexp.MustCompile(`incidunt\s+ut`),
+		},
+		{
+			name: "blocks",
+			page: mustReadPages(t, "lorem-mixed.xml")[0],
+			re:   regexp.MustCompile(`(incidunt)\s+(ut)`),
+			opts: FindTextOptions{Scope: TextSearchBlocks},
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{"incidunt \nut", "incidunt", "ut"}},
+			},
+		},
+		{
+			name: "page",
+			page: mustReadPages(t, "lorem-mixed.xml")[0],
+			re:   regexp.MustCompile(`(?s)aliqua\.\s+At`),
+			opts: FindTextOptions{Scope: TextSearchPage},
+			want: []textSearchResult{
+				{Page: 1, Groups: []string{"aliqua.\nAt"}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newPage(nil, tc.page)
+			if err != nil {
+				t.Fatalf("newPage() failed: %v", err)
+			}
+
+			got := summarizeTextSearch(t, p.FindText(tc.re, tc.opts))
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FindText() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDocumentFindText(t *testing.T) {
+	doc := NewDocument(
+		testutil.MustWriteFile(t, filepath.Join(t.TempDir(), "empty"), nil),
+		WithStaticDocumentParser(&parsertest.SimpleParser{
+			Pages: mustReadPages(t, "multipage.xml"),
+		}))
+
+	got, err := doc.FindText(context.Background(), pagerange.All, regexp.MustCompile(`(\w+) page`), FindTextOptions{})
+	if err != nil {
+		t.Fatalf("FindText() failed: %v", err)
+	}
+
+	want := []textSearchResult{
+		{Page: 2, Groups: []string{"Second page", "Second"}},
+		{Page: 3, Groups: []string{"Third page", "Third"}},
+	}
+
+	if diff := cmp.Diff(want, summarizeTextSearch(t, got)); diff != "" {
+		t.Errorf("FindText() diff (-want +got):\n%s", diff)
+	}
+}
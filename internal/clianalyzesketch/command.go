@@ -19,7 +19,10 @@ import (
 type Command struct {
 	maxPages        int
 	textProtoFormat bool
+	explain         bool
 	lengthUnit      geometry.LengthUnit
+	params          map[string]any
+	sketchRoot      string
 
 	documentPath string
 	sketchPath   string
@@ -45,9 +48,15 @@ func (c *Command) SetFlags(fs *flag.FlagSet) {
 		"Maximum number of pages to analyze.")
 	fs.BoolVar(&c.textProtoFormat, "textproto", false,
 		"Write output using the Protocol Buffer text format instead of JSON.")
+	fs.BoolVar(&c.explain, "explain", false,
+		"Include a trace of the elements considered for each node and why they were rejected.")
 
 	lu := cliutil.NewLengthUnitVar(&c.lengthUnit, geometry.Millimeter)
 	fs.Var(lu, "unit", lu.Usage("Length unit for output."))
+
+	fs.Var(cliutil.NewParamsVar(&c.params), "param",
+		"Override a sketch parameter using name=value. May be repeated.")
+	cliutil.AddSketchRootFlag(fs, &c.sketchRoot)
 }
 
 func (c *Command) execute(ctx context.Context) error {
@@ -57,12 +66,12 @@ func (c *Command) execute(ctx context.Context) error {
 		return fmt.Errorf("document validation: %w", err)
 	}
 
-	sketchBytes, err := os.ReadFile(c.sketchPath)
+	dir, name, err := cliutil.SketchLocation(c.sketchRoot, c.sketchPath)
 	if err != nil {
-		return fmt.Errorf("reading sketch file: %w", err)
+		return err
 	}
 
-	s, err := sketch.CompileFromTextproto(sketchBytes)
+	s, err := sketch.CompileFileWithParams(os.DirFS(dir), name, c.params)
 	if err != nil {
 		return fmt.Errorf("parsing sketch: %w", err)
 	}
@@ -75,7 +84,13 @@ func (c *Command) execute(ctx context.Context) error {
 		}
 	}
 
-	report, err := s.AnalyzeDocument(ctx, doc, r)
+	var opts []sketch.AnalyzeOption
+
+	if c.explain {
+		opts = append(opts, sketch.WithTrace())
+	}
+
+	report, err := s.AnalyzeDocument(ctx, doc, r, opts...)
 	if err != nil {
 		return fmt.Errorf("analyzing document: %w", err)
 	}
@@ -0,0 +1,207 @@
+package clidiffreport
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/subcommands"
+	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/internal/cliutil"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/pkg/pagerange"
+	"github.com/hansmi/dossier/pkg/sketch"
+	"github.com/hansmi/dossier/proto/reportpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// errDifferent is returned when the reports differ and an exit code was
+// requested.
+var errDifferent = errors.New("reports differ")
+
+type Command struct {
+	documentPath string
+	maxPages     int
+	params       map[string]any
+	tolerance    geometry.Length
+	lengthUnit   geometry.LengthUnit
+	exitCode     bool
+	sketchRoot   string
+
+	oldPath string
+	newPath string
+}
+
+func (*Command) Name() string {
+	return "diff-report"
+}
+
+func (*Command) Synopsis() string {
+	return `Compare the nodes and computed values of two analysis reports.`
+}
+
+func (c *Command) Usage() string {
+	return `Arguments: ` + c.Name() + ` <old_report> <new_report>
+       ` + c.Name() + ` -document <document_file> <old_sketch_file> <new_sketch_file>
+
+Reports as written by analyze-sketch are read using the JSON format unless the
+file name ends in .textproto or .txtpb. With -document the document is
+analyzed using both sketches instead.
+
+Flags:
+`
+}
+
+func (c *Command) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.documentPath, "document", "",
+		"Analyze the document using the two given sketches instead of reading reports.")
+	fs.IntVar(&c.maxPages, "max_pages", 0,
+		"Maximum number of pages to analyze.")
+	fs.Var(cliutil.NewParamsVar(&c.params), "param",
+		"Override a sketch parameter using name=value. May be repeated.")
+	cliutil.AddSketchRootFlag(fs, &c.sketchRoot)
+	fs.Var(cliutil.NewLengthVar(&c.tolerance, geometry.Millimeter), "tolerance",
+		"Largest movement of a node edge not reported as a change, e.g. 0.5mm.")
+
+	lu := cliutil.NewLengthUnitVar(&c.lengthUnit, geometry.Millimeter)
+	fs.Var(lu, "unit", lu.Usage("Length unit for output."))
+
+	fs.BoolVar(&c.exitCode, "exit_code", false,
+		"Exit with a failure status if the reports differ.")
+}
+
+func readReport(path string) (*reportpb.Document, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pb := &reportpb.Document{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".textproto", ".txtpb":
+		err = prototext.Unmarshal(buf, pb)
+	default:
+		err = protojson.Unmarshal(buf, pb)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return pb, nil
+}
+
+func (c *Command) analyze(ctx context.Context, doc *dossier.Document, r pagerange.Range, sketchPath string) (*reportpb.Document, error) {
+	dir, name, err := cliutil.SketchLocation(c.sketchRoot, sketchPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := sketch.CompileFileWithParams(os.DirFS(dir), name, c.params)
+	if err != nil {
+		return nil, fmt.Errorf("parsing sketch %s: %w", sketchPath, err)
+	}
+
+	report, err := s.AnalyzeDocument(ctx, doc, r)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing document using %s: %w", sketchPath, err)
+	}
+
+	return report.AsProto(geometry.Pt), nil
+}
+
+// loadReports returns the old and the new report.
+func (c *Command) loadReports(ctx context.Context) (*reportpb.Document, *reportpb.Document, error) {
+	if c.documentPath == "" {
+		a, err := readReport(c.oldPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		b, err := readReport(c.newPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return a, b, nil
+	}
+
+	doc := dossier.NewDocument(c.documentPath)
+
+	if err := doc.Validate(ctx); err != nil {
+		return nil, nil, fmt.Errorf("document validation: %w", err)
+	}
+
+	r := pagerange.All
+
+	if c.maxPages != 0 {
+		var err error
+
+		if r, err = pagerange.New(1, c.maxPages); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Pages are parsed only once and shared by both analyses.
+	a, err := c.analyze(ctx, doc, r, c.oldPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b, err := c.analyze(ctx, doc, r, c.newPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a, b, nil
+}
+
+func (c *Command) execute(ctx context.Context) error {
+	a, b, err := c.loadReports(ctx)
+	if err != nil {
+		return err
+	}
+
+	diff, err := sketch.DiffReportProtos(a, b, sketch.WithBoundsTolerance(c.tolerance))
+	if err != nil {
+		return fmt.Errorf("comparing reports: %w", err)
+	}
+
+	if err := diff.Format(os.Stdout, c.lengthUnit); err != nil {
+		return err
+	}
+
+	if c.exitCode && !diff.Empty() {
+		return errDifferent
+	}
+
+	return nil
+}
+
+func (c *Command) Execute(ctx context.Context, fs *flag.FlagSet, args ...any) subcommands.ExitStatus {
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	c.oldPath = fs.Arg(0)
+	c.newPath = fs.Arg(1)
+
+	if err := c.execute(ctx); err != nil {
+		if errors.Is(err, errDifferent) {
+			return subcommands.ExitFailure
+		}
+
+		log.Printf("Error: %v", err)
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,108 @@
+package clifmtsketch
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/subcommands"
+	"github.com/hansmi/dossier/internal/cliutil"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/pkg/sketch"
+)
+
+type Command struct {
+	check      bool
+	lengthUnit geometry.LengthUnit
+}
+
+func (*Command) Name() string {
+	return "fmt-sketch"
+}
+
+func (*Command) Synopsis() string {
+	return `Rewrite sketch files in canonical form.`
+}
+
+func (c *Command) Usage() string {
+	return `Arguments: ` + c.Name() + ` <sketch_file>...
+
+Files are rewritten in place. Comments are retained.
+
+Flags:
+`
+}
+
+func (c *Command) SetFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.check, "check", false,
+		"Report files not in canonical form and exit with a non-zero status instead of rewriting them.")
+
+	lu := cliutil.NewLengthUnitVar(&c.lengthUnit, nil)
+	fs.Var(lu, "unit", lu.Usage("Convert all lengths to the given unit."))
+}
+
+// format rewrites a file and reports whether it was already formatted.
+func (c *Command) format(path string) (bool, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		return false, fmt.Errorf("%s: only sketches in the text format can be formatted", path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var opts []sketch.FormatOption
+
+	if c.lengthUnit != nil {
+		opts = append(opts, sketch.WithLengthUnit(c.lengthUnit))
+	}
+
+	formatted, err := sketch.Format(content, opts...)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if bytes.Equal(content, formatted) {
+		return true, nil
+	}
+
+	if c.check {
+		return false, nil
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	return false, os.WriteFile(path, formatted, fi.Mode().Perm())
+}
+
+func (c *Command) Execute(ctx context.Context, fs *flag.FlagSet, args ...any) subcommands.ExitStatus {
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	status := subcommands.ExitSuccess
+
+	for _, path := range fs.Args() {
+		ok, err := c.format(path)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			status = subcommands.ExitFailure
+		} else if !ok && c.check {
+			fmt.Println(path)
+			status = subcommands.ExitFailure
+		}
+	}
+
+	return status
+}
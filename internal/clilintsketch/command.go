@@ -0,0 +1,81 @@
+package clilintsketch
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/google/subcommands"
+	"github.com/hansmi/dossier/pkg/sketch"
+)
+
+type Command struct {
+	failOnWarnings bool
+}
+
+func (*Command) Name() string {
+	return "lint-sketch"
+}
+
+func (*Command) Synopsis() string {
+	return `Report likely mistakes in sketch files.`
+}
+
+func (c *Command) Usage() string {
+	return `Arguments: ` + c.Name() + ` <sketch_file>...
+
+Each diagnostic is written on a separate line ("<file>:<line>:<column>: <message> (<check>)").
+
+Flags:
+`
+}
+
+func (c *Command) SetFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.failOnWarnings, "fail_on_warnings", false,
+		"Exit with a non-zero status if any diagnostics are reported.")
+}
+
+// lint writes the diagnostics for a file and returns their number.
+func (c *Command) lint(w io.Writer, path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	diagnostics, err := sketch.Lint(path, content)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range diagnostics {
+		if _, err := fmt.Fprintln(w, d.String()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(diagnostics), nil
+}
+
+func (c *Command) Execute(ctx context.Context, fs *flag.FlagSet, args ...any) subcommands.ExitStatus {
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	status := subcommands.ExitSuccess
+
+	for _, path := range fs.Args() {
+		count, err := c.lint(os.Stdout, path)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			status = subcommands.ExitFailure
+		} else if count > 0 && c.failOnWarnings {
+			status = subcommands.ExitFailure
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,164 @@
+package clirendersketch
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/google/subcommands"
+	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/internal/cliutil"
+	"github.com/hansmi/dossier/pkg/pagerange"
+	"github.com/hansmi/dossier/pkg/renderformat"
+	"github.com/hansmi/dossier/pkg/sketch"
+	"github.com/hansmi/dossier/pkg/sketchrender"
+	"go.uber.org/multierr"
+)
+
+type Command struct {
+	maxPages int
+	format   string
+	width    int
+
+	sketchRoot string
+
+	documentPath string
+	sketchPath   string
+	outputDir    string
+}
+
+func (*Command) Name() string {
+	return "render-sketch"
+}
+
+func (*Command) Synopsis() string {
+	return `Analyze a document using a sketch and write annotated page images.`
+}
+
+func (c *Command) Usage() string {
+	return `Arguments: ` + c.Name() + ` <document_file> <sketch_file> <output_dir>
+
+One image per page is written to the output directory ("page-<number>.<format>").
+
+Flags:
+`
+}
+
+func (c *Command) SetFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.maxPages, "max_pages", 0,
+		"Maximum number of pages to render.")
+	fs.StringVar(&c.format, "format", "png",
+		"Image format (supported: png, svg).")
+	fs.IntVar(&c.width, "width", 1200,
+		"Image width in pixels.")
+	cliutil.AddSketchRootFlag(fs, &c.sketchRoot)
+}
+
+func (c *Command) newRenderer(w *os.File) (renderformat.Renderer, error) {
+	switch c.format {
+	case "png":
+		return &renderformat.PNG{Width: c.width, Output: w}, nil
+	case "svg":
+		return &renderformat.SVG{Width: c.width, Output: w}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported image format %q", c.format)
+}
+
+func (c *Command) writePage(ctx context.Context, p *dossier.Page, report *sketch.PageReport) (err error) {
+	path := filepath.Join(c.outputDir, fmt.Sprintf("page-%d.%s", p.Number(), c.format))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer multierr.AppendFunc(&err, f.Close)
+
+	r, err := c.newRenderer(f)
+	if err != nil {
+		return err
+	}
+
+	if err := sketchrender.RenderPage(ctx, p, report, r); err != nil {
+		return fmt.Errorf("page %d: %w", p.Number(), err)
+	}
+
+	log.Printf("Wrote %s", path)
+
+	return nil
+}
+
+func (c *Command) execute(ctx context.Context) error {
+	doc := dossier.NewDocument(c.documentPath)
+
+	if err := doc.Validate(ctx); err != nil {
+		return fmt.Errorf("document validation: %w", err)
+	}
+
+	dir, name, err := cliutil.SketchLocation(c.sketchRoot, c.sketchPath)
+	if err != nil {
+		return err
+	}
+
+	s, err := sketch.CompileFile(os.DirFS(dir), name)
+	if err != nil {
+		return fmt.Errorf("parsing sketch: %w", err)
+	}
+
+	r := pagerange.All
+
+	if c.maxPages != 0 {
+		if r, err = pagerange.New(1, c.maxPages); err != nil {
+			return err
+		}
+	}
+
+	pages, err := doc.ParsePages(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.outputDir, 0o755); err != nil {
+		return err
+	}
+
+	for _, p := range pages {
+		report, err := s.AnalyzePage(p)
+		if err != nil {
+			return fmt.Errorf("analyzing page %d: %w", p.Number(), err)
+		}
+
+		if err := c.writePage(ctx, p, report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Command) Execute(ctx context.Context, fs *flag.FlagSet, args ...any) subcommands.ExitStatus {
+	if fs.NArg() != 3 {
+		fs.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	c.documentPath = fs.Arg(0)
+	c.sketchPath = fs.Arg(1)
+	c.outputDir = fs.Arg(2)
+
+	if _, err := c.newRenderer(nil); err != nil {
+		log.Printf("Error: %v", err)
+		return subcommands.ExitUsageError
+	}
+
+	if err := c.execute(ctx); err != nil {
+		log.Printf("Error: %v", err)
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
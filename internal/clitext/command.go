@@ -0,0 +1,100 @@
+package clitext
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/google/subcommands"
+	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/pkg/pagerange"
+)
+
+type Command struct {
+	maxPages int
+	layout   bool
+
+	documentPath string
+}
+
+func (*Command) Name() string {
+	return "text"
+}
+
+func (*Command) Synopsis() string {
+	return `Print the text of a document.`
+}
+
+func (c *Command) Usage() string {
+	return `Arguments: ` + c.Name() + ` <document_file>
+
+Pages are separated by form feed characters.
+
+Flags:
+`
+}
+
+func (c *Command) SetFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.maxPages, "max_pages", 0,
+		"Maximum number of pages to print.")
+	fs.BoolVar(&c.layout, "layout", false,
+		"Maintain the physical layout of the text.")
+}
+
+func (c *Command) execute(ctx context.Context) error {
+	doc := dossier.NewDocument(c.documentPath)
+
+	if err := doc.Validate(ctx); err != nil {
+		return fmt.Errorf("document validation: %w", err)
+	}
+
+	r := pagerange.All
+
+	if c.maxPages != 0 {
+		var err error
+
+		if r, err = pagerange.New(1, c.maxPages); err != nil {
+			return err
+		}
+	}
+
+	pages, err := doc.ParsePages(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+
+	for idx, p := range pages {
+		if idx > 0 {
+			w.WriteByte('\f')
+		}
+
+		if c.layout {
+			w.WriteString(p.LayoutText(dossier.LayoutTextOptions{}))
+		} else {
+			w.WriteString(p.Text())
+		}
+	}
+
+	return w.Flush()
+}
+
+func (c *Command) Execute(ctx context.Context, fs *flag.FlagSet, args ...any) subcommands.ExitStatus {
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return subcommands.ExitUsageError
+	}
+
+	c.documentPath = fs.Arg(0)
+
+	if err := c.execute(ctx); err != nil {
+		log.Printf("Error: %v", err)
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,43 @@
+package cliutil
+
+import (
+	"flag"
+
+	"github.com/hansmi/dossier/pkg/geometry"
+)
+
+// LengthVar parses a length with a unit, e.g. "1.5mm".
+type LengthVar struct {
+	p *geometry.Length
+}
+
+var _ flag.Getter = (*LengthVar)(nil)
+
+func NewLengthVar(p *geometry.Length, def geometry.Length) *LengthVar {
+	*p = def
+
+	return &LengthVar{p}
+}
+
+func (v *LengthVar) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	return v.p.String()
+}
+
+func (v *LengthVar) Get() any {
+	return *v.p
+}
+
+func (v *LengthVar) Set(s string) error {
+	value, err := geometry.ParseLength(s)
+	if err != nil {
+		return err
+	}
+
+	*v.p = value
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package cliutil
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/pkg/geometry"
+)
+
+func TestLength(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		value   string
+		want    geometry.Length
+		wantErr bool
+	}{
+		{name: "mm", value: "2mm", want: 2 * geometry.Millimeter},
+		{name: "inch", value: "0.5in", want: geometry.Inch / 2},
+		{name: "no unit", value: "12", wantErr: true},
+		{name: "bad number", value: "xmm", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var flagValue geometry.Length
+
+			lv := NewLengthVar(&flagValue, geometry.Cm)
+
+			if diff := cmp.Diff(geometry.Cm, lv.Get()); diff != "" {
+				t.Errorf("Default value diff (-want +got):\n%s", diff)
+			}
+
+			err := lv.Set(tc.value)
+
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Set(%q) succeeded", tc.value)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Set(%q) failed: %v", tc.value, err)
+			}
+
+			if diff := cmp.Diff(tc.want, flagValue, geometry.EquateLengthApprox(0.001)); diff != "" {
+				t.Errorf("Value diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package cliutil
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParamsVar collects "name=value" pairs from a repeatable flag.
+type ParamsVar struct {
+	p *map[string]any
+}
+
+var _ flag.Getter = (*ParamsVar)(nil)
+
+func NewParamsVar(p *map[string]any) *ParamsVar {
+	return &ParamsVar{p}
+}
+
+func (v *ParamsVar) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	var pairs []string
+
+	for name, value := range *v.p {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", name, value))
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (v *ParamsVar) Get() any {
+	return *v.p
+}
+
+func (v *ParamsVar) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("parameter %q is not in the format name=value", s)
+	}
+
+	if *v.p == nil {
+		*v.p = map[string]any{}
+	}
+
+	if _, ok := (*v.p)[name]; ok {
+		return fmt.Errorf("parameter %q given multiple times", name)
+	}
+
+	(*v.p)[name] = value
+
+	return nil
+}
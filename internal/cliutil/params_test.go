@@ -0,0 +1,64 @@
+package cliutil
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParams(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		want    map[string]any
+		wantErr bool
+	}{
+		{name: "empty"},
+		{
+			name: "values",
+			args: []string{"-param", "customer=C-\\d+", "-param", "shift=2mm", "-param", "empty="},
+			want: map[string]any{
+				"customer": `C-\d+`,
+				"shift":    "2mm",
+				"empty":    "",
+			},
+		},
+		{
+			name:    "missing separator",
+			args:    []string{"-param", "customer"},
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			args:    []string{"-param", "=value"},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate",
+			args:    []string{"-param", "a=1", "-param", "a=2"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got map[string]any
+
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Var(NewParamsVar(&got), "param", "")
+
+			err := fs.Parse(tc.args)
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Parse() failed with %v, want error %t", err, tc.wantErr)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("Parameter diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
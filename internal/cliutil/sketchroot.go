@@ -0,0 +1,50 @@
+package cliutil
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+)
+
+// AddSketchRootFlag registers the "-sketch_root" flag setting the directory
+// sketch imports are resolved in. See [SketchLocation].
+func AddSketchRootFlag(fs *flag.FlagSet, p *string) {
+	fs.StringVar(p, "sketch_root", "",
+		"Directory containing the sketch and all files it imports, e.g. shared via \"../common/header.textproto\". "+
+			"Defaults to the working directory, or the directory of the sketch if it is outside.")
+}
+
+// SketchLocation returns the directory for loading a sketch and its imports
+// together with the slash-separated name of the sketch within it. Imports
+// can't refer to files outside of the directory.
+//
+// Without an explicit root the working directory is used if it contains the
+// sketch and the directory of the sketch otherwise.
+func SketchLocation(root, path string) (string, string, error) {
+	explicit := root != ""
+
+	if !explicit {
+		root = "."
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", "", err
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || !filepath.IsLocal(rel) {
+		if explicit {
+			return "", "", fmt.Errorf("sketch %s is outside of the sketch root %s", path, root)
+		}
+
+		return filepath.Dir(absPath), filepath.Base(absPath), nil
+	}
+
+	return absRoot, filepath.ToSlash(rel), nil
+}
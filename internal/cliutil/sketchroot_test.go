@@ -0,0 +1,69 @@
+package cliutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSketchLocation(t *testing.T) {
+	tmpdir := t.TempDir()
+
+	t.Chdir(tmpdir)
+
+	other := t.TempDir()
+
+	for _, tc := range []struct {
+		name     string
+		root     string
+		path     string
+		wantDir  string
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "working directory",
+			path:     "suppliers/acme.textproto",
+			wantDir:  tmpdir,
+			wantName: "suppliers/acme.textproto",
+		},
+		{
+			name:     "outside of working directory",
+			path:     filepath.Join(other, "sketch.textproto"),
+			wantDir:  other,
+			wantName: "sketch.textproto",
+		},
+		{
+			name:     "explicit root",
+			root:     "suppliers",
+			path:     "suppliers/acme/invoice.textproto",
+			wantDir:  filepath.Join(tmpdir, "suppliers"),
+			wantName: "acme/invoice.textproto",
+		},
+		{
+			name:    "outside of explicit root",
+			root:    "suppliers",
+			path:    "sketch.textproto",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, name, err := SketchLocation(tc.root, tc.path)
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("SketchLocation() failed with %v, want error %t", err, tc.wantErr)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(tc.wantDir, dir); diff != "" {
+					t.Errorf("Directory diff (-want +got):\n%s", diff)
+				}
+
+				if diff := cmp.Diff(tc.wantName, name); diff != "" {
+					t.Errorf("Name diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
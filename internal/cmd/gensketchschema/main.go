@@ -0,0 +1,31 @@
+// Command gensketchschema writes the JSON Schema for sketches in the JSON and
+// YAML formats.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/hansmi/dossier/internal/jsonschema"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+var output = flag.String("output", "", "Destination file.")
+
+func main() {
+	flag.Parse()
+
+	if *output == "" {
+		log.Fatal("Output file is required")
+	}
+
+	buf, err := jsonschema.Generate((*sketchpb.Sketch)(nil).ProtoReflect().Descriptor())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*output, buf, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,105 @@
+// Package expr implements a small expression language for values derived
+// from sketch nodes.
+//
+// Supported are number, text ("...") and bool (true, false) literals,
+// arithmetic (+, -, *, /), comparisons (==, !=, <, <=, >, >=), logical
+// operators (!, &&, ||), conditionals (cond ? a : b) and built-in functions.
+// Identifiers separated by dots, e.g. "net.amount", are references resolved
+// by the environment.
+//
+// Numbers are exact decimals. Texts are converted to numbers where a number
+// is required. Dates are produced by the date() function and support
+// comparisons as well as adding and subtracting days.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnavailable is returned by environments for references whose value is
+// not available, e.g. because a node wasn't found. The has() function turns
+// such errors into false.
+var ErrUnavailable = errors.New("value unavailable")
+
+// Env resolves references.
+type Env interface {
+	Lookup(path []string) (Value, error)
+}
+
+// Expr is a parsed expression.
+type Expr struct {
+	src  string
+	root node
+	refs []*referenceNode
+}
+
+// Parse parses an expression.
+func Parse(src string) (*Expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+
+	return &Expr{
+		src:  src,
+		root: root,
+		refs: p.refs,
+	}, nil
+}
+
+func (e *Expr) String() string {
+	return e.src
+}
+
+// References returns the paths of all references in order of appearance.
+func (e *Expr) References() [][]string {
+	var result [][]string
+
+	for _, ref := range e.refs {
+		result = append(result, slices.Clone(ref.path))
+	}
+
+	return result
+}
+
+// RewriteReferences returns the expression source with all references
+// replaced. Formatting is preserved.
+func (e *Expr) RewriteReferences(fn func(path []string) []string) string {
+	var buf strings.Builder
+
+	pos := 0
+
+	for _, ref := range e.refs {
+		buf.WriteString(e.src[pos:ref.start])
+		buf.WriteString(strings.Join(fn(slices.Clone(ref.path)), "."))
+		pos = ref.end
+	}
+
+	buf.WriteString(e.src[pos:])
+
+	return buf.String()
+}
+
+// Eval evaluates the expression.
+func (e *Expr) Eval(env Env) (Value, error) {
+	v, err := e.root.eval(env)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating %q: %w", e.src, err)
+	}
+
+	return v, nil
+}
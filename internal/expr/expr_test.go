@@ -0,0 +1,213 @@
+package expr
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type fakeEnv map[string]Value
+
+func (e fakeEnv) Lookup(path []string) (Value, error) {
+	name := strings.Join(path, ".")
+
+	if v, ok := e[name]; ok {
+		return v, nil
+	}
+
+	if name == "broken" {
+		return nil, fmt.Errorf("broken reference")
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnavailable, name)
+}
+
+func TestEval(t *testing.T) {
+	env := fakeEnv{
+		"net.amount":   "1'200.50",
+		"vat.amount":   "96.04",
+		"gross.amount": "1,296.54",
+		"german":       "1.296,54",
+		"comma":        "12,50",
+		"grouped":      "1,234,567",
+		"invoice.date": "15.03.2024",
+		"due.date":     "14.04.2024",
+		"name":         "ACME",
+		"flag":         true,
+	}
+
+	for _, tc := range []struct {
+		expr    string
+		want    string
+		wantErr error
+	}{
+		{expr: `1`, want: "1"},
+		{expr: `1.25 + 2`, want: "3.25"},
+		{expr: `0.1 + 0.2 == 0.3`, want: "true"},
+		{expr: `1 / 3`, want: "0.3333333333"},
+		{expr: `2 * (3 + 4) - -1`, want: "15"},
+		{expr: `"a" == "a" && !false`, want: "true"},
+		{expr: `true || broken`, want: "true"},
+		{expr: `false && broken`, want: "false"},
+		{expr: `net.amount + vat.amount == gross.amount`, want: "true"},
+		{expr: `net.amount + vat.amount`, want: "1296.54"},
+		{expr: `number(german, ",") == gross.amount`, want: "true"},
+		{expr: `number(comma, ",") == 12.5`, want: "true"},
+		{expr: `grouped + 0`, want: "1234567"},
+		{expr: `comma + 0`, wantErr: cmpopts.AnyError},
+		{expr: `german + 0`, wantErr: cmpopts.AnyError},
+		{expr: `comma == 12.5`, wantErr: cmpopts.AnyError},
+		{expr: `number(comma) == 12.5`, wantErr: cmpopts.AnyError},
+		{expr: `"1,23" + 0`, wantErr: cmpopts.AnyError},
+		{expr: `"1,2345.00" + 0`, wantErr: cmpopts.AnyError},
+		{expr: `sum(net.amount, vat.amount, 1)`, want: "1297.54"},
+		{expr: `min(3, "2", 5) + max(1, 4)`, want: "6"},
+		{expr: `abs(-2.5)`, want: "2.5"},
+		{expr: `round(2.345, 2)`, want: "2.35"},
+		{expr: `round(-2.345, 2)`, want: "-2.35"},
+		{expr: `round(2.344, 0)`, want: "2"},
+		{expr: `len(name)`, want: "4"},
+		{expr: `matches(name, "^AC")`, want: "true"},
+		{expr: `date(due.date, "02.01.2006") > date(invoice.date, "02.01.2006")`, want: "true"},
+		{expr: `date(due.date, "02.01.2006") - date(invoice.date, "02.01.2006")`, want: "30"},
+		{expr: `date(invoice.date, "02.01.2006") + 30`, want: "2024-04-14"},
+		{expr: `max(date(due.date, "02.01.2006"), date(invoice.date, "02.01.2006"))`, want: "2024-04-14"},
+		{expr: `has(name) && !has(missing)`, want: "true"},
+		{expr: `flag ? name : "other"`, want: "ACME"},
+		{expr: `name != "ACME" ? 1 : 2`, want: "2"},
+		{expr: `missing`, wantErr: ErrUnavailable},
+		{expr: `missing.value + 1`, wantErr: ErrUnavailable},
+		{expr: `1 / 0`, wantErr: cmpopts.AnyError},
+		{expr: `name + 1`, wantErr: cmpopts.AnyError},
+		{expr: `name < "B"`, wantErr: cmpopts.AnyError},
+		{expr: `flag == 1`, wantErr: cmpopts.AnyError},
+		{expr: `!name`, wantErr: cmpopts.AnyError},
+		{expr: `date(name, "2006")`, wantErr: cmpopts.AnyError},
+		{expr: `date(invoice.date, "02.01.2006") == "2024-03-15"`, wantErr: cmpopts.AnyError},
+		{expr: `round(1, 0.5)`, wantErr: cmpopts.AnyError},
+		{expr: `has(broken)`, wantErr: cmpopts.AnyError},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+
+			got, err := e.Eval(env)
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(tc.want, Format(got)); diff != "" {
+					t.Errorf("Eval() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`1 +`,
+		`(1`,
+		`1)`,
+		`1 2`,
+		`1.2.3`,
+		`"abc`,
+		`a.`,
+		`a.1`,
+		`1 < 2 < 3`,
+		`unknown(1)`,
+		`has(1)`,
+		`has(a, b)`,
+		`round(1)`,
+		`len(1, 2)`,
+		`a ? b`,
+		`a # b`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Parse(expr); err == nil {
+				t.Errorf("Parse(%q) succeeded", expr)
+			}
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	e, err := Parse(`has(a.b) ? sum(a.b,  c) : d . e`)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	want := [][]string{{"a", "b"}, {"a", "b"}, {"c"}, {"d", "e"}}
+
+	if diff := cmp.Diff(want, e.References()); diff != "" {
+		t.Errorf("References() diff (-want +got):\n%s", diff)
+	}
+
+	got := e.RewriteReferences(func(path []string) []string {
+		return append([]string{"ns"}, path...)
+	})
+
+	if diff := cmp.Diff(`has(ns.a.b) ? sum(ns.a.b,  ns.c) : ns.d.e`, got); diff != "" {
+		t.Errorf("RewriteReferences() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	for _, tc := range []struct {
+		text    string
+		sep     string
+		want    *big.Rat
+		wantErr bool
+	}{
+		{text: "12", sep: ".", want: big.NewRat(12, 1)},
+		{text: " -1,234.5 ", sep: ".", want: big.NewRat(-12345, 10)},
+		{text: "1'234.50", sep: ".", want: big.NewRat(12345, 10)},
+		{text: "1 234,50", sep: ",", want: big.NewRat(12345, 10)},
+		{text: "1.234.567,8", sep: ",", want: big.NewRat(12345678, 10)},
+		{text: "", sep: ".", wantErr: true},
+		{text: "1e3", sep: ".", wantErr: true},
+		{text: "12 EUR", sep: ".", wantErr: true},
+		{text: "1.2.3", sep: ".", wantErr: true},
+		{text: "1", sep: ";", wantErr: true},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			got, err := ParseNumber(tc.text, tc.sep)
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseNumber() failed with %v, want error %t", err, tc.wantErr)
+			}
+
+			if err == nil && got.Cmp(tc.want) != 0 {
+				t.Errorf("ParseNumber() returned %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, tc := range []struct {
+		value Value
+		want  string
+	}{
+		{value: true, want: "true"},
+		{value: big.NewRat(5, 2), want: "2.5"},
+		{value: big.NewRat(-7, 1), want: "-7"},
+		{value: big.NewRat(2, 3), want: "0.6666666667"},
+		{value: "text", want: "text"},
+		{value: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), want: "2024-03-05"},
+	} {
+		if got := Format(tc.value); got != tc.want {
+			t.Errorf("Format(%v) returned %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
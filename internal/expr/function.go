@@ -0,0 +1,217 @@
+package expr
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+type function struct {
+	// Number of arguments. No upper limit if maxArgs is negative.
+	minArgs, maxArgs int
+
+	call func([]Value) (Value, error)
+}
+
+// functions contains the built-in functions. The "has" function is handled
+// by the parser as it takes a reference instead of a value.
+var functions = map[string]*function{
+	// number(value[, decimal_separator]) converts text to a number.
+	"number": {
+		minArgs: 1,
+		maxArgs: 2,
+		call: func(args []Value) (Value, error) {
+			if len(args) == 1 {
+				return toNumber(args[0])
+			}
+
+			sep, err := toText(args[1])
+			if err != nil {
+				return nil, err
+			}
+
+			if r, ok := args[0].(*big.Rat); ok {
+				return r, nil
+			}
+
+			text, err := toText(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			return ParseNumber(text, sep)
+		},
+	},
+
+	// date(value, layout) parses a date using a Go time layout, e.g.
+	// "02.01.2006".
+	"date": {
+		minArgs: 2,
+		maxArgs: 2,
+		call: func(args []Value) (Value, error) {
+			text, err := toText(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			layout, err := toText(args[1])
+			if err != nil {
+				return nil, err
+			}
+
+			return time.Parse(layout, text)
+		},
+	},
+
+	// sum(values...) adds all values.
+	"sum": {
+		minArgs: 1,
+		maxArgs: -1,
+		call: func(args []Value) (Value, error) {
+			result := new(big.Rat)
+
+			for _, arg := range args {
+				r, err := toNumber(arg)
+				if err != nil {
+					return nil, err
+				}
+
+				result.Add(result, r)
+			}
+
+			return result, nil
+		},
+	},
+
+	// min(values...) returns the smallest value.
+	"min": {
+		minArgs: 1,
+		maxArgs: -1,
+		call: func(args []Value) (Value, error) {
+			return extremum(args, -1)
+		},
+	},
+
+	// max(values...) returns the largest value.
+	"max": {
+		minArgs: 1,
+		maxArgs: -1,
+		call: func(args []Value) (Value, error) {
+			return extremum(args, 1)
+		},
+	},
+
+	// abs(value) returns the absolute value.
+	"abs": {
+		minArgs: 1,
+		maxArgs: 1,
+		call: func(args []Value) (Value, error) {
+			r, err := toNumber(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			return new(big.Rat).Abs(r), nil
+		},
+	},
+
+	// round(value, digits) rounds to the given number of fractional digits.
+	// Halfway values are rounded away from zero.
+	"round": {
+		minArgs: 2,
+		maxArgs: 2,
+		call: func(args []Value) (Value, error) {
+			r, err := toNumber(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			digits, err := toNumber(args[1])
+			if err != nil {
+				return nil, err
+			}
+
+			if !digits.IsInt() || digits.Sign() < 0 || digits.Num().Cmp(big.NewInt(30)) > 0 {
+				return nil, errors.New("digits must be a whole number between 0 and 30")
+			}
+
+			return roundRat(r, int(digits.Num().Int64())), nil
+		},
+	},
+
+	// len(text) returns the number of characters.
+	"len": {
+		minArgs: 1,
+		maxArgs: 1,
+		call: func(args []Value) (Value, error) {
+			text, err := toText(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			return new(big.Rat).SetInt64(int64(utf8.RuneCountInString(text))), nil
+		},
+	},
+
+	// matches(text, regex) reports whether the text matches a regular
+	// expression.
+	"matches": {
+		minArgs: 2,
+		maxArgs: 2,
+		call: func(args []Value) (Value, error) {
+			text, err := toText(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			pattern, err := toText(args[1])
+			if err != nil {
+				return nil, err
+			}
+
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+
+			return re.MatchString(text), nil
+		},
+	},
+}
+
+func extremum(args []Value, sign int) (Value, error) {
+	result := args[0]
+
+	for _, arg := range args[1:] {
+		cmp, err := compare(arg, result, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp == sign {
+			result = arg
+		}
+	}
+
+	if _, ok := result.(time.Time); ok {
+		return result, nil
+	}
+
+	return toNumber(result)
+}
+
+func roundRat(r *big.Rat, digits int) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(scale))
+
+	// Add or subtract one half before truncating
+	half := big.NewRat(int64(scaled.Sign()), 2)
+	scaled.Add(scaled, half)
+
+	truncated := new(big.Int).Quo(scaled.Num(), scaled.Denom())
+
+	return new(big.Rat).SetFrac(truncated, scale)
+}
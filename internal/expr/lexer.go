@@ -0,0 +1,133 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+
+	// Source text of the token. Unquoted value for strings.
+	text string
+
+	// Byte offsets within the expression source.
+	start, end int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+
+	return strconv.Quote(t.text)
+}
+
+// operators are sorted such that longer operators come first.
+var operators = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "<", ">", "!", "(", ")", ",", "?", ":", ".",
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+// tokenize splits an expression into tokens. The last token is always of kind
+// tokenEOF.
+func tokenize(src string) ([]token, error) {
+	var result []token
+
+	for pos := 0; pos < len(src); {
+		r, size := utf8.DecodeRuneInString(src[pos:])
+
+		if unicode.IsSpace(r) {
+			pos += size
+			continue
+		}
+
+		start := pos
+
+		switch {
+		case r >= '0' && r <= '9':
+			for pos < len(src) && (src[pos] >= '0' && src[pos] <= '9' || src[pos] == '.') {
+				pos++
+			}
+
+			result = append(result, token{kind: tokenNumber, text: src[start:pos], start: start, end: pos})
+
+		case r == '"':
+			pos++
+
+			for pos < len(src) && src[pos] != '"' {
+				if src[pos] == '\\' {
+					pos++
+				}
+
+				pos++
+			}
+
+			if pos >= len(src) {
+				return nil, fmt.Errorf("offset %d: unterminated string", start)
+			}
+
+			pos++
+
+			text, err := strconv.Unquote(src[start:pos])
+			if err != nil {
+				return nil, fmt.Errorf("offset %d: invalid string %s: %w", start, src[start:pos], err)
+			}
+
+			result = append(result, token{kind: tokenString, text: text, start: start, end: pos})
+
+		case isIdentStart(r):
+			for pos < len(src) {
+				r, size := utf8.DecodeRuneInString(src[pos:])
+				if !isIdentPart(r) {
+					break
+				}
+
+				pos += size
+			}
+
+			result = append(result, token{kind: tokenIdent, text: src[start:pos], start: start, end: pos})
+
+		default:
+			var op string
+
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[pos:], candidate) {
+					op = candidate
+					break
+				}
+			}
+
+			if op == "" {
+				return nil, fmt.Errorf("offset %d: unexpected character %q", start, r)
+			}
+
+			pos += len(op)
+
+			result = append(result, token{kind: tokenOperator, text: op, start: start, end: pos})
+		}
+	}
+
+	return append(result, token{kind: tokenEOF, start: len(src), end: len(src)}), nil
+}
@@ -0,0 +1,248 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+type node interface {
+	eval(Env) (Value, error)
+}
+
+type literalNode struct {
+	value Value
+}
+
+func (n *literalNode) eval(Env) (Value, error) {
+	return n.value, nil
+}
+
+type referenceNode struct {
+	path []string
+
+	// Byte offsets within the expression source.
+	start, end int
+}
+
+func (n *referenceNode) eval(env Env) (Value, error) {
+	return env.Lookup(n.path)
+}
+
+// hasNode reports whether a reference is available.
+type hasNode struct {
+	ref *referenceNode
+}
+
+const hasFunction = "has"
+
+func (n *hasNode) eval(env Env) (Value, error) {
+	if _, err := n.ref.eval(env); err != nil {
+		if errors.Is(err, ErrUnavailable) {
+			return false, nil
+		}
+
+		return nil, err
+	}
+
+	return true, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(env Env) (Value, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "!" {
+		b, err := toBool(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return !b, nil
+	}
+
+	r, err := toNumber(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Rat).Neg(r), nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) evalLogical(env Env, left Value) (Value, error) {
+	a, err := toBool(left)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short-circuit evaluation
+	if a == (n.op == "||") {
+		return a, nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBool(right)
+}
+
+// evalDate implements date arithmetic. The difference between two dates is
+// given in days.
+func (n *binaryNode) evalDate(a time.Time, right Value) (Value, error) {
+	if b, ok := right.(time.Time); ok && n.op == "-" {
+		return new(big.Rat).SetInt64(int64(a.Sub(b).Round(time.Hour).Hours() / 24)), nil
+	}
+
+	days, err := toNumber(right)
+	if err != nil || !days.IsInt() || !days.Num().IsInt64() {
+		return nil, fmt.Errorf("operator %q requires a whole number of days to be used with a date", n.op)
+	}
+
+	switch n.op {
+	case "+":
+		return a.AddDate(0, 0, int(days.Num().Int64())), nil
+	case "-":
+		return a.AddDate(0, 0, -int(days.Num().Int64())), nil
+	}
+
+	return nil, fmt.Errorf("operator %q is not supported for dates", n.op)
+}
+
+func (n *binaryNode) evalArithmetic(left, right Value) (Value, error) {
+	if a, ok := left.(time.Time); ok {
+		return n.evalDate(a, right)
+	}
+
+	a, err := toNumber(left)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := toNumber(right)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(big.Rat)
+
+	switch n.op {
+	case "+":
+		result.Add(a, b)
+	case "-":
+		result.Sub(a, b)
+	case "*":
+		result.Mul(a, b)
+	case "/":
+		if b.Sign() == 0 {
+			return nil, errors.New("division by zero")
+		}
+
+		result.Quo(a, b)
+	}
+
+	return result, nil
+}
+
+func (n *binaryNode) eval(env Env) (Value, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "&&" || n.op == "||" {
+		return n.evalLogical(env, left)
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "+", "-", "*", "/":
+		return n.evalArithmetic(left, right)
+	}
+
+	cmp, err := compare(left, right, !(n.op == "==" || n.op == "!="))
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+
+	return cmp >= 0, nil
+}
+
+type conditionalNode struct {
+	cond, then, otherwise node
+}
+
+func (n *conditionalNode) eval(env Env) (Value, error) {
+	v, err := n.cond.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, err := toBool(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if cond {
+		return n.then.eval(env)
+	}
+
+	return n.otherwise.eval(env)
+}
+
+type callNode struct {
+	name string
+	fn   *function
+	args []node
+}
+
+func (n *callNode) eval(env Env) (Value, error) {
+	args := make([]Value, 0, len(n.args))
+
+	for _, arg := range n.args {
+		v, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, v)
+	}
+
+	result, err := n.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", n.name, err)
+	}
+
+	return result, nil
+}
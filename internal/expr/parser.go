@@ -0,0 +1,272 @@
+package expr
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+
+	// All references in order of appearance.
+	refs []*referenceNode
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+// accept consumes the next token if it's one of the given operators.
+func (p *parser) accept(ops ...string) (token, bool) {
+	if t := p.peek(); t.kind == tokenOperator && slices.Contains(ops, t.text) {
+		return p.next(), true
+	}
+
+	return token{}, false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		return p.unexpected()
+	}
+
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+
+	return fmt.Errorf("offset %d: unexpected %s", t.start, t)
+}
+
+// parseExpr parses a conditional expression:
+//
+//	or ["?" expr ":" expr]
+func (p *parser) parseExpr() (node, error) {
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+
+	otherwise, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return &conditionalNode{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+func (p *parser) parseBinary(ops []string, operand func() (node, error)) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryNode{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseBinary([]string{"||"}, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary([]string{"&&"}, p.parseComparison)
+}
+
+// parseComparison parses a non-associative comparison.
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	return &binaryNode{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseBinary([]string{"+", "-"}, p.parseMultiplicative)
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseBinary([]string{"*", "/"}, p.parseUnary)
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if t, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryNode{op: t.text, operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePath parses a dot-separated reference following its first segment.
+func (p *parser) parsePath(first token) (*referenceNode, error) {
+	ref := &referenceNode{
+		path:  []string{first.text},
+		start: first.start,
+		end:   first.end,
+	}
+
+	for {
+		if _, ok := p.accept("."); !ok {
+			p.refs = append(p.refs, ref)
+
+			return ref, nil
+		}
+
+		t := p.peek()
+		if t.kind != tokenIdent {
+			return nil, p.unexpected()
+		}
+
+		p.next()
+
+		ref.path = append(ref.path, t.text)
+		ref.end = t.end
+	}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok && name.text != hasFunction {
+		return nil, fmt.Errorf("offset %d: unknown function %q", name.start, name.text)
+	}
+
+	var args []node
+
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, arg)
+
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if name.text == hasFunction {
+		if len(args) == 1 {
+			if ref, ok := args[0].(*referenceNode); ok {
+				return &hasNode{ref: ref}, nil
+			}
+		}
+
+		return nil, fmt.Errorf("offset %d: %s() requires a single reference", name.start, hasFunction)
+	}
+
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("offset %d: wrong number of arguments for %s()", name.start, name.text)
+	}
+
+	return &callNode{name: name.text, fn: fn, args: args}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+
+	if t.kind == tokenEOF || (t.kind == tokenOperator && t.text != "(") {
+		return nil, p.unexpected()
+	}
+
+	p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		if !numberPattern.MatchString(t.text) {
+			return nil, fmt.Errorf("offset %d: invalid number %q", t.start, t.text)
+		}
+
+		value, _ := new(big.Rat).SetString(t.text)
+
+		return &literalNode{value: value}, nil
+
+	case tokenString:
+		return &literalNode{value: t.text}, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		}
+
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+
+		return p.parsePath(t)
+	}
+
+	inner, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return inner, nil
+}
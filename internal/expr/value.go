@@ -0,0 +1,201 @@
+package expr
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Value is the result of an expression. Supported types are bool, *big.Rat
+// for numbers, string for texts and time.Time for dates.
+type Value any
+
+// DateLayout is used for formatting dates.
+const DateLayout = "2006-01-02"
+
+var numberPattern = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+
+// commaGroupingPattern matches numbers using commas to separate groups of
+// three digits and an optional decimal point.
+var commaGroupingPattern = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+(\.\d+)?$`)
+
+// numberGroupSeparators are removed from numbers before parsing.
+var numberGroupSeparators = strings.NewReplacer(
+	" ", "",
+	"'", "",
+	"\u00a0", "",
+	"\u2009", "",
+	"\u202f", "",
+	"\u2019", "",
+)
+
+// ParseNumber converts text to a number. Spaces and apostrophes are ignored.
+// The decimal separator is either "." or ",". The other is ignored as
+// a thousands separator.
+func ParseNumber(text, decimalSeparator string) (*big.Rat, error) {
+	s := numberGroupSeparators.Replace(strings.TrimSpace(text))
+
+	switch decimalSeparator {
+	case ".":
+		s = strings.ReplaceAll(s, ",", "")
+	case ",":
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	default:
+		return nil, fmt.Errorf("unsupported decimal separator %q", decimalSeparator)
+	}
+
+	if numberPattern.MatchString(s) {
+		if r, ok := new(big.Rat).SetString(s); ok {
+			return r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%q is not a number", text)
+}
+
+// typeName returns the name of a value type for error messages.
+func typeName(v Value) string {
+	switch v.(type) {
+	case bool:
+		return "bool"
+	case *big.Rat:
+		return "number"
+	case string:
+		return "text"
+	case time.Time:
+		return "date"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+// FormatNumber formats a number using the shortest exact decimal
+// representation. Numbers without one are rounded to ten fractional digits.
+func FormatNumber(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	for prec := 1; prec < 10; prec++ {
+		s := r.FloatString(prec)
+
+		if exact, ok := new(big.Rat).SetString(s); ok && exact.Cmp(r) == 0 {
+			return s
+		}
+	}
+
+	return r.FloatString(10)
+}
+
+// Format returns the textual representation of a value.
+func Format(v Value) string {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return "true"
+		}
+
+		return "false"
+
+	case *big.Rat:
+		return FormatNumber(v)
+
+	case string:
+		return v
+
+	case time.Time:
+		return v.Format(DateLayout)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// toNumber converts numbers and texts to numbers. Texts must use "." as the
+// decimal separator.
+func toNumber(v Value) (*big.Rat, error) {
+	switch v := v.(type) {
+	case *big.Rat:
+		return v, nil
+
+	case string:
+		// Commas are ambiguous, e.g. "12,50" is likely to use a decimal
+		// comma. Only well-formed groups of thousands are converted
+		// implicitly.
+		if strings.Contains(v, ",") &&
+			!commaGroupingPattern.MatchString(numberGroupSeparators.Replace(strings.TrimSpace(v))) {
+			return nil, fmt.Errorf(`%q is ambiguous, use number(text, ",") or number(text, ".") to convert it`, v)
+		}
+
+		return ParseNumber(v, ".")
+	}
+
+	return nil, fmt.Errorf("%s value can't be used as a number", typeName(v))
+}
+
+func toBool(v Value) (bool, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+
+	return false, fmt.Errorf("%s value can't be used as a bool", typeName(v))
+}
+
+func toText(v Value) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	return "", fmt.Errorf("%s value can't be used as text", typeName(v))
+}
+
+// compare returns -1, 0 or 1 depending on the order of the values. Numbers are
+// compared numerically with texts converted to numbers. Dates can only be
+// compared with other dates. If ordered is false texts and bools are compared
+// for equality.
+func compare(a, b Value, ordered bool) (int, error) {
+	_, aNumber := a.(*big.Rat)
+	_, bNumber := b.(*big.Rat)
+
+	aDate, aIsDate := a.(time.Time)
+	bDate, bIsDate := b.(time.Time)
+
+	switch {
+	case aIsDate && bIsDate:
+		return aDate.Compare(bDate), nil
+
+	case aIsDate || bIsDate:
+
+	case aNumber || bNumber || ordered:
+		x, err := toNumber(a)
+		if err != nil {
+			return 0, err
+		}
+
+		y, err := toNumber(b)
+		if err != nil {
+			return 0, err
+		}
+
+		return x.Cmp(y), nil
+
+	default:
+		switch a := a.(type) {
+		case string:
+			if b, ok := b.(string); ok {
+				return strings.Compare(a, b), nil
+			}
+
+		case bool:
+			if b, ok := b.(bool); ok && a == b {
+				return 0, nil
+			} else if ok {
+				return 1, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("%s and %s values can't be compared", typeName(a), typeName(b))
+}
@@ -0,0 +1,209 @@
+package flexrect
+
+import (
+	"fmt"
+
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+// AdjacentArea is a rectangle next to another node, extending in a given
+// direction.
+type AdjacentArea struct {
+	node      string
+	direction geometry.Direction
+
+	// Fixed extent in the search direction. Zero when not set.
+	distance geometry.Length
+
+	// Number of lines in the search direction. Zero when not set.
+	lines      int
+	lineHeight geometry.Length
+
+	spanPage bool
+	margin   geometry.Length
+}
+
+func AdjacentAreaFromProto(pb *sketchpb.AdjacentArea) (*AdjacentArea, error) {
+	if pb.GetNode() == "" {
+		return nil, fmt.Errorf("%w: adjacent area requires node name", sketcherror.ErrIncompleteConfig)
+	}
+
+	return adjacentAreaFromProto(pb)
+}
+
+// RelativeAreaFromProto builds an area next to a reference rectangle supplied
+// when resolving via [AdjacentArea.ResolveFrom]. The node name must be empty.
+func RelativeAreaFromProto(pb *sketchpb.AdjacentArea) (*AdjacentArea, error) {
+	if pb.GetNode() != "" {
+		return nil, fmt.Errorf("%w: relative area must not reference node %q", sketcherror.ErrBadConfig, pb.GetNode())
+	}
+
+	return adjacentAreaFromProto(pb)
+}
+
+func adjacentAreaFromProto(pb *sketchpb.AdjacentArea) (*AdjacentArea, error) {
+	var err error
+
+	a := &AdjacentArea{
+		node:     pb.GetNode(),
+		spanPage: pb.GetSpanPage(),
+	}
+
+	if a.direction, err = DirectionFromProto(pb.GetDirection()); err != nil {
+		return nil, fmt.Errorf("adjacent area: %w", err)
+	}
+
+	switch e := pb.GetExtent().(type) {
+	case *sketchpb.AdjacentArea_Distance:
+		if a.distance, err = geometry.LengthFromProto(e.Distance); err != nil {
+			return nil, fmt.Errorf("adjacent area distance: %w", err)
+		}
+
+		if a.distance <= 0 {
+			return nil, fmt.Errorf("%w: adjacent area requires positive distance, got %s",
+				sketcherror.ErrBadConfig, a.distance.String())
+		}
+
+	case *sketchpb.AdjacentArea_Lines:
+		if a.direction.Horizontal() {
+			return nil, fmt.Errorf("%w: adjacent area lines require vertical direction, got %s",
+				sketcherror.ErrBadConfig, a.direction.String())
+		}
+
+		if a.lines = int(e.Lines); a.lines < 1 {
+			return nil, fmt.Errorf("%w: adjacent area requires at least one line", sketcherror.ErrBadConfig)
+		}
+	}
+
+	if pb.LineHeight != nil {
+		if a.lines == 0 {
+			return nil, fmt.Errorf("%w: adjacent area line height requires lines", sketcherror.ErrBadConfig)
+		}
+
+		if a.lineHeight, err = geometry.LengthFromProto(pb.GetLineHeight()); err != nil {
+			return nil, fmt.Errorf("adjacent area line height: %w", err)
+		}
+	}
+
+	if pb.Margin != nil {
+		if a.margin, err = geometry.LengthFromProto(pb.GetMargin()); err != nil {
+			return nil, fmt.Errorf("adjacent area margin: %w", err)
+		}
+	}
+
+	return a, nil
+}
+
+func (a *AdjacentArea) String() string {
+	if a.node == "" {
+		return a.direction.String()
+	}
+
+	return fmt.Sprintf("%s of %q", a.direction.String(), a.node)
+}
+
+// RequiredNodeFeatures returns the node features needed to determine the
+// bounds of the referenced node. Relative areas don't require any.
+func (a *AdjacentArea) RequiredNodeFeatures() []NodeFeature {
+	if a.node == "" {
+		return nil
+	}
+
+	return NodeBoundsFeatures(a.node)
+}
+
+// extent returns the size of the area in the search direction. Zero is
+// returned if the area extends to the page edge.
+func (a *AdjacentArea) extent(ref geometry.Rect) geometry.Length {
+	if a.lines > 0 {
+		lineHeight := a.lineHeight
+
+		if lineHeight <= 0 {
+			lineHeight = ref.Height()
+		}
+
+		return lineHeight.Mul(float64(a.lines))
+	}
+
+	return a.distance
+}
+
+// Resolve calculates the absolute position of the area from the bounds of the
+// referenced node and the page size.
+func (a *AdjacentArea) Resolve(cb PageCallbacks) (geometry.Rect, error) {
+	ref, err := ResolveNodeBounds(cb, a.node)
+	if err != nil {
+		return geometry.Rect{}, err
+	}
+
+	return a.ResolveFrom(ref, cb.Size())
+}
+
+// ResolveFrom calculates the absolute position of the area next to the given
+// reference rectangle.
+func (a *AdjacentArea) ResolveFrom(ref geometry.Rect, size geometry.Size) (geometry.Rect, error) {
+	extent := a.extent(ref)
+	result := ref
+
+	switch a.direction {
+	case geometry.Up:
+		result.Bottom = ref.Top
+		result.Top = 0
+
+		if extent > 0 {
+			result.Top = ref.Top - extent
+		}
+
+	case geometry.Down:
+		result.Top = ref.Bottom
+		result.Bottom = ref.Bottom.Max(size.Height)
+
+		if extent > 0 {
+			result.Bottom = ref.Bottom + extent
+		}
+
+	case geometry.Left:
+		result.Right = ref.Left
+		result.Left = 0
+
+		if extent > 0 {
+			result.Left = ref.Left - extent
+		}
+
+	case geometry.Right:
+		result.Left = ref.Right
+		result.Right = ref.Right.Max(size.Width)
+
+		if extent > 0 {
+			result.Right = ref.Right + extent
+		}
+	}
+
+	if a.direction.Horizontal() {
+		if a.spanPage {
+			result.Top = 0
+			result.Bottom = size.Height
+		}
+
+		result.Top -= a.margin
+		result.Bottom += a.margin
+	} else {
+		if a.spanPage {
+			result.Left = 0
+			result.Right = size.Width
+		}
+
+		result.Left -= a.margin
+		result.Right += a.margin
+	}
+
+	result = result.Normalize()
+
+	if err := result.Validate(); err != nil {
+		return geometry.Rect{}, fmt.Errorf("adjacent area: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package flexrect
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+type fakePageCallbacks struct {
+	fakeCallbacks
+	size geometry.Size
+}
+
+func (c *fakePageCallbacks) Size() geometry.Size {
+	return c.size
+}
+
+func TestAdjacentArea(t *testing.T) {
+	cb := &fakePageCallbacks{
+		fakeCallbacks: fakeCallbacks{
+			features: map[NodeFeature]geometry.Point{
+				NewNodeFeature("label", sketchpb.NodeFeature_TOP_LEFT): {
+					Left: 2 * geometry.Cm,
+					Top:  3 * geometry.Cm,
+				},
+				NewNodeFeature("label", sketchpb.NodeFeature_BOTTOM_RIGHT): {
+					Left: 5 * geometry.Cm,
+					Top:  3.5 * geometry.Cm,
+				},
+			},
+		},
+		size: geometry.Size{
+			Width:  20 * geometry.Cm,
+			Height: 30 * geometry.Cm,
+		},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		input       string
+		wantErr     error
+		wantRefsErr error
+		want        geometry.Rect
+	}{
+		{
+			name:    "empty",
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name:    "missing direction",
+			input:   `node: "label"`,
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name:  "right to page edge",
+			input: `node: "label" direction: RIGHT`,
+			want:  geometry.RectFromCentimeters(5, 3, 20, 3.5),
+		},
+		{
+			name:  "left to page edge",
+			input: `node: "label" direction: LEFT`,
+			want:  geometry.RectFromCentimeters(0, 3, 2, 3.5),
+		},
+		{
+			name:  "up to page edge",
+			input: `node: "label" direction: UP`,
+			want:  geometry.RectFromCentimeters(2, 0, 5, 3),
+		},
+		{
+			name:  "right with distance and margin",
+			input: `node: "label" direction: RIGHT distance { cm: 4 } margin { mm: 1 }`,
+			want:  geometry.RectFromCentimeters(5, 2.9, 9, 3.6),
+		},
+		{
+			name:  "below within lines",
+			input: `node: "label" direction: DOWN lines: 3`,
+			want:  geometry.RectFromCentimeters(2, 3.5, 5, 5),
+		},
+		{
+			name:  "below with line height spanning page",
+			input: `node: "label" direction: DOWN lines: 2 line_height { cm: 1 } span_page: true`,
+			want:  geometry.RectFromCentimeters(0, 3.5, 20, 5.5),
+		},
+		{
+			name:    "lines horizontal",
+			input:   `node: "label" direction: LEFT lines: 2`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "line height without lines",
+			input:   `node: "label" direction: DOWN line_height { cm: 1 }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "negative distance",
+			input:   `node: "label" direction: DOWN distance { cm: -1 }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:        "unknown node",
+			input:       `node: "other" direction: DOWN`,
+			wantRefsErr: errUnknownNode,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := AdjacentAreaFromProto(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.AdjacentArea{}))
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := a.RequiredNodeFeatures(); len(got) != 2 {
+				t.Errorf("RequiredNodeFeatures() returned %d features, want 2", len(got))
+			}
+
+			got, err := a.Resolve(cb)
+
+			if diff := cmp.Diff(tc.wantRefsErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Resolve() error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(tc.want, got, geometry.EquateLength()); diff != "" {
+					t.Errorf("Resolve() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestRelativeArea(t *testing.T) {
+	ref := geometry.RectFromCentimeters(2, 3, 5, 3.5)
+	size := geometry.Size{
+		Width:  20 * geometry.Cm,
+		Height: 30 * geometry.Cm,
+	}
+
+	for _, tc := range []struct {
+		name    string
+		input   string
+		wantErr error
+		want    geometry.Rect
+	}{
+		{
+			name:    "with node",
+			input:   `node: "label" direction: UP`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "missing direction",
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name:  "line above",
+			input: `direction: UP lines: 1`,
+			want:  geometry.RectFromCentimeters(2, 2.5, 5, 3),
+		},
+		{
+			name:  "right to page edge",
+			input: `direction: RIGHT`,
+			want:  geometry.RectFromCentimeters(5, 3, 20, 3.5),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := RelativeAreaFromProto(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.AdjacentArea{}))
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := a.RequiredNodeFeatures(); len(got) != 0 {
+				t.Errorf("RequiredNodeFeatures() returned %d features, want none", len(got))
+			}
+
+			got, err := a.ResolveFrom(ref, size)
+			if err != nil {
+				t.Errorf("ResolveFrom() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, got, geometry.EquateLength()); diff != "" {
+				t.Errorf("ResolveFrom() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -8,3 +8,40 @@ import (
 type Callbacks interface {
 	NodeFeaturePosition(name string, feature sketchpb.NodeFeature) (geometry.Point, error)
 }
+
+// PageCallbacks extend [Callbacks] with information about the page.
+type PageCallbacks interface {
+	Callbacks
+
+	// Size returns the physical page size.
+	Size() geometry.Size
+}
+
+// ResolveNodeBounds returns the bounds of a node using its top left and
+// bottom right features.
+func ResolveNodeBounds(cb Callbacks, name string) (geometry.Rect, error) {
+	topLeft, err := cb.NodeFeaturePosition(name, sketchpb.NodeFeature_TOP_LEFT)
+	if err != nil {
+		return geometry.Rect{}, err
+	}
+
+	bottomRight, err := cb.NodeFeaturePosition(name, sketchpb.NodeFeature_BOTTOM_RIGHT)
+	if err != nil {
+		return geometry.Rect{}, err
+	}
+
+	return geometry.Rect{
+		Left:   topLeft.Left,
+		Top:    topLeft.Top,
+		Right:  bottomRight.Left,
+		Bottom: bottomRight.Top,
+	}, nil
+}
+
+// NodeBoundsFeatures returns the features used by [ResolveNodeBounds].
+func NodeBoundsFeatures(name string) []NodeFeature {
+	return []NodeFeature{
+		NewNodeFeature(name, sketchpb.NodeFeature_TOP_LEFT),
+		NewNodeFeature(name, sketchpb.NodeFeature_BOTTOM_RIGHT),
+	}
+}
@@ -0,0 +1,26 @@
+package flexrect
+
+import (
+	"fmt"
+
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+func DirectionFromProto(d sketchpb.Direction) (geometry.Direction, error) {
+	switch d {
+	case sketchpb.Direction_UP:
+		return geometry.Up, nil
+	case sketchpb.Direction_DOWN:
+		return geometry.Down, nil
+	case sketchpb.Direction_LEFT:
+		return geometry.Left, nil
+	case sketchpb.Direction_RIGHT:
+		return geometry.Right, nil
+	case sketchpb.Direction_DIRECTION_UNSPECIFIED:
+		return 0, fmt.Errorf("%w: direction is required", sketcherror.ErrIncompleteConfig)
+	}
+
+	return 0, fmt.Errorf("%w: unsupported direction %v", sketcherror.ErrBadConfig, d)
+}
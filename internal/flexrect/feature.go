@@ -13,6 +13,14 @@ type NodeFeature struct {
 	feature sketchpb.NodeFeature
 }
 
+// NewNodeFeature returns a reference to a feature on the named node.
+func NewNodeFeature(name string, feature sketchpb.NodeFeature) NodeFeature {
+	return NodeFeature{
+		name:    name,
+		feature: feature,
+	}
+}
+
 func newNodeFeature(pb interface {
 	GetNode() string
 	GetFeature() sketchpb.NodeFeature
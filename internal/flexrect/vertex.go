@@ -94,3 +94,41 @@ func vertexFromProto(pb *sketchpb.FlexRect_Vertex, name string) (genericVertex,
 
 	return nil, fmt.Errorf("%w: vertex %q requires absolute or relative position", sketcherror.ErrIncompleteConfig, name)
 }
+
+// Anchor is a single point given by an absolute or relative position.
+type Anchor struct {
+	vertex genericVertex
+	deps   []NodeFeature
+}
+
+func AnchorFromProto(pb *sketchpb.FlexRect_Vertex, name string) (*Anchor, error) {
+	v, err := vertexFromProto(pb, name)
+	if err != nil {
+		return nil, err
+	}
+
+	discovery := dependencyDiscovery{}
+
+	if _, err := v.Position(&discovery); err != nil {
+		return nil, err
+	}
+
+	return &Anchor{
+		vertex: v,
+		deps:   discovery.get(),
+	}, nil
+}
+
+func (a *Anchor) String() string {
+	return a.vertex.String()
+}
+
+// RequiredNodeFeatures returns all node features referenced by the anchor.
+func (a *Anchor) RequiredNodeFeatures() []NodeFeature {
+	return a.deps
+}
+
+// Resolve calculates the absolute position of the anchor.
+func (a *Anchor) Resolve(cb Callbacks) (geometry.Point, error) {
+	return a.vertex.Position(cb)
+}
@@ -176,3 +176,43 @@ func TestVertexPosition(t *testing.T) {
 		})
 	}
 }
+
+func TestAnchor(t *testing.T) {
+	cb := &fakeCallbacks{
+		features: map[NodeFeature]geometry.Point{
+			NewNodeFeature("title", sketchpb.NodeFeature_TOP_RIGHT): {
+				Left: 10 * geometry.Cm,
+				Top:  2 * geometry.Cm,
+			},
+		},
+	}
+
+	a, err := AnchorFromProto(testutil.MustUnmarshalTextproto(t, `
+rel {
+	node: "title"
+	feature: TOP_RIGHT
+	offset { width { cm: 1 } }
+}
+`, &sketchpb.FlexRect_Vertex{}), "anchor")
+	if err != nil {
+		t.Fatalf("AnchorFromProto() failed: %v", err)
+	}
+
+	if diff := cmp.Diff([]NodeFeature{NewNodeFeature("title", sketchpb.NodeFeature_TOP_RIGHT)}, a.RequiredNodeFeatures(), cmp.AllowUnexported(NodeFeature{})); diff != "" {
+		t.Errorf("RequiredNodeFeatures() diff (-want +got):\n%s", diff)
+	}
+
+	got, err := a.Resolve(cb)
+	if err != nil {
+		t.Errorf("Resolve() failed: %v", err)
+	}
+
+	want := geometry.Point{
+		Left: 11 * geometry.Cm,
+		Top:  2 * geometry.Cm,
+	}
+
+	if diff := cmp.Diff(want, got, geometry.EquateLength()); diff != "" {
+		t.Errorf("Resolve() diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,173 @@
+// Package jsonschema generates JSON Schemas for the JSON mapping of Protocol
+// Buffer messages. Fields are described using their original names as used in
+// the text format.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+type generator struct {
+	defs map[string]any
+}
+
+// Generate returns a schema for a message including all messages and enums it
+// refers to.
+func Generate(md protoreflect.MessageDescriptor) ([]byte, error) {
+	g := &generator{
+		defs: map[string]any{},
+	}
+
+	root := map[string]any{
+		"$schema": draft,
+		"title":   string(md.FullName()),
+		"$ref":    g.message(md)["$ref"],
+		"$defs":   g.defs,
+	}
+
+	buf, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, '\n'), nil
+}
+
+func ref(name protoreflect.FullName) map[string]any {
+	return map[string]any{
+		"$ref": "#/$defs/" + string(name),
+	}
+}
+
+// message adds the definition of a message, if necessary, and returns
+// a reference to it.
+func (g *generator) message(md protoreflect.MessageDescriptor) map[string]any {
+	name := md.FullName()
+
+	if _, ok := g.defs[string(name)]; ok {
+		return ref(name)
+	}
+
+	def := map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+	}
+
+	// Register before visiting fields to support recursive messages.
+	g.defs[string(name)] = def
+
+	properties := map[string]any{}
+
+	for i := range md.Fields().Len() {
+		fd := md.Fields().Get(i)
+
+		properties[fd.TextName()] = g.field(fd)
+	}
+
+	def["properties"] = properties
+
+	var exclusive []any
+
+	for i := range md.Oneofs().Len() {
+		od := md.Oneofs().Get(i)
+
+		if od.IsSynthetic() {
+			continue
+		}
+
+		// Members of a oneof are mutually exclusive.
+		for a := range od.Fields().Len() {
+			for b := a + 1; b < od.Fields().Len(); b++ {
+				exclusive = append(exclusive, map[string]any{
+					"required": []string{
+						od.Fields().Get(a).TextName(),
+						od.Fields().Get(b).TextName(),
+					},
+				})
+			}
+		}
+	}
+
+	if len(exclusive) > 0 {
+		def["not"] = map[string]any{
+			"anyOf": exclusive,
+		}
+	}
+
+	return ref(name)
+}
+
+func (g *generator) enum(ed protoreflect.EnumDescriptor) map[string]any {
+	name := ed.FullName()
+
+	if _, ok := g.defs[string(name)]; !ok {
+		var names []string
+
+		for i := range ed.Values().Len() {
+			names = append(names, string(ed.Values().Get(i).Name()))
+		}
+
+		g.defs[string(name)] = map[string]any{
+			"enum": names,
+		}
+	}
+
+	return ref(name)
+}
+
+func (g *generator) field(fd protoreflect.FieldDescriptor) map[string]any {
+	if fd.IsMap() {
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": g.singular(fd.MapValue()),
+		}
+	}
+
+	if fd.IsList() {
+		return map[string]any{
+			"type":  "array",
+			"items": g.singular(fd),
+		}
+	}
+
+	return g.singular(fd)
+}
+
+// singular describes a single value of a field.
+func (g *generator) singular(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.message(fd.Message())
+
+	case protoreflect.EnumKind:
+		return g.enum(fd.Enum())
+
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+
+	case protoreflect.StringKind:
+		return map[string]any{"type": "string"}
+
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]any{"type": "number"}
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer"}
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64-bit integers are encoded as strings.
+		return map[string]any{"type": []string{"integer", "string"}}
+	}
+
+	panic(fmt.Sprintf("unsupported field kind %v", fd.Kind()))
+}
@@ -0,0 +1,92 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/proto/geometrypb"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+func TestGenerate(t *testing.T) {
+	buf, err := Generate((*geometrypb.Point)(nil).ProtoReflect().Descriptor())
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+
+	var got any
+
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	number := map[string]any{"type": "number"}
+	length := map[string]any{"$ref": "#/$defs/dossier.geometry.Length"}
+
+	exclusive := func(a, b string) any {
+		return map[string]any{"required": []any{a, b}}
+	}
+
+	want := map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "dossier.geometry.Point",
+		"$ref":    "#/$defs/dossier.geometry.Point",
+		"$defs": map[string]any{
+			"dossier.geometry.Point": map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"left": length,
+					"top":  length,
+				},
+			},
+			"dossier.geometry.Length": map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"pt":    number,
+					"mm":    number,
+					"cm":    number,
+					"in":    number,
+					"param": map[string]any{"type": "string"},
+				},
+				"not": map[string]any{
+					"anyOf": []any{
+						exclusive("pt", "mm"),
+						exclusive("pt", "cm"),
+						exclusive("pt", "in"),
+						exclusive("pt", "param"),
+						exclusive("mm", "cm"),
+						exclusive("mm", "in"),
+						exclusive("mm", "param"),
+						exclusive("cm", "in"),
+						exclusive("cm", "param"),
+						exclusive("in", "param"),
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Generate() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSketchSchemaUpToDate(t *testing.T) {
+	want, err := Generate((*sketchpb.Sketch)(nil).ProtoReflect().Descriptor())
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+
+	got, err := os.ReadFile("../../proto/sketch.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("Schema is outdated, run \"go generate ./proto\" (-want +got):\n%s", diff)
+	}
+}
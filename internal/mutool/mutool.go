@@ -134,6 +134,11 @@ func (w *Wrapper) Draw(ctx context.Context, path string, pageNum int, r renderfo
 		a.width = r.Width
 		a.height = r.Height
 		a.stdout = r.Output
+	case *renderformat.SVG:
+		a.format = "svg"
+		a.width = r.Width
+		a.height = r.Height
+		a.stdout = r.Output
 	default:
 		return fmt.Errorf("%w: render format %q is not supported", os.ErrInvalid, r.String())
 	}
@@ -238,6 +238,7 @@ func TestWrapperDraw(t *testing.T) {
 		name       string
 		pageNum    int
 		renderer   renderformat.Renderer
+		newOutput  func(io.Writer) renderformat.Renderer
 		draw       func(*testing.T, drawArgs) error
 		wantErr    error
 		wantOutput string
@@ -253,6 +254,24 @@ func TestWrapperDraw(t *testing.T) {
 			},
 			wantOutput: "test output",
 		},
+		{
+			name: "svg",
+			newOutput: func(w io.Writer) renderformat.Renderer {
+				return &renderformat.SVG{Output: w}
+			},
+			draw: func(t *testing.T, a drawArgs) error {
+				if diff := cmp.Diff("svg", a.format); diff != "" {
+					t.Errorf("Format diff (-want +got):\n%s", diff)
+				}
+
+				if _, err := io.WriteString(a.stdout, "<svg/>"); err != nil {
+					t.Errorf("WriteString() failed: %v", err)
+				}
+
+				return nil
+			},
+			wantOutput: "<svg/>",
+		},
 		{
 			name: "error",
 			draw: func(*testing.T, drawArgs) error {
@@ -281,8 +300,12 @@ func TestWrapperDraw(t *testing.T) {
 			var out bytes.Buffer
 
 			if tc.renderer == nil {
-				tc.renderer = &renderformat.PNG{
-					Output: &out,
+				if tc.newOutput != nil {
+					tc.renderer = tc.newOutput(&out)
+				} else {
+					tc.renderer = &renderformat.PNG{
+						Output: &out,
+					}
 				}
 			}
 
@@ -0,0 +1,468 @@
+package textproto
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Messages are written on a single line if they contain no comments and the
+// line doesn't exceed this width.
+const formatMaxLineWidth = 80
+
+const formatIndent = "  "
+
+// treeEntry is a value within a syntax tree together with its comments.
+type treeEntry struct {
+	value       *Value
+	blankBefore bool
+	leading     []*Comment
+	trailing    *Comment
+	used        bool
+}
+
+// treeIndex maps the values of a message syntax tree to their path elements.
+type treeIndex struct {
+	entries map[PathElem]*treeEntry
+	order   []*treeEntry
+	end     []*Comment
+}
+
+func newTreeIndex(m *Message) *treeIndex {
+	idx := &treeIndex{
+		entries: map[PathElem]*treeEntry{},
+	}
+
+	if m == nil {
+		return idx
+	}
+
+	counts := map[string]int{}
+
+	add := func(name string, e *treeEntry) {
+		idx.entries[PathElem{Name: name, Index: counts[name]}] = e
+		idx.order = append(idx.order, e)
+		counts[name]++
+	}
+
+	for _, f := range m.Fields {
+		if f.Value == nil {
+			continue
+		}
+
+		if f.Value.IsList && len(f.Value.List) == 0 {
+			// Retain the comments of empty lists.
+			idx.order = append(idx.order, &treeEntry{
+				value:       f.Value,
+				blankBefore: f.BlankBefore,
+				leading:     f.Leading,
+				trailing:    f.Trailing,
+			})
+			continue
+		}
+
+		if !f.Value.IsList {
+			add(f.Name, &treeEntry{
+				value:       f.Value,
+				blankBefore: f.BlankBefore,
+				leading:     f.Leading,
+				trailing:    f.Trailing,
+			})
+			continue
+		}
+
+		for itemIdx, item := range f.Value.List {
+			e := &treeEntry{
+				value:   item,
+				leading: item.Leading,
+			}
+
+			if itemIdx == 0 {
+				e.blankBefore = f.BlankBefore
+				e.leading = slices.Concat(f.Leading, item.Leading)
+			}
+
+			if itemIdx == len(f.Value.List)-1 {
+				e.trailing = f.Trailing
+			}
+
+			add(f.Name, e)
+		}
+	}
+
+	idx.end = m.EndComments
+
+	return idx
+}
+
+// lookup finds the unused tree entry for a field value. Members of a oneof
+// also match values of other members, e.g. a length using a different unit.
+func (t *treeIndex) lookup(fd protoreflect.FieldDescriptor, index int) *treeEntry {
+	names := []string{fd.TextName()}
+
+	if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+		for i := range od.Fields().Len() {
+			if other := od.Fields().Get(i); other != fd {
+				names = append(names, other.TextName())
+			}
+		}
+	}
+
+	for _, name := range names {
+		if e := t.entries[PathElem{Name: name, Index: index}]; e != nil && !e.used {
+			e.used = true
+			return e
+		}
+	}
+
+	return nil
+}
+
+// unused returns the comments of all entries without a corresponding value
+// followed by the comments at the end of the message.
+func (t *treeIndex) unused() []*Comment {
+	var result []*Comment
+
+	for _, e := range t.order {
+		if e.used {
+			continue
+		}
+
+		comments := slices.Clone(e.leading)
+
+		if e.trailing != nil {
+			comments = append(comments, e.trailing)
+		}
+
+		if e.value.Message != nil {
+			comments = append(comments, allComments(e.value.Message)...)
+		}
+
+		if len(comments) > 0 && e.blankBefore && !comments[0].BlankBefore {
+			c := *comments[0]
+			c.BlankBefore = true
+			comments[0] = &c
+		}
+
+		result = append(result, comments...)
+	}
+
+	return append(result, t.end...)
+}
+
+// allComments returns all comments within a message in source order.
+func allComments(m *Message) []*Comment {
+	var result []*Comment
+
+	var visitValue func(*Value)
+
+	visitValue = func(v *Value) {
+		result = append(result, v.Leading...)
+
+		if v.Message != nil {
+			result = append(result, allComments(v.Message)...)
+		}
+
+		for _, item := range v.List {
+			visitValue(item)
+		}
+	}
+
+	for _, f := range m.Fields {
+		result = append(result, f.Leading...)
+
+		if f.Value != nil {
+			visitValue(f.Value)
+		}
+
+		if f.Trailing != nil {
+			result = append(result, f.Trailing)
+		}
+	}
+
+	return append(result, m.EndComments...)
+}
+
+type printer struct {
+	buf bytes.Buffer
+
+	// Fields written first, keyed by message name.
+	order map[protoreflect.FullName][]protoreflect.Name
+}
+
+// FormatOption customizes the behaviour of [Format].
+type FormatOption func(*printer)
+
+// WithFieldOrder writes the named fields of a message type before all other
+// fields and in the given order.
+func WithFieldOrder(message protoreflect.FullName, fields ...protoreflect.Name) FormatOption {
+	return func(p *printer) {
+		if p.order == nil {
+			p.order = map[protoreflect.FullName][]protoreflect.Name{}
+		}
+
+		p.order[message] = fields
+	}
+}
+
+// Format writes a message in the text format. Fields are written in their
+// order of declaration unless configured otherwise using [WithFieldOrder].
+// Messages use curly braces and no colon. Repeated fields are written as one
+// field per value.
+//
+// Comments are taken from the syntax tree of the original source, if any, by
+// matching field names and indices. Comments of fields no longer present are
+// moved to the end of the enclosing message.
+func Format(m protoreflect.Message, tree *Message, opts ...FormatOption) []byte {
+	var p printer
+
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	if tree != nil && len(tree.Header) > 0 {
+		p.comments(tree.Header, 0, true)
+		p.buf.WriteByte('\n')
+	}
+
+	p.fields(m, newTreeIndex(tree), 0)
+
+	return p.buf.Bytes()
+}
+
+func (p *printer) writeIndent(depth int) {
+	for range depth {
+		p.buf.WriteString(formatIndent)
+	}
+}
+
+func (p *printer) comments(comments []*Comment, depth int, first bool) {
+	for idx, c := range comments {
+		if c.BlankBefore && !(first && idx == 0) {
+			p.buf.WriteByte('\n')
+		}
+
+		p.writeIndent(depth)
+		p.buf.WriteString(c.Text)
+		p.buf.WriteByte('\n')
+	}
+}
+
+// fields writes all populated fields of a message, one per line.
+func (p *printer) fields(m protoreflect.Message, tree *treeIndex, depth int) {
+	first := true
+
+	p.forEachValue(m, func(fd protoreflect.FieldDescriptor, index int, v protoreflect.Value) {
+		e := tree.lookup(fd, index)
+
+		if e == nil {
+			e = &treeEntry{}
+		}
+
+		if e.blankBefore && !first {
+			p.buf.WriteByte('\n')
+		}
+
+		p.comments(e.leading, depth, first)
+
+		p.writeIndent(depth)
+		p.field(fd, v, e, depth)
+
+		if e.trailing != nil {
+			p.buf.WriteString(" ")
+			p.buf.WriteString(e.trailing.Text)
+		}
+
+		p.buf.WriteByte('\n')
+
+		first = false
+	})
+
+	p.comments(tree.unused(), depth, first)
+}
+
+// field writes a single field value without a trailing newline.
+func (p *printer) field(fd protoreflect.FieldDescriptor, v protoreflect.Value, e *treeEntry, depth int) {
+	p.buf.WriteString(fd.TextName())
+
+	if fd.Message() == nil {
+		p.buf.WriteString(": ")
+		p.buf.WriteString(formatScalar(fd, v))
+		return
+	}
+
+	var subtree *Message
+
+	if e.value != nil {
+		subtree = e.value.Message
+	}
+
+	if subtree == nil || len(allComments(subtree)) == 0 {
+		if inline, ok := p.formatInline(v.Message()); ok &&
+			len(formatIndent)*depth+len(fd.TextName())+1+len(inline) <= formatMaxLineWidth {
+			p.buf.WriteString(" ")
+			p.buf.WriteString(inline)
+			return
+		}
+	}
+
+	p.buf.WriteString(" {\n")
+	p.fields(v.Message(), newTreeIndex(subtree), depth+1)
+	p.writeIndent(depth)
+	p.buf.WriteString("}")
+}
+
+// formatInline writes a message on a single line. Returns false if the result
+// is too long.
+func (p *printer) formatInline(m protoreflect.Message) (string, bool) {
+	var parts []string
+	var width int
+
+	ok := true
+
+	p.forEachValue(m, func(fd protoreflect.FieldDescriptor, _ int, v protoreflect.Value) {
+		if !ok {
+			return
+		}
+
+		var part string
+
+		if fd.Message() == nil {
+			part = fd.TextName() + ": " + formatScalar(fd, v)
+		} else if inline, inlineOK := p.formatInline(v.Message()); inlineOK {
+			part = fd.TextName() + " " + inline
+		} else {
+			ok = false
+			return
+		}
+
+		width += len(part) + 1
+		ok = width <= formatMaxLineWidth
+		parts = append(parts, part)
+	})
+
+	if !ok {
+		return "", false
+	}
+
+	if len(parts) == 0 {
+		return "{}", true
+	}
+
+	return "{ " + strings.Join(parts, " ") + " }", true
+}
+
+// orderedFields returns the fields of a message in the configured order
+// followed by the remaining fields in their order of declaration.
+func (p *printer) orderedFields(md protoreflect.MessageDescriptor) []protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	result := make([]protoreflect.FieldDescriptor, 0, fields.Len())
+
+	for _, name := range p.order[md.FullName()] {
+		if fd := fields.ByName(name); fd != nil {
+			result = append(result, fd)
+		}
+	}
+
+	for i := range fields.Len() {
+		if fd := fields.Get(i); !slices.Contains(result, fd) {
+			result = append(result, fd)
+		}
+	}
+
+	return result
+}
+
+// forEachValue calls the function for all populated fields in the configured
+// order. Repeated fields produce one call per value and map entries are
+// visited in key order.
+func (p *printer) forEachValue(m protoreflect.Message, fn func(protoreflect.FieldDescriptor, int, protoreflect.Value)) {
+	for _, fd := range p.orderedFields(m.Descriptor()) {
+		if !m.Has(fd) {
+			continue
+		}
+
+		v := m.Get(fd)
+
+		switch {
+		case fd.IsList():
+			for idx := range v.List().Len() {
+				fn(fd, idx, v.List().Get(idx))
+			}
+
+		case fd.IsMap():
+			forEachMapEntry(fd, v.Map(), func(idx int, entry protoreflect.Value) {
+				fn(fd, idx, entry)
+			})
+
+		default:
+			fn(fd, 0, v)
+		}
+	}
+}
+
+// forEachMapEntry converts map entries to entry messages ordered by key.
+func forEachMapEntry(fd protoreflect.FieldDescriptor, m protoreflect.Map, fn func(int, protoreflect.Value)) {
+	var keys []protoreflect.MapKey
+
+	m.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+
+	slices.SortFunc(keys, func(a, b protoreflect.MapKey) int {
+		return strings.Compare(formatScalar(fd.MapKey(), a.Value()), formatScalar(fd.MapKey(), b.Value()))
+	})
+
+	for idx, k := range keys {
+		entry := dynamicpb.NewMessage(fd.Message())
+		entry.Set(fd.MapKey(), k.Value())
+		entry.Set(fd.MapValue(), m.Get(k))
+
+		fn(idx, protoreflect.ValueOfMessage(entry))
+	}
+}
+
+// formatScalar returns the text representation of a non-message value.
+func formatScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return strconv.Quote(v.String())
+
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(v.Bytes()))
+
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+
+		return strconv.Itoa(int(v.Enum()))
+
+	case protoreflect.FloatKind:
+		return formatFloat(v.Float(), 32)
+
+	case protoreflect.DoubleKind:
+		return formatFloat(v.Float(), 64)
+	}
+
+	return v.String()
+}
+
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
@@ -0,0 +1,151 @@
+package textproto
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+func TestFormat(t *testing.T) {
+	sketchOrder := WithFieldOrder("dossier.sketch.Sketch", "imports", "tags", "nodes")
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		opts  []FormatOption
+		want  string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "canonical",
+			input: `tags: ["b", "a"]
+nodes: {
+	line_text: <regex: 'x' 'y'>
+  name: "first"
+}
+imports { path: "common.textproto" namespace: "common" }
+`,
+			want: `nodes { name: "first" line_text { regex: "xy" } }
+imports { path: "common.textproto" namespace: "common" }
+tags: "b"
+tags: "a"
+`,
+		},
+		{
+			name: "field order",
+			input: `tags: "a"
+nodes { name: "first" }
+imports { path: "common.textproto" }
+normalization {}
+`,
+			opts: []FormatOption{sketchOrder},
+			want: `imports { path: "common.textproto" }
+tags: "a"
+nodes { name: "first" }
+normalization {}
+`,
+		},
+		{
+			name: "comments",
+			input: `# Header
+
+# Description
+nodes {
+  # Name
+  name: "a"  # trailing
+
+  search_areas {
+    width { cm: 1 }  # width
+  }
+  # end of node
+}
+
+# Removed field
+tags: []
+# end
+`,
+			want: `# Header
+
+# Description
+nodes {
+  # Name
+  name: "a" # trailing
+
+  search_areas {
+    width { cm: 1 } # width
+  }
+  # end of node
+}
+
+# Removed field
+# end
+`,
+		},
+		{
+			name: "header before field comment",
+			input: `# Copyright
+# License
+
+# More header
+
+# Description
+nodes { name: "a" }
+tags: "x"
+`,
+			opts: []FormatOption{sketchOrder},
+			want: `# Copyright
+# License
+
+# More header
+
+tags: "x"
+# Description
+nodes { name: "a" }
+`,
+		},
+		{
+			name: "long",
+			input: `nodes {
+  name: "a"
+  search_areas { top_left { abs { left { cm: 1 } top { cm: 2 } } } width { cm: 3 } height { cm: 4 } }
+}
+`,
+			want: `nodes {
+  name: "a"
+  search_areas {
+    top_left { abs { left { cm: 1 } top { cm: 2 } } }
+    width { cm: 3 }
+    height { cm: 4 }
+  }
+}
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var pb sketchpb.Sketch
+
+			if err := prototext.Unmarshal([]byte(tc.input), &pb); err != nil {
+				t.Fatalf("Unmarshal() failed: %v", err)
+			}
+
+			tree, err := Parse([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+
+			got := string(Format(pb.ProtoReflect(), tree, tc.opts...))
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Format() diff (-want +got):\n%s", diff)
+			}
+
+			if again := string(Format(pb.ProtoReflect(), tree, tc.opts...)); again != got {
+				t.Errorf("Format() is not deterministic:\n%s", again)
+			}
+		})
+	}
+}
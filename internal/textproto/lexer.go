@@ -0,0 +1,195 @@
+package textproto
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenComment
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+
+	// Source text of the token.
+	text string
+
+	pos Position
+
+	// Number of line breaks between the end of the previous token and the
+	// start of this token.
+	newlines int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return "string " + t.text
+	}
+
+	return strconv.Quote(t.text)
+}
+
+type lexer struct {
+	src []byte
+	pos Position
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// isNumberPart reports whether the character may be part of a number. Signs
+// are handled separately.
+func isNumberPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.'
+}
+
+func (l *lexer) peekByte(offset int) byte {
+	if pos := l.pos.Offset + offset; pos < len(l.src) {
+		return l.src[pos]
+	}
+
+	return 0
+}
+
+// advance moves forward by the given number of bytes.
+func (l *lexer) advance(n int) {
+	for ; n > 0 && l.pos.Offset < len(l.src); n-- {
+		if l.src[l.pos.Offset] == '\n' {
+			l.pos.Line++
+			l.pos.Column = 1
+		} else {
+			l.pos.Column++
+		}
+
+		l.pos.Offset++
+	}
+}
+
+func (l *lexer) errorf(pos Position, format string, args ...any) error {
+	return &Error{
+		Pos: pos,
+		Msg: fmt.Sprintf(format, args...),
+	}
+}
+
+// scan reads the next token including comments.
+func (l *lexer) scan() (token, error) {
+	var newlines int
+
+	for l.pos.Offset < len(l.src) {
+		switch l.src[l.pos.Offset] {
+		case '\n':
+			newlines++
+			fallthrough
+
+		case ' ', '\t', '\r', '\v', '\f':
+			l.advance(1)
+			continue
+		}
+
+		break
+	}
+
+	t := token{
+		pos:      l.pos,
+		newlines: newlines,
+	}
+
+	if l.pos.Offset >= len(l.src) {
+		t.kind = tokenEOF
+		return t, nil
+	}
+
+	start := l.pos.Offset
+	c := l.src[start]
+
+	switch {
+	case c == '#':
+		t.kind = tokenComment
+
+		for l.pos.Offset < len(l.src) && l.src[l.pos.Offset] != '\n' {
+			l.advance(1)
+		}
+
+	case isIdentStart(c):
+		t.kind = tokenIdent
+
+		for l.pos.Offset < len(l.src) && (isIdentStart(l.src[l.pos.Offset]) || isDigit(l.src[l.pos.Offset])) {
+			l.advance(1)
+		}
+
+	case isDigit(c) || (c == '.' && isDigit(l.peekByte(1))):
+		t.kind = tokenNumber
+
+		hex := c == '0' && (l.peekByte(1) == 'x' || l.peekByte(1) == 'X')
+
+		for l.pos.Offset < len(l.src) {
+			ch := l.src[l.pos.Offset]
+
+			if ch == '+' || ch == '-' {
+				// Signs are only part of a decimal exponent
+				if prev := l.src[l.pos.Offset-1]; hex || (prev != 'e' && prev != 'E') {
+					break
+				}
+			} else if !isNumberPart(ch) {
+				break
+			}
+
+			l.advance(1)
+		}
+
+	case c == '"' || c == '\'':
+		t.kind = tokenString
+
+		l.advance(1)
+
+		for {
+			if l.pos.Offset >= len(l.src) || l.src[l.pos.Offset] == '\n' {
+				return token{}, l.errorf(t.pos, "unterminated string")
+			}
+
+			ch := l.src[l.pos.Offset]
+
+			if ch == '\\' {
+				l.advance(2)
+				continue
+			}
+
+			l.advance(1)
+
+			if ch == c {
+				break
+			}
+		}
+
+	default:
+		switch c {
+		case '{', '}', '<', '>', '[', ']', ':', ',', ';', '-', '.', '/':
+			t.kind = tokenPunct
+			l.advance(1)
+
+		default:
+			return token{}, l.errorf(t.pos, "unexpected character %q", c)
+		}
+	}
+
+	t.text = string(l.src[start:l.pos.Offset])
+
+	return t, nil
+}
@@ -0,0 +1,325 @@
+package textproto
+
+type parser struct {
+	lex *lexer
+
+	// Current token. Comments are never stored here.
+	tok token
+
+	// Comments seen before the current token.
+	comments []token
+}
+
+// next advances to the next non-comment token.
+func (p *parser) next() error {
+	p.comments = nil
+
+	for {
+		t, err := p.lex.scan()
+		if err != nil {
+			return err
+		}
+
+		if t.kind != tokenComment {
+			p.tok = t
+			return nil
+		}
+
+		p.comments = append(p.comments, t)
+	}
+}
+
+func (p *parser) unexpected() error {
+	return p.lex.errorf(p.tok.pos, "unexpected %s", p.tok)
+}
+
+func (p *parser) isPunct(text string) bool {
+	return p.tok.kind == tokenPunct && p.tok.text == text
+}
+
+func newComment(t token) *Comment {
+	return &Comment{
+		Pos:         t.pos,
+		Text:        t.text,
+		BlankBefore: t.newlines > 1,
+	}
+}
+
+// takeComments converts the pending comments. Comments on the line of the
+// previous token are returned separately.
+func (p *parser) takeComments() (trailing *Comment, leading []*Comment) {
+	for idx, t := range p.comments {
+		c := newComment(t)
+
+		if idx == 0 && t.newlines == 0 {
+			trailing = c
+		} else {
+			leading = append(leading, c)
+		}
+	}
+
+	p.comments = nil
+
+	return trailing, leading
+}
+
+// Parse parses a message in the text format.
+func Parse(src []byte) (*Message, error) {
+	p := &parser{
+		lex: &lexer{
+			src: src,
+			pos: Position{Line: 1, Column: 1},
+		},
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var header []*Comment
+
+	if p.tok.kind != tokenEOF {
+		// Comments up to the last blank line before the first field form the
+		// header, e.g. a license notice.
+		split := 0
+
+		if p.tok.newlines > 1 {
+			split = len(p.comments)
+		} else {
+			for idx := len(p.comments) - 1; idx > 0; idx-- {
+				if p.comments[idx].newlines > 1 {
+					split = idx
+					break
+				}
+			}
+		}
+
+		for _, t := range p.comments[:split] {
+			header = append(header, newComment(t))
+		}
+
+		p.comments = p.comments[split:]
+
+		if split > 0 && len(p.comments) > 0 {
+			// The blank line separates the header.
+			p.comments[0].newlines = 1
+		}
+	}
+
+	root, err := p.parseFields(Position{Line: 1, Column: 1}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	root.Header = header
+
+	if p.tok.kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+
+	root.End = p.tok.pos
+
+	return root, nil
+}
+
+// parseFields parses fields until the closing delimiter, which is not
+// consumed. An empty delimiter parses until the end of the input.
+func (p *parser) parseFields(pos Position, closing string) (*Message, error) {
+	m := &Message{
+		Pos: pos,
+	}
+
+	var last *Field
+
+	for {
+		trailing, leading := p.takeComments()
+
+		if trailing != nil {
+			if last != nil && last.Trailing == nil {
+				last.Trailing = trailing
+			} else {
+				leading = append([]*Comment{trailing}, leading...)
+			}
+		}
+
+		if (closing == "" && p.tok.kind == tokenEOF) || (closing != "" && p.isPunct(closing)) {
+			m.EndComments = leading
+			return m, nil
+		}
+
+		blank := p.tok.newlines > 1
+		if len(leading) > 0 {
+			blank = leading[0].BlankBefore
+			leading[0].BlankBefore = false
+		}
+
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+
+		f.BlankBefore = blank
+		f.Leading = leading
+
+		m.Fields = append(m.Fields, f)
+		last = f
+	}
+}
+
+func (p *parser) parseFieldName() (string, error) {
+	if p.tok.kind == tokenIdent {
+		name := p.tok.text
+		return name, p.next()
+	}
+
+	if !p.isPunct("[") {
+		return "", p.unexpected()
+	}
+
+	name := "["
+
+	for {
+		if err := p.next(); err != nil {
+			return "", err
+		}
+
+		switch {
+		case p.tok.kind == tokenIdent, p.isPunct("."), p.isPunct("/"):
+			name += p.tok.text
+
+		case p.isPunct("]"):
+			return name + "]", p.next()
+
+		default:
+			return "", p.unexpected()
+		}
+	}
+}
+
+func (p *parser) parseField() (*Field, error) {
+	var err error
+
+	f := &Field{
+		Pos: p.tok.pos,
+	}
+
+	if f.Name, err = p.parseFieldName(); err != nil {
+		return nil, err
+	}
+
+	if p.isPunct(":") {
+		f.Colon = true
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.Value, err = p.parseValue(); err != nil {
+		return nil, err
+	}
+
+	if p.isPunct(",") || p.isPunct(";") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (p *parser) parseValue() (*Value, error) {
+	v := &Value{
+		Pos: p.tok.pos,
+	}
+
+	switch {
+	case p.isPunct("{"), p.isPunct("<"):
+		closing := "}"
+		if p.tok.text == "<" {
+			closing = ">"
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		m, err := p.parseFields(v.Pos, closing)
+		if err != nil {
+			return nil, err
+		}
+
+		m.Angle = closing == ">"
+		m.End = p.tok.pos
+		v.Message = m
+
+		return v, p.next()
+
+	case p.isPunct("["):
+		return v, p.parseList(v)
+
+	case p.tok.kind == tokenString:
+		for p.tok.kind == tokenString {
+			v.Scalar = append(v.Scalar, p.tok.text)
+
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+
+		return v, nil
+
+	case p.isPunct("-"):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		if p.tok.kind != tokenNumber && p.tok.kind != tokenIdent {
+			return nil, p.unexpected()
+		}
+
+		v.Scalar = []string{"-" + p.tok.text}
+
+		return v, p.next()
+
+	case p.tok.kind == tokenNumber, p.tok.kind == tokenIdent:
+		v.Scalar = []string{p.tok.text}
+
+		return v, p.next()
+	}
+
+	return nil, p.unexpected()
+}
+
+func (p *parser) parseList(v *Value) error {
+	v.IsList = true
+
+	if err := p.next(); err != nil {
+		return err
+	}
+
+	for !p.isPunct("]") {
+		if len(v.List) > 0 {
+			if !p.isPunct(",") {
+				return p.unexpected()
+			}
+
+			if err := p.next(); err != nil {
+				return err
+			}
+		}
+
+		_, leading := p.takeComments()
+
+		item, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+
+		item.Leading = leading
+
+		v.List = append(v.List, item)
+	}
+
+	return p.next()
+}
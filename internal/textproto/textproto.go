@@ -0,0 +1,206 @@
+// Package textproto parses the Protocol Buffer text format into a syntax tree
+// retaining source positions and comments. Decoding into messages is left to
+// [google.golang.org/protobuf/encoding/prototext]; the tree is used to
+// attribute diagnostics to their location and to reformat files.
+package textproto
+
+import (
+	"fmt"
+)
+
+// Position is a location within the source text.
+type Position struct {
+	// Byte offset, starting at 0.
+	Offset int
+
+	// Line number, starting at 1.
+	Line int
+
+	// Column number in bytes, starting at 1.
+	Column int
+}
+
+// IsValid reports whether the position is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Error is a syntax error.
+type Error struct {
+	Pos Position
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// Comment is a single line comment including the leading "#".
+type Comment struct {
+	Pos  Position
+	Text string
+
+	// Whether the comment is preceded by an empty line.
+	BlankBefore bool
+}
+
+// Field is a field name with its value.
+type Field struct {
+	Pos Position
+
+	// Field name. Extension and type URL names retain their square brackets,
+	// e.g. "[foo.bar]".
+	Name string
+
+	// Whether the name is followed by a colon.
+	Colon bool
+
+	Value *Value
+
+	// Whether the field, or its leading comments, are preceded by an empty
+	// line.
+	BlankBefore bool
+
+	// Comments on the lines before the field.
+	Leading []*Comment
+
+	// Comment on the same line after the field.
+	Trailing *Comment
+}
+
+// Value is a scalar, message or list value.
+type Value struct {
+	Pos Position
+
+	// Source text of scalar values. String values may consist of multiple
+	// adjacent literals which are concatenated.
+	Scalar []string
+
+	// Message value. Nil for other kinds.
+	Message *Message
+
+	// Whether the value uses the list syntax ("[a, b]").
+	IsList bool
+	List   []*Value
+
+	// Comments on the lines before a list item.
+	Leading []*Comment
+}
+
+// Message is a sequence of fields. For the top-level message the positions
+// refer to the start and end of the input.
+type Message struct {
+	Pos Position
+	End Position
+
+	// Whether angle brackets are used as delimiters instead of curly braces.
+	Angle bool
+
+	Fields []*Field
+
+	// Comments at the start of the input separated from the first field by
+	// an empty line, e.g. a file description. Only used for the top-level
+	// message.
+	Header []*Comment
+
+	// Comments after the last field.
+	EndComments []*Comment
+}
+
+// PathElem refers to a field by name and index. The index counts all values
+// of the field in the order of appearance, including list items. Singular
+// fields use index 0.
+type PathElem struct {
+	Name  string
+	Index int
+}
+
+// Path refers to a value nested within messages.
+type Path []PathElem
+
+func (p Path) String() string {
+	var buf []byte
+
+	for idx, elem := range p {
+		if idx > 0 {
+			buf = append(buf, '.')
+		}
+
+		buf = fmt.Appendf(buf, "%s[%d]", elem.Name, elem.Index)
+	}
+
+	return string(buf)
+}
+
+// Append returns a copy of the path with an additional element.
+func (p Path) Append(name string, index int) Path {
+	result := make(Path, len(p), len(p)+1)
+	copy(result, p)
+
+	return append(result, PathElem{Name: name, Index: index})
+}
+
+// lookup finds the value of a field by name and index. The returned position
+// is that of the field name for non-list values and of the item for lists.
+func (m *Message) lookup(elem PathElem) (*Value, Position, bool) {
+	count := 0
+
+	for _, f := range m.Fields {
+		if f.Name != elem.Name || f.Value == nil {
+			continue
+		}
+
+		if !f.Value.IsList {
+			if count == elem.Index {
+				return f.Value, f.Pos, true
+			}
+
+			count++
+			continue
+		}
+
+		for _, item := range f.Value.List {
+			if count == elem.Index {
+				return item, item.Pos, true
+			}
+
+			count++
+		}
+	}
+
+	return nil, Position{}, false
+}
+
+// Locate returns the position of the value at the given path. If the path
+// can't be resolved completely the position of the innermost value found is
+// returned together with false.
+func (m *Message) Locate(path Path) (Position, bool) {
+	pos := m.Pos
+
+	for idx, elem := range path {
+		value, valuePos, ok := m.lookup(elem)
+		if !ok {
+			return pos, false
+		}
+
+		pos = valuePos
+
+		if idx == len(path)-1 {
+			break
+		}
+
+		if m = value.Message; m == nil {
+			return pos, false
+		}
+	}
+
+	return pos, true
+}
@@ -0,0 +1,191 @@
+package textproto
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		want    *Message
+		wantErr *Error
+	}{
+		{
+			name: "empty",
+			want: &Message{},
+		},
+		{
+			name: "fields",
+			input: `# leading
+name: "a" 'b'  # trailing
+
+nodes {
+  value: -1.5e+3
+}
+list: [1, 0x1F, inf];
+ext < [foo.bar/baz]: true >
+# end
+`,
+			want: &Message{
+				Fields: []*Field{
+					{
+						Name:     "name",
+						Colon:    true,
+						Value:    &Value{Scalar: []string{`"a"`, `'b'`}},
+						Leading:  []*Comment{{Text: "# leading"}},
+						Trailing: &Comment{Text: "# trailing"},
+					},
+					{
+						Name:        "nodes",
+						BlankBefore: true,
+						Value: &Value{
+							Message: &Message{
+								Fields: []*Field{
+									{Name: "value", Colon: true, Value: &Value{Scalar: []string{"-1.5e+3"}}},
+								},
+							},
+						},
+					},
+					{
+						Name:  "list",
+						Colon: true,
+						Value: &Value{
+							IsList: true,
+							List: []*Value{
+								{Scalar: []string{"1"}},
+								{Scalar: []string{"0x1F"}},
+								{Scalar: []string{"inf"}},
+							},
+						},
+					},
+					{
+						Name: "ext",
+						Value: &Value{
+							Message: &Message{
+								Angle: true,
+								Fields: []*Field{
+									{Name: "[foo.bar/baz]", Colon: true, Value: &Value{Scalar: []string{"true"}}},
+								},
+							},
+						},
+					},
+				},
+				EndComments: []*Comment{{Text: "# end"}},
+			},
+		},
+		{
+			name:    "unterminated string",
+			input:   "a: 1\nb: \"x\n",
+			wantErr: &Error{Pos: Position{Offset: 8, Line: 2, Column: 4}, Msg: "unterminated string"},
+		},
+		{
+			name:    "missing value",
+			input:   "a {\n  b:\n}",
+			wantErr: &Error{Pos: Position{Offset: 9, Line: 3, Column: 1}, Msg: `unexpected "}"`},
+		},
+		{
+			name:    "unclosed message",
+			input:   "a { b: 1",
+			wantErr: &Error{Pos: Position{Offset: 8, Line: 1, Column: 9}, Msg: "unexpected end of input"},
+		},
+		{
+			name:    "bad character",
+			input:   "a: @",
+			wantErr: &Error{Pos: Position{Offset: 3, Line: 1, Column: 4}, Msg: `unexpected character '@'`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse([]byte(tc.input))
+
+			if tc.wantErr != nil {
+				if diff := cmp.Diff(tc.wantErr, err); diff != "" {
+					t.Errorf("Error diff (-want +got):\n%s", diff)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreTypes(Position{})); diff != "" {
+				t.Errorf("Parse() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLocate(t *testing.T) {
+	root, err := Parse([]byte(`nodes { name: "a" }
+nodes [{ name: "b" }, {
+  name: "c"
+  areas { top: 1 }
+}]
+`))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		path   Path
+		want   Position
+		wantOK bool
+	}{
+		{
+			name:   "root",
+			want:   Position{Line: 1, Column: 1},
+			wantOK: true,
+		},
+		{
+			name:   "first",
+			path:   Path{{"nodes", 0}},
+			want:   Position{Offset: 0, Line: 1, Column: 1},
+			wantOK: true,
+		},
+		{
+			name:   "list item",
+			path:   Path{{"nodes", 1}},
+			want:   Position{Offset: 27, Line: 2, Column: 8},
+			wantOK: true,
+		},
+		{
+			name:   "nested",
+			path:   Path{{"nodes", 2}, {"areas", 0}, {"top", 0}},
+			want:   Position{Offset: 66, Line: 4, Column: 11},
+			wantOK: true,
+		},
+		{
+			name: "missing field",
+			path: Path{{"nodes", 2}, {"areas", 0}, {"left", 0}},
+			want: Position{Offset: 58, Line: 4, Column: 3},
+		},
+		{
+			name: "missing index",
+			path: Path{{"nodes", 3}},
+			want: Position{Line: 1, Column: 1},
+		},
+		{
+			name: "scalar",
+			path: Path{{"nodes", 0}, {"name", 0}, {"x", 0}},
+			want: Position{Offset: 8, Line: 1, Column: 9},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := root.Locate(tc.path)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Locate() diff (-want +got):\n%s", diff)
+			}
+
+			if ok != tc.wantOK {
+				t.Errorf("Locate() returned %v, want %v", ok, tc.wantOK)
+			}
+		})
+	}
+}
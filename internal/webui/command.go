@@ -3,17 +3,20 @@ package webui
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"runtime"
 
 	"github.com/google/subcommands"
+	"github.com/hansmi/dossier/internal/cliutil"
 )
 
 type Command struct {
-	listenAddr string
-	serverOpts serverOptions
+	listenAddr    string
+	extraSketches []string
+	serverOpts    serverOptions
 }
 
 func (*Command) Name() string {
@@ -25,7 +28,10 @@ func (*Command) Synopsis() string {
 }
 
 func (c *Command) Usage() string {
-	return `Arguments: ` + c.Name() + ` <document_file> <sketch_file>
+	return `Arguments: ` + c.Name() + ` <document_file|directory>... <sketch_file>
+
+All files in a directory are shown as documents. Use -sketch to add more
+sketches.
 
 Flags:
 `
@@ -38,9 +44,25 @@ func (c *Command) SetFlags(fs *flag.FlagSet) {
 		"Maximum number of concurrently processed requests.")
 	fs.IntVar(&c.serverOpts.maxPages, "max_pages", 10,
 		"Maximum number of pages to parse.")
+	fs.Var(cliutil.NewParamsVar(&c.serverOpts.sketchParams), "param",
+		"Override a sketch parameter using name=value. May be repeated.")
+	cliutil.AddSketchRootFlag(fs, &c.serverOpts.sketchRoot)
+	fs.Func("sketch", "Additional sketch file. May be repeated.", func(value string) error {
+		c.extraSketches = append(c.extraSketches, value)
+		return nil
+	})
+	fs.BoolVar(&c.serverOpts.watch, "watch", true,
+		"Watch the sketches and the documents for changes and reload open pages.")
+	fs.BoolVar(&c.serverOpts.allowSave, "allow_save", false,
+		"Allow the sketch editor to overwrite the sketch file.")
 }
 
 func (c *Command) execute(ctx context.Context) error {
+	s, err := newServer(c.serverOpts)
+	if err != nil {
+		return err
+	}
+
 	ln, err := net.Listen("tcp", c.listenAddr)
 	if err != nil {
 		return err
@@ -48,22 +70,23 @@ func (c *Command) execute(ctx context.Context) error {
 
 	log.Printf("HTTP server listening on http://%s", ln.Addr())
 
-	s, err := newServer(c.serverOpts)
-	if err != nil {
-		return err
+	if c.serverOpts.watch {
+		if err := s.startWatching(ctx); err != nil {
+			return fmt.Errorf("watching files: %w", err)
+		}
 	}
 
 	return http.Serve(ln, s.makeRouter())
 }
 
 func (c *Command) Execute(ctx context.Context, fs *flag.FlagSet, args ...any) subcommands.ExitStatus {
-	if fs.NArg() != 2 {
+	if fs.NArg() < 2 {
 		fs.Usage()
 		return subcommands.ExitUsageError
 	}
 
-	c.serverOpts.documentPath = fs.Arg(0)
-	c.serverOpts.sketchPath = fs.Arg(1)
+	c.serverOpts.documentPaths = fs.Args()[:fs.NArg()-1]
+	c.serverOpts.sketchPaths = append([]string{fs.Arg(fs.NArg() - 1)}, c.extraSketches...)
 
 	if err := c.execute(ctx); err != nil {
 		log.Printf("Error: %v", err)
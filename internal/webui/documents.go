@@ -0,0 +1,216 @@
+package webui
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/internal/httperr"
+	"github.com/hansmi/dossier/internal/webui/template"
+	"github.com/hansmi/dossier/pkg/sketch"
+)
+
+// documentEntry is a document shown by the viewer. The parsed document is
+// retained while the file is unchanged to reuse its page cache.
+type documentEntry struct {
+	path string
+
+	mu          sync.Mutex
+	doc         *dossier.Document
+	fingerprint string
+}
+
+func newDocumentEntry(path string) *documentEntry {
+	return &documentEntry{
+		path: path,
+		doc:  dossier.NewDocument(path),
+	}
+}
+
+// open returns the document, replacing it if the file was modified.
+func (e *documentEntry) open() (*dossier.Document, fs.FileInfo, error) {
+	fi, err := os.Lstat(e.path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fp, err := e.doc.Fingerprint()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if e.fingerprint != fp {
+		if e.fingerprint != "" {
+			// Pages cached for the previous version are stale.
+			e.doc = dossier.NewDocument(e.path)
+		}
+
+		e.fingerprint = fp
+	}
+
+	return e.doc, fi, nil
+}
+
+// sketchEntry is a sketch file available in the viewer. Sketches are compiled
+// on every use to pick up modifications.
+type sketchEntry struct {
+	path string
+
+	// Directory containing the sketch and its imports.
+	dir string
+
+	// Slash-separated name of the sketch within the directory.
+	rel string
+}
+
+// name returns the name of the sketch file within its directory.
+func (e *sketchEntry) name() string {
+	return e.rel
+}
+
+// fs returns the file system used to load the sketch and its imports.
+func (e *sketchEntry) fs() fs.FS {
+	return os.DirFS(e.dir)
+}
+
+func (e *sketchEntry) compile(params map[string]any) (*sketch.Sketch, error) {
+	return sketch.CompileFileWithParams(e.fs(), e.name(), params)
+}
+
+// sourceFiles returns the names of the sketch file and the files it imports,
+// relative to the directory of the sketch.
+func (e *sketchEntry) sourceFiles(params map[string]any) []string {
+	fsys := &recordingFS{FS: e.fs()}
+
+	// Files read before a failure are included. Problems are reported when
+	// the sketch is used.
+	_, _ = sketch.CompileFileWithParams(fsys, e.name(), params)
+
+	if !slices.Contains(fsys.names, e.name()) {
+		fsys.names = append(fsys.names, e.name())
+	}
+
+	return fsys.names
+}
+
+// recordingFS records the names of all files opened successfully.
+type recordingFS struct {
+	fs.FS
+	names []string
+}
+
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	f, err := r.FS.Open(name)
+	if err == nil && !slices.Contains(r.names, name) {
+		r.names = append(r.names, name)
+	}
+
+	return f, err
+}
+
+// expandDocumentPaths replaces directories with the files they contain.
+// Hidden files and subdirectories are skipped.
+func expandDocumentPaths(paths []string) ([]string, error) {
+	var result []string
+
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !fi.IsDir() {
+			result = append(result, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				result = append(result, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no documents found in %q", paths)
+	}
+
+	return result, nil
+}
+
+// documentFromRequest returns the document given in the request URL.
+func (s *server) documentFromRequest(r *http.Request) (int, *documentEntry, error) {
+	idx, err := strconv.Atoi(chi.URLParam(r, "doc"))
+	if err != nil || idx < 0 || idx >= len(s.documents) {
+		return 0, nil, httperr.New(http.StatusNotFound, fmt.Errorf("document %q not found", chi.URLParam(r, "doc")))
+	}
+
+	return idx, s.documents[idx], nil
+}
+
+// sketchFromRequest returns the sketch given in the request URL or selected via
+// the "sketch" query parameter. The first sketch is used by default.
+func (s *server) sketchFromRequest(r *http.Request) (int, *sketchEntry, error) {
+	value := chi.URLParam(r, "sketch")
+
+	if value == "" {
+		value = r.URL.Query().Get("sketch")
+	}
+
+	if value == "" {
+		return 0, s.sketches[0], nil
+	}
+
+	idx, err := strconv.Atoi(value)
+	if err != nil || idx < 0 || idx >= len(s.sketches) {
+		return 0, nil, httperr.New(http.StatusNotFound, fmt.Errorf("sketch %q not found", value))
+	}
+
+	return idx, s.sketches[idx], nil
+}
+
+// documentChoices returns a menu entry for every document.
+func (s *server) documentChoices(active int, url func(int) string) []template.NavChoice {
+	var result []template.NavChoice
+
+	for idx, i := range s.documents {
+		result = append(result, template.NavChoice{
+			Text:   filepath.Base(i.path),
+			URL:    url(idx),
+			Active: idx == active,
+		})
+	}
+
+	return result
+}
+
+// sketchChoices returns a menu entry for every sketch.
+func (s *server) sketchChoices(active int, url func(int) string) []template.NavChoice {
+	var result []template.NavChoice
+
+	for idx, i := range s.sketches {
+		result = append(result, template.NavChoice{
+			Text:   i.name(),
+			URL:    url(idx),
+			Active: idx == active,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,281 @@
+package webui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing/fstest"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/hansmi/dossier/internal/httperr"
+	"github.com/hansmi/dossier/internal/textproto"
+	"github.com/hansmi/dossier/internal/webui/template"
+	"github.com/hansmi/dossier/pkg/sketch"
+)
+
+// Maximum size of sketches submitted by the editor.
+const maxEditedSketchSize = 4 << 20
+
+// overlayFS replaces the content of a single file within a file system.
+type overlayFS struct {
+	fs.FS
+	file fstest.MapFS
+}
+
+func newOverlayFS(fsys fs.FS, name string, content []byte) *overlayFS {
+	return &overlayFS{
+		FS: fsys,
+		file: fstest.MapFS{
+			name: &fstest.MapFile{Data: content, Mode: 0o644},
+		},
+	}
+}
+
+func (o *overlayFS) Open(name string) (fs.File, error) {
+	if _, ok := o.file[name]; ok {
+		return o.file.Open(name)
+	}
+
+	return o.FS.Open(name)
+}
+
+// sketchEditorData returns the content of the sketch for the editor. The
+// editor isn't shown if the sketch can't be read.
+func (s *server) sketchEditorData(sketchIdx int, entry *sketchEntry) *template.SketchEditorData {
+	name := entry.name()
+
+	content, err := fs.ReadFile(entry.fs(), name)
+	if err != nil {
+		// Reported when compiling the sketch.
+		return nil
+	}
+
+	return &template.SketchEditorData{
+		Name:      name,
+		Content:   string(content),
+		URL:       sketchFileURL(sketchIdx, name),
+		AllowSave: s.opts.allowSave,
+	}
+}
+
+type sketchPreviewRequest struct {
+	Sketch string `json:"sketch"`
+}
+
+// sketchEditorError is a problem within the edited sketch.
+type sketchEditorError struct {
+	// 1-based line and column numbers. Zero if unknown.
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+type sketchPreviewResponse struct {
+	// Problems within the edited sketch.
+	Errors []sketchEditorError `json:"errors"`
+
+	// Problems elsewhere, e.g. in imported sketches.
+	Messages []template.Message `json:"messages"`
+
+	// Rendered page content and analysis results.
+	Content  string `json:"content"`
+	Analysis string `json:"analysis"`
+}
+
+// isTextFormat reports whether a sketch file uses the Protocol Buffer text
+// format based on its name.
+func isTextFormat(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return false
+	}
+
+	return true
+}
+
+// editorErrors splits a sketch compilation error into problems within the
+// edited sketch and other messages.
+func editorErrors(sketchIdx int, name string, content []byte, err error) ([]sketchEditorError, []template.Message) {
+	var errs sketch.ConfigErrors
+
+	if !errors.As(err, &errs) {
+		result := sketchEditorError{
+			Message: err.Error(),
+		}
+
+		// Syntax errors of the text format are returned without a position.
+		var synErr *textproto.Error
+
+		if isTextFormat(name) {
+			if _, err := textproto.Parse(content); errors.As(err, &synErr) {
+				result.Line = synErr.Pos.Line
+				result.Column = synErr.Pos.Column
+				result.Message = synErr.Msg
+			}
+		}
+
+		return []sketchEditorError{result}, nil
+	}
+
+	var result []sketchEditorError
+	var messages []template.Message
+
+	for _, i := range errs {
+		if i.Pos.File != "" && i.Pos.File != name {
+			messages = append(messages, sketchMessages(sketchIdx, i)...)
+			continue
+		}
+
+		result = append(result, sketchEditorError{
+			Line:    i.Pos.Line,
+			Column:  i.Pos.Column,
+			Message: i.Err.Error(),
+		})
+	}
+
+	return result, messages
+}
+
+func renderToString(r *http.Request, c templ.Component) (string, error) {
+	var buf bytes.Buffer
+
+	if err := c.Render(r.Context(), &buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// handlePagePreview analyzes a page using a sketch sent by the editor without
+// saving it.
+func (s *server) handlePagePreview(w http.ResponseWriter, r *http.Request) error {
+	var req sketchPreviewRequest
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEditedSketchSize)).Decode(&req); err != nil {
+		return httperr.New(http.StatusBadRequest, err)
+	}
+
+	docIdx, doc, page, err := s.loadPage(r)
+	if err != nil {
+		return err
+	}
+
+	sketchIdx, entry, err := s.sketchFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	fp, err := doc.Fingerprint()
+	if err != nil {
+		return err
+	}
+
+	data := template.PageData{
+		Doc:            docIdx,
+		DocFingerprint: fp,
+		Page:           page,
+	}
+
+	resp := sketchPreviewResponse{
+		Errors:   []sketchEditorError{},
+		Messages: []template.Message{},
+	}
+
+	name := entry.name()
+	content := []byte(req.Sketch)
+
+	if cfg, err := sketch.CompileFileWithParams(newOverlayFS(entry.fs(), name, content), name, s.opts.sketchParams); err != nil {
+		errs, messages := editorErrors(sketchIdx, name, content, err)
+
+		resp.Errors = append(resp.Errors, errs...)
+		resp.Messages = append(resp.Messages, messages...)
+	} else {
+		resp.Messages = append(resp.Messages, analyzePage(&data, cfg)...)
+	}
+
+	if resp.Content, err = renderToString(r, template.PageContent(data)); err != nil {
+		return err
+	}
+
+	if resp.Analysis, err = renderToString(r, template.PageAnalysis(data)); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(w).Encode(resp)
+}
+
+// writeFileReplace replaces the content of a file by writing to a temporary
+// file and renaming it. The permissions of an existing file are retained.
+func writeFileReplace(path string, content []byte) (err error) {
+	mode := os.FileMode(0o644)
+
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// handleSketchSave writes the sketch sent by the editor back to the sketch
+// file. Only permitted if enabled on the command line.
+func (s *server) handleSketchSave(w http.ResponseWriter, r *http.Request) error {
+	if !s.opts.allowSave {
+		return httperr.New(http.StatusForbidden, errors.New("saving the sketch is not enabled"))
+	}
+
+	_, entry, err := s.sketchFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	if name := chi.URLParam(r, "*"); name != entry.name() {
+		return httperr.New(http.StatusForbidden, errors.New("only the main sketch file can be saved"))
+	}
+
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEditedSketchSize))
+	if err != nil {
+		return httperr.New(http.StatusBadRequest, err)
+	}
+
+	if err := writeFileReplace(entry.path, content); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
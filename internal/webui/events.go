@@ -0,0 +1,109 @@
+package webui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/hansmi/dossier/internal/httperr"
+	"github.com/hansmi/dossier/internal/webui/template"
+)
+
+// changeEvent is sent to browsers after the sketch or the document changed.
+type changeEvent struct {
+	// Problems with the sketch, if any.
+	Messages []template.Message `json:"messages"`
+}
+
+// startWatching notifies event stream subscribers about changes to the
+// sketches and the documents until the context is cancelled.
+func (s *server) startWatching(ctx context.Context) error {
+	var sketchPaths, documentPaths []string
+
+	for _, i := range s.sketches {
+		sketchPaths = append(sketchPaths, i.path)
+	}
+
+	for _, i := range s.documents {
+		documentPaths = append(documentPaths, i.path)
+	}
+
+	fw, err := newFileWatcher(sketchPaths, documentPaths)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := fw.run(ctx, s.changes.notify); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("Watching files failed: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func writeServerSentEvent(w io.Writer, name string, data any) error {
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, buf)
+
+	return err
+}
+
+// handleEvents streams change notifications using Server-Sent Events. The
+// sketch selected via the query is compiled for every change to report
+// problems.
+func (s *server) handleEvents(w http.ResponseWriter, r *http.Request) error {
+	if !s.opts.watch {
+		return httperr.New(http.StatusNotFound, errors.New("watching files is disabled"))
+	}
+
+	sketchIdx, entry, err := s.sketchFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	changes, unsubscribe := s.changes.subscribe()
+	defer unsubscribe()
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+
+		case <-changes:
+		}
+
+		var ev changeEvent
+
+		if _, err := entry.compile(s.opts.sketchParams); err != nil {
+			ev.Messages = sketchMessages(sketchIdx, err)
+		}
+
+		if err := writeServerSentEvent(w, "change", ev); err != nil {
+			// Client went away
+			return nil
+		}
+
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
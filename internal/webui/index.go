@@ -0,0 +1,107 @@
+package webui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/hansmi/dossier/internal/webui/template"
+	"github.com/hansmi/dossier/pkg/sketch"
+	"github.com/sourcegraph/conc/pool"
+)
+
+// analyzeDocument adds a summary of analyzing a document using a sketch to the
+// result.
+func (s *server) analyzeDocument(ctx context.Context, entry *documentEntry, cfg *sketch.Sketch, result *template.IndexResultData) error {
+	pr, err := s.pageRange()
+	if err != nil {
+		return err
+	}
+
+	doc, _, err := entry.open()
+	if err != nil {
+		return err
+	}
+
+	report, err := cfg.AnalyzeDocument(ctx, doc, pr)
+	if err != nil {
+		return err
+	}
+
+	for _, page := range report.Pages() {
+		result.Pages++
+
+		for _, node := range page.Nodes() {
+			if node.Valid() {
+				result.ValidNodes++
+			} else {
+				result.InvalidNodes++
+			}
+		}
+	}
+
+	result.ChecksFailed = report.ChecksFailed()
+
+	return nil
+}
+
+// handleIndex lists all documents together with a summary of their analysis
+// using every sketch.
+func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) error {
+	var messages []template.Message
+
+	data := template.IndexContentData{}
+
+	sketches := make([]*sketch.Sketch, len(s.sketches))
+
+	for idx, i := range s.sketches {
+		data.Sketches = append(data.Sketches, i.name())
+
+		cfg, err := i.compile(s.opts.sketchParams)
+		if err != nil {
+			messages = append(messages, sketchMessages(idx, err)...)
+			continue
+		}
+
+		sketches[idx] = cfg
+	}
+
+	p := pool.New().WithMaxGoroutines(s.opts.maxConcurrent)
+
+	for docIdx, entry := range s.documents {
+		doc := template.IndexDocumentData{
+			Name:    filepath.Base(entry.path),
+			Path:    entry.path,
+			URL:     template.DocumentURL(docIdx, 0),
+			Results: make([]template.IndexResultData, len(s.sketches)),
+		}
+
+		for sketchIdx, cfg := range sketches {
+			result := &doc.Results[sketchIdx]
+			result.URL = template.DocumentURL(docIdx, sketchIdx)
+
+			if cfg == nil {
+				result.Err = fmt.Sprintf("Sketch %s failed to compile", s.sketches[sketchIdx].name())
+				continue
+			}
+
+			p.Go(func() {
+				if err := s.analyzeDocument(r.Context(), entry, cfg, result); err != nil {
+					result.Err = err.Error()
+				}
+			})
+		}
+
+		data.Documents = append(data.Documents, doc)
+	}
+
+	p.Wait()
+
+	return template.Base(template.BaseData{
+		HeadTitle:    "Documents",
+		TopNavActive: template.TopNavIndex,
+		Messages:     messages,
+		Content:      template.IndexContent(data),
+	}).Render(r.Context(), w)
+}
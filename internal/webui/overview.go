@@ -10,13 +10,35 @@ import (
 	"github.com/hansmi/dossier/pkg/pagerange"
 )
 
+// pageRange returns the pages to parse for an overview.
+func (s *server) pageRange() (pagerange.Range, error) {
+	if s.opts.maxPages > 0 {
+		return pagerange.New(1, s.opts.maxPages)
+	}
+
+	return pagerange.All, nil
+}
+
 func (s *server) handleOverview(w http.ResponseWriter, r *http.Request) error {
-	doc, fi, err := s.openDocument(r.Context())
+	docIdx, entry, err := s.documentFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	sketchIdx, _, err := s.sketchFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	doc, fi, err := entry.open()
 	if err != nil {
 		return err
 	}
 
-	var data template.OverviewContentData
+	data := template.OverviewContentData{
+		Doc:    docIdx,
+		Sketch: sketchIdx,
+	}
 
 	if fp, err := doc.Fingerprint(); err != nil {
 		return err
@@ -24,12 +46,9 @@ func (s *server) handleOverview(w http.ResponseWriter, r *http.Request) error {
 		data.DocFingerprint = fp
 	}
 
-	pr := pagerange.All
-
-	if s.opts.maxPages > 0 {
-		if pr, err = pagerange.New(1, s.opts.maxPages); err != nil {
-			return err
-		}
+	pr, err := s.pageRange()
+	if err != nil {
+		return err
 	}
 
 	if pages, err := doc.ParsePages(r.Context(), pr); err != nil {
@@ -43,7 +62,14 @@ func (s *server) handleOverview(w http.ResponseWriter, r *http.Request) error {
 	return template.Base(template.BaseData{
 		HeadTitle:    filepath.Base(doc.Path()),
 		TopNavActive: template.TopNavOverview,
-		Content:      template.OverviewContent(data),
+		OverviewURL:  template.DocumentURL(docIdx, sketchIdx),
+		Documents: s.documentChoices(docIdx, func(idx int) string {
+			return template.DocumentURL(idx, sketchIdx)
+		}),
+		Sketches: s.sketchChoices(sketchIdx, func(idx int) string {
+			return template.DocumentURL(docIdx, idx)
+		}),
+		Content: template.OverviewContent(data),
 		Sidebar: template.OverviewSidebar(template.OverviewSidebarData{
 			Path:        doc.Path(),
 			Size:        humanize.IBytes(uint64(fi.Size())),
@@ -7,65 +7,101 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/hansmi/dossier"
 	"github.com/hansmi/dossier/internal/httperr"
 	"github.com/hansmi/dossier/internal/webui/template"
 	"github.com/hansmi/dossier/pkg/pagerange"
+	"github.com/hansmi/dossier/pkg/sketch"
 )
 
-func (s *server) handlePage(w http.ResponseWriter, r *http.Request) error {
+// loadPage parses the page given in the request URL. The index of the
+// document is returned as well.
+func (s *server) loadPage(r *http.Request) (int, *dossier.Document, *dossier.Page, error) {
 	pageNumber, err := strconv.Atoi(chi.URLParam(r, "num"))
 	if err != nil {
-		return httperr.New(http.StatusBadRequest, err)
+		return 0, nil, nil, httperr.New(http.StatusBadRequest, err)
 	}
 
-	doc, _, err := s.openDocument(r.Context())
+	docIdx, entry, err := s.documentFromRequest(r)
 	if err != nil {
-		return err
+		return 0, nil, nil, err
 	}
 
-	fp, err := doc.Fingerprint()
+	doc, _, err := entry.open()
 	if err != nil {
-		return err
+		return 0, nil, nil, err
 	}
 
 	pr, err := pagerange.Single(pageNumber)
 
 	if err != nil {
-		return httperr.New(http.StatusNotFound, fmt.Errorf("page %d not found: %w", pageNumber, err))
+		return 0, nil, nil, httperr.New(http.StatusNotFound, fmt.Errorf("page %d not found: %w", pageNumber, err))
 	}
 
 	pages, err := doc.ParsePages(r.Context(), pr)
 	if err != nil {
-		return err
+		return 0, nil, nil, err
 	}
 
 	if !(len(pages) > 0 && pages[0].Number() == pageNumber) {
-		return httperr.New(http.StatusNotFound, fmt.Errorf("page %d not found", pageNumber))
+		return 0, nil, nil, httperr.New(http.StatusNotFound, fmt.Errorf("page %d not found", pageNumber))
+	}
+
+	return docIdx, doc, pages[0], nil
+}
+
+// analyzePage runs a sketch on a page and adds the results to the page data.
+// Problems are returned as messages.
+func analyzePage(data *template.PageData, cfg *sketch.Sketch) []template.Message {
+	report, err := cfg.AnalyzePage(data.Page, sketch.WithTrace())
+	if err != nil {
+		return []template.Message{{Text: fmt.Sprintf("Processing document: %v", err)}}
+	}
+
+	var nodes []template.SketchNodeData
+
+	for idx, i := range report.Nodes() {
+		nodes = append(nodes, template.SketchNodeData{
+			ID:   fmt.Sprintf("sketch_node_%d_info", idx),
+			Node: i,
+		})
+	}
+
+	data.SketchNodes = nodes
+	data.Computed = report.Computed()
+
+	return nil
+}
+
+func (s *server) handlePage(w http.ResponseWriter, r *http.Request) error {
+	docIdx, doc, page, err := s.loadPage(r)
+	if err != nil {
+		return err
+	}
+
+	sketchIdx, entry, err := s.sketchFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	fp, err := doc.Fingerprint()
+	if err != nil {
+		return err
 	}
 
-	var messages []string
+	var messages []template.Message
 
-	page := pages[0]
 	data := template.PageData{
+		Doc:            docIdx,
 		DocFingerprint: fp,
 		Page:           page,
+		Editor:         s.sketchEditorData(sketchIdx, entry),
 	}
 
-	if cfg, err := s.compileSketch(); err != nil {
-		messages = append(messages, fmt.Sprintf("Sketch: %v", err))
-	} else if report, err := cfg.AnalyzePage(page); err != nil {
-		messages = append(messages, fmt.Sprintf("Processing document: %v", err))
+	if cfg, err := entry.compile(s.opts.sketchParams); err != nil {
+		messages = append(messages, sketchMessages(sketchIdx, err)...)
 	} else {
-		var nodes []template.SketchNodeData
-
-		for idx, i := range report.Nodes() {
-			nodes = append(nodes, template.SketchNodeData{
-				ID:   fmt.Sprintf("sketch_node_%d_info", idx),
-				Node: i,
-			})
-		}
-
-		data.SketchNodes = nodes
+		messages = append(messages, analyzePage(&data, cfg)...)
 	}
 
 	return template.Base(template.BaseData{
@@ -73,6 +109,14 @@ func (s *server) handlePage(w http.ResponseWriter, r *http.Request) error {
 		Scripts: []string{
 			"/static/page.js",
 		},
+		OverviewURL: template.DocumentURL(docIdx, sketchIdx),
+		Documents: s.documentChoices(docIdx, func(idx int) string {
+			// Other documents may have fewer pages.
+			return template.PageURL(idx, 1, sketchIdx)
+		}),
+		Sketches: s.sketchChoices(sketchIdx, func(idx int) string {
+			return template.PageURL(docIdx, page.Number(), idx)
+		}),
 		Messages: messages,
 		Content:  template.PageContent(data),
 		Sidebar:  template.PageSidebar(data),
@@ -30,7 +30,12 @@ func (s *server) handlePageImage(w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
-	doc, fi, err := s.openDocument(r.Context())
+	_, entry, err := s.documentFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	doc, fi, err := entry.open()
 	if err != nil {
 		return err
 	}
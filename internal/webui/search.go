@@ -0,0 +1,108 @@
+package webui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/internal/httperr"
+	"github.com/hansmi/dossier/pkg/geometry"
+)
+
+type searchRect struct {
+	Top    float64 `json:"top-pt"`
+	Left   float64 `json:"left-pt"`
+	Right  float64 `json:"right-pt"`
+	Bottom float64 `json:"bottom-pt"`
+}
+
+func newSearchRect(r geometry.Rect) searchRect {
+	return searchRect{
+		Top:    r.Top.Pt(),
+		Left:   r.Left.Pt(),
+		Right:  r.Right.Pt(),
+		Bottom: r.Bottom.Pt(),
+	}
+}
+
+type searchGroup struct {
+	Name   string      `json:"name,omitempty"`
+	Start  int         `json:"start"`
+	End    int         `json:"end"`
+	Text   string      `json:"text"`
+	Bounds *searchRect `json:"bounds,omitempty"`
+}
+
+type searchMatch struct {
+	Groups []searchGroup `json:"groups"`
+}
+
+type searchResponse struct {
+	Matches []searchMatch `json:"matches"`
+}
+
+func parseSearchScope(value string) (dossier.TextSearchScope, error) {
+	switch value {
+	case "", "line":
+		return dossier.TextSearchLines, nil
+	case "block":
+		return dossier.TextSearchBlocks, nil
+	case "page":
+		return dossier.TextSearchPage, nil
+	}
+
+	return 0, fmt.Errorf("unknown search scope %q", value)
+}
+
+func (s *server) handlePageSearch(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	re, err := regexp.Compile(query.Get("q"))
+	if err != nil {
+		return httperr.New(http.StatusBadRequest, err)
+	}
+
+	scope, err := parseSearchScope(query.Get("scope"))
+	if err != nil {
+		return httperr.New(http.StatusBadRequest, err)
+	}
+
+	_, _, page, err := s.loadPage(r)
+	if err != nil {
+		return err
+	}
+
+	resp := searchResponse{
+		Matches: []searchMatch{},
+	}
+
+	if re.String() != "" {
+		for _, m := range page.FindText(re, dossier.FindTextOptions{Scope: scope}) {
+			var sm searchMatch
+
+			for _, g := range m.Groups {
+				sg := searchGroup{
+					Name:  g.Name,
+					Start: g.Start,
+					End:   g.End,
+					Text:  g.Text,
+				}
+
+				if g.Start >= 0 {
+					bounds := newSearchRect(g.Bounds)
+					sg.Bounds = &bounds
+				}
+
+				sm.Groups = append(sm.Groups, sg)
+			}
+
+			resp.Matches = append(resp.Matches, sm)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(w).Encode(resp)
+}
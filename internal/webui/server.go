@@ -1,18 +1,14 @@
 package webui
 
 import (
-	"context"
 	"embed"
-	"fmt"
-	"io/fs"
+	"errors"
 	"net/http"
-	"os"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/internal/cliutil"
 	"github.com/hansmi/dossier/internal/httperr"
-	"github.com/hansmi/dossier/pkg/sketch"
 )
 
 //go:embed static/*.css static/*.js
@@ -21,12 +17,19 @@ var staticFiles embed.FS
 type serverOptions struct {
 	maxConcurrent int
 	maxPages      int
-	sketchPath    string
-	documentPath  string
+	sketchPaths   []string
+	sketchRoot    string
+	documentPaths []string
+	sketchParams  map[string]any
+	watch         bool
+	allowSave     bool
 }
 
 type server struct {
-	opts serverOptions
+	opts      serverOptions
+	changes   *broadcaster
+	documents []*documentEntry
+	sketches  []*sketchEntry
 }
 
 func newServer(opts serverOptions) (*server, error) {
@@ -34,8 +37,35 @@ func newServer(opts serverOptions) (*server, error) {
 		opts.maxConcurrent = 1
 	}
 
+	if len(opts.sketchPaths) == 0 {
+		return nil, errors.New("at least one sketch is required")
+	}
+
+	documentPaths, err := expandDocumentPaths(opts.documentPaths)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &server{
-		opts: opts,
+		opts:    opts,
+		changes: newBroadcaster(),
+	}
+
+	for _, path := range documentPaths {
+		s.documents = append(s.documents, newDocumentEntry(path))
+	}
+
+	for _, path := range opts.sketchPaths {
+		dir, name, err := cliutil.SketchLocation(opts.sketchRoot, path)
+		if err != nil {
+			return nil, err
+		}
+
+		s.sketches = append(s.sketches, &sketchEntry{
+			path: path,
+			dir:  dir,
+			rel:  name,
+		})
 	}
 
 	return s, nil
@@ -45,36 +75,25 @@ func (s *server) makeRouter() http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
-	r.Use(middleware.Throttle(s.opts.maxConcurrent))
-
-	r.Get(`/`, httperr.WrapHandler(httperr.HandlerFunc(s.handleOverview)))
-	r.Get(`/page/{num:\d+}`, httperr.WrapHandler(httperr.HandlerFunc(s.handlePage)))
-	r.Get(`/page/{num:\d+}/image`, httperr.WrapHandler(httperr.HandlerFunc(s.handlePageImage)))
-
-	r.Handle("/static/*", http.FileServerFS(staticFiles))
 
-	return r
-}
+	// Event streams are long-lived and must not count towards the limit of
+	// concurrent requests.
+	r.Get(`/events`, httperr.WrapHandler(httperr.HandlerFunc(s.handleEvents)))
 
-func (s *server) compileSketch() (*sketch.Sketch, error) {
-	content, err := os.ReadFile(s.opts.sketchPath)
-	if err != nil {
-		return nil, fmt.Errorf("reading sketch file: %w", err)
-	}
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Throttle(s.opts.maxConcurrent))
 
-	cfg, err := sketch.CompileFromTextproto(content)
-	if err != nil {
-		return nil, fmt.Errorf("compiling sketch: %w", err)
-	}
+		r.Get(`/`, httperr.WrapHandler(httperr.HandlerFunc(s.handleIndex)))
+		r.Get(`/doc/{doc:\d+}`, httperr.WrapHandler(httperr.HandlerFunc(s.handleOverview)))
+		r.Get(`/doc/{doc:\d+}/page/{num:\d+}`, httperr.WrapHandler(httperr.HandlerFunc(s.handlePage)))
+		r.Get(`/doc/{doc:\d+}/page/{num:\d+}/image`, httperr.WrapHandler(httperr.HandlerFunc(s.handlePageImage)))
+		r.Get(`/doc/{doc:\d+}/page/{num:\d+}/search`, httperr.WrapHandler(httperr.HandlerFunc(s.handlePageSearch)))
+		r.Post(`/doc/{doc:\d+}/page/{num:\d+}/preview`, httperr.WrapHandler(httperr.HandlerFunc(s.handlePagePreview)))
+		r.Get(`/sketch/{sketch:\d+}/*`, httperr.WrapHandler(httperr.HandlerFunc(s.handleSketchSource)))
+		r.Put(`/sketch/{sketch:\d+}/*`, httperr.WrapHandler(httperr.HandlerFunc(s.handleSketchSave)))
 
-	return cfg, nil
-}
+		r.Handle("/static/*", http.FileServerFS(staticFiles))
+	})
 
-func (s *server) openDocument(ctx context.Context) (*dossier.Document, fs.FileInfo, error) {
-	fi, err := os.Lstat(s.opts.documentPath)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return dossier.NewDocument(s.opts.documentPath), fi, nil
+	return r
 }
@@ -0,0 +1,94 @@
+package webui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hansmi/dossier/internal/httperr"
+	"github.com/hansmi/dossier/internal/webui/template"
+	"github.com/hansmi/dossier/pkg/sketch"
+)
+
+// sketchFileURL returns the URL of a file relative to the directory of a
+// sketch.
+func sketchFileURL(sketchIdx int, name string) string {
+	return (&url.URL{Path: fmt.Sprintf("/sketch/%d/%s", sketchIdx, name)}).String()
+}
+
+// sketchSourceURL returns the URL showing a location within a sketch file.
+func sketchSourceURL(sketchIdx int, pos sketch.Position) string {
+	u := sketchFileURL(sketchIdx, pos.File)
+
+	if pos.Line > 0 {
+		u += "#L" + strconv.Itoa(pos.Line)
+	}
+
+	return u
+}
+
+// sketchMessages converts a sketch compilation error to messages linking to
+// the offending locations.
+func sketchMessages(sketchIdx int, err error) []template.Message {
+	var errs sketch.ConfigErrors
+
+	if !errors.As(err, &errs) {
+		return []template.Message{{Text: "Sketch: " + err.Error()}}
+	}
+
+	var result []template.Message
+
+	for _, i := range errs {
+		msg := template.Message{
+			Text: "Sketch: " + i.Error(),
+		}
+
+		if i.Pos.File != "" {
+			msg.URL = sketchSourceURL(sketchIdx, i.Pos)
+		}
+
+		result = append(result, msg)
+	}
+
+	return result
+}
+
+// handleSketchSource shows the content of a sketch file or one of its imports.
+// Other files are not accessible.
+func (s *server) handleSketchSource(w http.ResponseWriter, r *http.Request) error {
+	_, entry, err := s.sketchFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	name := chi.URLParam(r, "*")
+
+	if !slices.Contains(entry.sourceFiles(s.opts.sketchParams), name) {
+		return httperr.New(http.StatusNotFound, fmt.Errorf("%q is not part of the sketch", name))
+	}
+
+	content, err := fs.ReadFile(entry.fs(), name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+			return httperr.New(http.StatusNotFound, err)
+		}
+
+		return err
+	}
+
+	data := template.SketchSourceData{
+		Path:  name,
+		Lines: strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"),
+	}
+
+	return template.Base(template.BaseData{
+		HeadTitle: name,
+		Content:   template.SketchSourceContent(data),
+	}).Render(r.Context(), w)
+}
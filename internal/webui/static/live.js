@@ -0,0 +1,60 @@
+// Receive notifications about changes to the sketch or the document from the
+// server. Pages handle changes by listening for the "dossier:change" event on
+// the document and cancelling it. The whole page is reloaded otherwise.
+
+const kChangeEvent = 'dossier:change';
+
+export function setMessages(messages) {
+  // The message area may get replaced when a page is updated.
+  const elMessages = document.getElementById('dossier_messages');
+
+  const list = elMessages.querySelector('ol');
+
+  list.replaceChildren(...messages.map((msg) => {
+    const item = document.createElement('li');
+
+    if (msg.url) {
+      const link = document.createElement('a');
+
+      link.href = msg.url;
+      link.classList.add('alert-link');
+      link.textContent = msg.text;
+
+      item.appendChild(link);
+    } else {
+      item.textContent = msg.text;
+    }
+
+    return item;
+  }));
+
+  elMessages.hidden = messages.length === 0;
+}
+
+function initLive() {
+  if (!window.EventSource) {
+    return;
+  }
+
+  // Problems are reported for the sketch selected via the query.
+  const source = new EventSource(`/events${window.location.search}`);
+
+  source.addEventListener('change', (ev) => {
+    const data = JSON.parse(ev.data);
+
+    setMessages(data.messages ?? []);
+
+    const notCancelled = document.dispatchEvent(new CustomEvent(kChangeEvent, {
+      cancelable: true,
+      detail: data,
+    }));
+
+    if (notCancelled) {
+      window.location.reload();
+    }
+  });
+}
+
+initLive();
+
+/* vim: set sw=2 sts=2 et : */
@@ -1,9 +1,13 @@
 import * as settings from './settings.js';
 import * as geometry from './geometry.js';
+import * as defaultunit from './defaultunit.js';
+import * as live from './live.js';
+import * as snippet from './snippet.js';
 
 const kShowKindSetting = 'show_kind';
 const kShowEmptySetting = 'show_empty';
 const kShowSketchNodeSetting = 'show_sketch_node';
+const kSnippetSnapSetting = 'snippet_snap';
 
 const divSidebar = document.getElementById('sidebar');
 
@@ -17,11 +21,32 @@ const cbSketchShowValid = document.getElementById('sketch_show_valid');
 
 const divViewer = document.getElementById('dossier_viewer');
 
+const formSearch = document.getElementById('page_search_form');
+const inputSearchQuery = document.getElementById('page_search_query');
+const divSearchScopeGroup = document.getElementById('page_search_scope_group');
+const elSearchStatus = document.getElementById('page_search_status');
+
+const formEditor = document.getElementById('sketch_editor_form');
+const textEditor = document.getElementById('sketch_editor_text');
+const elEditorErrors = document.getElementById('sketch_editor_errors');
+const btnEditorRevert = document.getElementById('sketch_editor_revert');
+const btnEditorSave = document.getElementById('sketch_editor_save');
+const elEditorStatus = document.getElementById('sketch_editor_status');
+
 const divNodeDialogTemplate = document.getElementById('dossier_page_node_dialog_template');
 const elNodeDialogKind = document.getElementById('dossier_page_node_dialog_kind');
 const elNodeDialogBounds = document.getElementById('dossier_page_node_dialog_bounds');
 const elNodeDialogText = document.getElementById('dossier_page_node_dialog_text');
 const elNodeDialogTextDetails = document.getElementById('dossier_page_node_dialog_text_details');
+const elNodeDialogSnippet = document.getElementById('dossier_page_node_dialog_snippet');
+
+const divSnippetReferenceGroup = document.getElementById('snippet_reference_group');
+const rbSnippetReferenceRel = document.getElementById('snippet_reference_rel');
+const selectSnippetNode = document.getElementById('snippet_node');
+const selectSnippetFeature = document.getElementById('snippet_feature');
+const cbSnippetSnap = document.getElementById('snippet_snap');
+const textSnippet = document.getElementById('snippet_text');
+const btnSnippetCopy = document.getElementById('snippet_copy');
 
 const kDocBlockClass = 'overlay_doc_block';
 const kDocLineClass = 'overlay_doc_line';
@@ -32,6 +57,21 @@ const kSketchNodeVisibleClass = 'dossier_sketch_node_visible';
 const kSketchNodeInfoClass = 'dossier_sketch_node_info';
 const kSketchNodeHighlightClass = 'dossier_sketch_node_highlight';
 const kSketchNodeSearchAreaHighlightClass = 'dossier_sketch_node_search_area_highlight';
+const kSearchMatchClass = 'dossier_search_match';
+const kChangeEvent = 'dossier:change';
+
+// Space around a line included in the search area of a proposed node.
+const kSnippetNodeMargin = new geometry.Length(2, geometry.Millimeter);
+
+// Convert bounds using the attribute names of the geometry elements.
+function parseBounds(bounds) {
+  return {
+    left: bounds['left-pt'],
+    top: bounds['top-pt'],
+    right: bounds['right-pt'],
+    bottom: bounds['bottom-pt'],
+  };
+}
 
 function applyShowLayout() {
   const toggle = (token, force) => {
@@ -200,16 +240,21 @@ class PositionTool {
 class MeasurementTool {
   _listenerAbortController = null;
   _origin = null;
+  _rect = null;
   _frame = null;
   _infoPopper = null;
   _infoPopperPlacement = null;
   _infoWrapper = null;
   _info = null;
 
-  constructor(element) {
+  // The optional snap function adjusts a rectangle given in points. The
+  // optional select function receives the final rectangle in points.
+  constructor(element, { snap, select } = {}) {
     this._elem = element;
     this._physWidth = new geometry.Length(this._elem.dataset.widthPt, geometry.Point);
     this._physHeight = new geometry.Length(this._elem.dataset.heightPt, geometry.Point);
+    this._snap = snap ?? ((rect) => rect);
+    this._select = select ?? (() => {});
 
     this._ensureFrame();
     this._ensureInfo();
@@ -380,10 +425,25 @@ class MeasurementTool {
   _onAfterMove(lifecycle, data) {
     const clamp = (value) => Math.max(0, Math.min(100, value));
 
-    const left = clamp(Math.min(this._origin.posPct.x, data.posPct.x));
-    const top = clamp(Math.min(this._origin.posPct.y, data.posPct.y));
-    const width = clamp(Math.max(this._origin.posPct.x, data.posPct.x)) - left;
-    const height = clamp(Math.max(this._origin.posPct.y, data.posPct.y)) - top;
+    const widthPt = this._physWidth.pt;
+    const heightPt = this._physHeight.pt;
+
+    // Snap within a few pixels.
+    const threshold = 5 * widthPt / data.elemBounds.width;
+
+    const rect = this._snap({
+      left: clamp(Math.min(this._origin.posPct.x, data.posPct.x)) * widthPt / 100,
+      top: clamp(Math.min(this._origin.posPct.y, data.posPct.y)) * heightPt / 100,
+      right: clamp(Math.max(this._origin.posPct.x, data.posPct.x)) * widthPt / 100,
+      bottom: clamp(Math.max(this._origin.posPct.y, data.posPct.y)) * heightPt / 100,
+    }, threshold);
+
+    this._rect = rect;
+
+    const left = rect.left * 100 / widthPt;
+    const top = rect.top * 100 / heightPt;
+    const width = (rect.right - rect.left) * 100 / widthPt;
+    const height = (rect.bottom - rect.top) * 100 / heightPt;
 
     const frameStyle = this._frame.style;
 
@@ -395,10 +455,10 @@ class MeasurementTool {
     if (this._infoPopper !== null) {
       const info = this._info;
 
-      info.setAttribute('left-pt', left * this._physWidth.pt / 100);
-      info.setAttribute('top-pt', top * this._physHeight.pt / 100);
-      info.setAttribute('right-pt', (left + width) * this._physWidth.pt / 100);
-      info.setAttribute('bottom-pt', (top + height) * this._physHeight.pt / 100);
+      info.setAttribute('left-pt', rect.left);
+      info.setAttribute('top-pt', rect.top);
+      info.setAttribute('right-pt', rect.right);
+      info.setAttribute('bottom-pt', rect.bottom);
 
       this._updateInfoPopperPlacement(data);
       this._infoPopper.update();
@@ -412,6 +472,7 @@ class MeasurementTool {
     }
 
     this._origin = null;
+    this._rect = null;
   }
 
   _onAfterUp(lifecycle) {
@@ -431,6 +492,10 @@ class MeasurementTool {
         ev.stopImmediatePropagation();
         ev.preventDefault();
       }, { capture: true, once: true });
+
+      if (this._rect !== null) {
+        this._select(this._rect);
+      }
       break;
     }
 
@@ -474,13 +539,20 @@ class MeasurementTool {
   }
 }
 
-function initViewer() {
-  divViewer.querySelectorAll('img, a').forEach((i) => {
+function disableDragging(root) {
+  root.querySelectorAll('img, a').forEach((i) => {
     i.draggable = false;
   });
+}
+
+function initViewer(snippetTool) {
+  disableDragging(divViewer);
 
   (new PositionTool(divViewer)).activate();
-  (new MeasurementTool(divViewer)).activate();
+  (new MeasurementTool(divViewer, {
+    snap: snippetTool.snap.bind(snippetTool),
+    select: snippetTool.select.bind(snippetTool),
+  })).activate();
 
   divViewer.addEventListener('click', (ev) => {
     const overlay = ev.target.closest('.dossier_viewer_overlay');
@@ -537,10 +609,17 @@ function initViewer() {
     }));
 
     elNodeDialogTextDetails.value = lines.join('\n');
+
+    if (overlay.dataset.nodeKind === 'Line') {
+      elNodeDialogSnippet.value = snippet.lineTextNode(text, parseBounds(bounds),
+        defaultunit.get(), kSnippetNodeMargin.pt);
+    } else {
+      elNodeDialogSnippet.value = '# Only lines can be matched using line_text.';
+    }
   });
 }
 
-function initSidebar() {
+function initSidebar(root) {
   const onSketchNodeInfoMouseEnter = (ev) => {
     const id = ev.target.id;
     const modified = new Array();
@@ -566,13 +645,638 @@ function initSidebar() {
     }
   };
 
-  divSidebar.querySelectorAll(`.${kSketchNodeInfoClass}`).forEach((el) => {
+  root.querySelectorAll(`.${kSketchNodeInfoClass}`).forEach((el) => {
     el.addEventListener('mouseenter', onSketchNodeInfoMouseEnter);
   });
 }
 
+class TextSearch {
+  _abortController = null;
+  _timeout = null;
+
+  constructor(element) {
+    this._elem = element;
+    this._physWidth = new geometry.Length(this._elem.dataset.widthPt, geometry.Point);
+    this._physHeight = new geometry.Length(this._elem.dataset.heightPt, geometry.Point);
+  }
+
+  activate() {
+    formSearch.addEventListener('submit', (ev) => {
+      ev.preventDefault();
+      this._schedule(0);
+    });
+
+    inputSearchQuery.addEventListener('input', () => this._schedule(300), {
+      passive: true,
+    });
+
+    divSearchScopeGroup.addEventListener('change', () => this._schedule(0), {
+      passive: true,
+    });
+  }
+
+  _schedule(delay) {
+    window.clearTimeout(this._timeout);
+    this._timeout = window.setTimeout(this._search.bind(this), delay);
+  }
+
+  refresh() {
+    this._schedule(0);
+  }
+
+  _clear() {
+    this._elem.querySelectorAll(`.${kSearchMatchClass}`).forEach((el) => el.remove());
+  }
+
+  _addHighlight(bounds, title) {
+    const el = document.createElement('div');
+
+    el.classList.add(kSearchMatchClass, 'position-absolute');
+    el.title = title;
+
+    const style = el.style;
+
+    style.left = `${bounds['left-pt'] * 100 / this._physWidth.pt}%`;
+    style.top = `${bounds['top-pt'] * 100 / this._physHeight.pt}%`;
+    style.width = `${(bounds['right-pt'] - bounds['left-pt']) * 100 / this._physWidth.pt}%`;
+    style.height = `${(bounds['bottom-pt'] - bounds['top-pt']) * 100 / this._physHeight.pt}%`;
+
+    this._elem.appendChild(el);
+  }
+
+  async _search() {
+    if (this._abortController !== null) {
+      this._abortController.abort();
+    }
+
+    const abortController = new AbortController();
+
+    this._abortController = abortController;
+
+    const query = inputSearchQuery.value;
+    const scope = divSearchScopeGroup.querySelector('input[name="page_search_scope"]:checked')?.value;
+
+    if (query === '') {
+      this._clear();
+      elSearchStatus.textContent = '';
+      return;
+    }
+
+    const url = new URL(`${window.location.pathname}/search`, window.location.href);
+
+    url.searchParams.set('q', query);
+    url.searchParams.set('scope', scope ?? '');
+
+    let data;
+
+    try {
+      const response = await fetch(url, { signal: abortController.signal });
+
+      if (!response.ok) {
+        throw new Error((await response.text()).trim());
+      }
+
+      data = await response.json();
+    } catch (err) {
+      if (!abortController.signal.aborted) {
+        this._clear();
+        elSearchStatus.textContent = err.message;
+      }
+
+      return;
+    }
+
+    this._clear();
+
+    data.matches.forEach((m) => {
+      const match = m.groups[0];
+
+      if (match.bounds) {
+        this._addHighlight(match.bounds, match.text);
+      }
+    });
+
+    elSearchStatus.textContent = `${data.matches.length} match(es)`;
+  }
+}
+
+// Replace the page image, the overlays and the analysis with a freshly
+// rendered version of the page after the sketch or the document changed.
+class PageReloader {
+  _abortController = null;
+  _editor = null;
+
+  // The update function is called after the page content was replaced.
+  constructor(update) {
+    this._update = update;
+  }
+
+  setEditor(editor) {
+    this._editor = editor;
+  }
+
+  activate() {
+    document.addEventListener(kChangeEvent, (ev) => {
+      ev.preventDefault();
+      this.reload();
+    });
+  }
+
+  _startRequest() {
+    if (this._abortController !== null) {
+      this._abortController.abort();
+    }
+
+    this._abortController = new AbortController();
+
+    return this._abortController;
+  }
+
+  async reload() {
+    if (this._editor?.dirty) {
+      // Keep showing the modified sketch.
+      await this._editor.preview();
+      return;
+    }
+
+    const abortController = this._startRequest();
+
+    let doc;
+
+    try {
+      const response = await fetch(window.location.href, {
+        cache: 'no-store',
+        signal: abortController.signal,
+      });
+
+      if (!response.ok) {
+        throw new Error(response.statusText);
+      }
+
+      doc = new DOMParser().parseFromString(await response.text(), 'text/html');
+    } catch (err) {
+      if (!abortController.signal.aborted) {
+        // Page may no longer exist
+        window.location.reload();
+      }
+
+      return;
+    }
+
+    if (this._apply(doc.getElementById('dossier_viewer'), doc.getElementById('page_analysis'))) {
+      document.getElementById('dossier_messages').replaceWith(doc.getElementById('dossier_messages'));
+
+      this._editor?.update(doc.getElementById('sketch_editor_text')?.value ?? null);
+    }
+  }
+
+  // Analyze the page using a modified sketch. Returns the problems found in
+  // the sketch.
+  async preview(sketch) {
+    const abortController = this._startRequest();
+
+    // The query selects the sketch.
+    const response = await fetch(`${window.location.pathname}/preview${window.location.search}`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ sketch }),
+      signal: abortController.signal,
+    });
+
+    if (!response.ok) {
+      throw new Error((await response.text()).trim());
+    }
+
+    const data = await response.json();
+
+    const parse = (html) => {
+      const el = document.createElement('template');
+
+      el.innerHTML = html;
+
+      return el.content;
+    };
+
+    if (this._apply(parse(data.content).getElementById('dossier_viewer'),
+      parse(data.analysis).getElementById('page_analysis'))) {
+      live.setMessages(data.messages);
+    }
+
+    return data.errors;
+  }
+
+  // Replace the page content. Returns false if the whole page is reloaded
+  // instead.
+  _apply(newViewer, newAnalysis) {
+    if (!newViewer ||
+      newViewer.dataset.widthPt !== divViewer.dataset.widthPt ||
+      newViewer.dataset.heightPt !== divViewer.dataset.heightPt) {
+      // The tools depend on the page size.
+      window.location.reload();
+      return false;
+    }
+
+    this._replaceViewer(newViewer);
+    this._replaceAnalysis(newAnalysis);
+
+    this._update();
+
+    return true;
+  }
+
+  // Elements with a tooltip including the root itself.
+  _tooltipElements(root) {
+    const selector = '[data-bs-toggle="tooltip"]';
+    const result = Array.from(root.querySelectorAll(selector));
+
+    if (root.matches(selector)) {
+      result.unshift(root);
+    }
+
+    return result;
+  }
+
+  _disposeTooltips(root) {
+    this._tooltipElements(root).forEach((el) => {
+      window.bootstrap.Tooltip.getInstance(el)?.dispose();
+    });
+  }
+
+  _createTooltips(root) {
+    this._tooltipElements(root).forEach((el) => {
+      new window.bootstrap.Tooltip(el);
+    });
+  }
+
+  _replaceViewer(newViewer) {
+    const image = divViewer.querySelector('img');
+    const newImage = newViewer.querySelector('img');
+
+    divViewer.querySelectorAll('.dossier_viewer_overlay').forEach((el) => {
+      this._disposeTooltips(el);
+      el.remove();
+    });
+
+    const overlays = Array.from(newViewer.querySelectorAll('.dossier_viewer_overlay'));
+
+    if (newImage.getAttribute('src') !== image.getAttribute('src')) {
+      newImage.draggable = false;
+      image.replaceWith(newImage);
+      newImage.after(...overlays);
+    } else {
+      image.after(...overlays);
+    }
+
+    overlays.forEach((el) => {
+      disableDragging(el);
+      this._createTooltips(el);
+    });
+  }
+
+  _replaceAnalysis(newAnalysis) {
+    const analysis = document.getElementById('page_analysis');
+
+    // Retain expanded or collapsed sections.
+    analysis.querySelectorAll('.accordion-collapse[id]').forEach((el) => {
+      const newEl = newAnalysis.querySelector(`#${el.id}`);
+      const newButton = newAnalysis.querySelector(`[data-bs-target="#${el.id}"]`);
+      const expanded = el.classList.contains('show');
+
+      newEl?.classList.toggle('show', expanded);
+      newButton?.classList.toggle('collapsed', !expanded);
+      newButton?.setAttribute('aria-expanded', expanded);
+    });
+
+    const scrollPositions = new Map();
+
+    analysis.querySelectorAll('[id]').forEach((el) => {
+      if (el.scrollTop > 0) {
+        scrollPositions.set(el.id, el.scrollTop);
+      }
+    });
+
+    this._disposeTooltips(analysis);
+
+    analysis.replaceWith(newAnalysis);
+
+    scrollPositions.forEach((value, id) => {
+      const el = document.getElementById(id);
+
+      if (el) {
+        el.scrollTop = value;
+      }
+    });
+
+    this._createTooltips(newAnalysis);
+
+    initSidebar(newAnalysis);
+  }
+}
+
+// Edit the sketch in the browser. Changes are previewed without saving.
+class SketchEditor {
+  _timeout = null;
+
+  constructor(reloader) {
+    this._reloader = reloader;
+    this._saved = textEditor.value;
+  }
+
+  get dirty() {
+    return textEditor.value !== this._saved;
+  }
+
+  activate() {
+    textEditor.addEventListener('input', () => {
+      this._updateState();
+      this._schedule(300);
+    }, {
+      passive: true,
+    });
+
+    btnEditorRevert.addEventListener('click', () => {
+      textEditor.value = this._saved;
+      this._updateState();
+      this._showErrors([]);
+      this._reloader.reload();
+    });
+
+    formEditor.addEventListener('submit', (ev) => {
+      ev.preventDefault();
+      this._save();
+    });
+
+    elEditorErrors.addEventListener('click', (ev) => {
+      const link = ev.target.closest('[data-line]');
+
+      if (link) {
+        ev.preventDefault();
+        this._selectLine(Number(link.dataset.line));
+      }
+    });
+  }
+
+  // Take the content of an unmodified sketch after it was reloaded.
+  update(content) {
+    if (content !== null && !this.dirty) {
+      textEditor.value = content;
+      this._saved = content;
+      this._showErrors([]);
+    }
+  }
+
+  _schedule(delay) {
+    window.clearTimeout(this._timeout);
+    this._timeout = window.setTimeout(this.preview.bind(this), delay);
+  }
+
+  async preview() {
+    let errors;
+
+    try {
+      errors = await this._reloader.preview(textEditor.value);
+    } catch (err) {
+      if (err.name === 'AbortError') {
+        return;
+      }
+
+      errors = [{ message: err.message }];
+    }
+
+    this._showErrors(errors);
+  }
+
+  _showErrors(errors) {
+    textEditor.classList.toggle('is-invalid', errors.length > 0);
+
+    elEditorErrors.replaceChildren(...errors.map((e) => {
+      const item = document.createElement('li');
+
+      if (e.line) {
+        const link = document.createElement('a');
+
+        link.href = '#';
+        link.dataset.line = e.line;
+        link.textContent = `${e.line}:${e.column}: ${e.message}`;
+
+        item.appendChild(link);
+      } else {
+        item.textContent = e.message;
+      }
+
+      return item;
+    }));
+  }
+
+  _selectLine(line) {
+    const lines = textEditor.value.split('\n');
+    const start = lines.slice(0, line - 1).reduce((offset, l) => offset + l.length + 1, 0);
+
+    textEditor.focus();
+    textEditor.setSelectionRange(start, start + (lines[line - 1] ?? '').length);
+
+    const lineHeight = textEditor.scrollHeight / lines.length;
+
+    textEditor.scrollTop = Math.max(0, (line - 3) * lineHeight);
+  }
+
+  _updateState() {
+    const dirty = this.dirty;
+
+    btnEditorRevert.disabled = !dirty;
+
+    if (btnEditorSave) {
+      btnEditorSave.disabled = !dirty;
+    }
+
+    elEditorStatus.textContent = dirty ? 'Modified, not saved' : '';
+  }
+
+  async _save() {
+    const content = textEditor.value;
+
+    try {
+      const response = await fetch(formEditor.dataset.url, {
+        method: 'PUT',
+        headers: {
+          'Content-Type': 'text/plain; charset=utf-8',
+        },
+        body: content,
+      });
+
+      if (!response.ok) {
+        throw new Error((await response.text()).trim());
+      }
+    } catch (err) {
+      elEditorStatus.textContent = `Saving failed: ${err.message}`;
+      return;
+    }
+
+    this._saved = content;
+    this._updateState();
+
+    elEditorStatus.textContent = 'Saved';
+  }
+}
+
+// Generate search areas from rectangles drawn on the page.
+class SnippetTool {
+  _rect = null;
+
+  constructor() {
+    this._placeholder = textSnippet.placeholder;
+  }
+
+  activate() {
+    cbSnippetSnap.checked = settings.get(kSnippetSnapSetting, false);
+    cbSnippetSnap.addEventListener('change', (ev) => {
+      settings.set(kSnippetSnapSetting, ev.target.checked);
+    });
+
+    [divSnippetReferenceGroup, selectSnippetNode, selectSnippetFeature].forEach((el) => {
+      el.addEventListener('change', () => this.refresh(), {
+        passive: true,
+      });
+    });
+
+    btnSnippetCopy.addEventListener('click', () => {
+      navigator.clipboard.writeText(textSnippet.value);
+    });
+
+    defaultunit.observe(() => this.refresh());
+
+    this.refresh();
+  }
+
+  // Adjust the edges of a rectangle to nearby edges of document elements.
+  snap(rect, threshold) {
+    if (!cbSnippetSnap.checked) {
+      return rect;
+    }
+
+    const horizontal = [];
+    const vertical = [];
+
+    divViewer.querySelectorAll('.dossier_doc_block, .dossier_doc_line').forEach((el) => {
+      const bounds = parseBounds(JSON.parse(el.dataset.nodeBounds));
+
+      horizontal.push(bounds.left, bounds.right);
+      vertical.push(bounds.top, bounds.bottom);
+    });
+
+    const nearest = (value, candidates) => {
+      let result = value;
+      let distance = threshold;
+
+      candidates.forEach((c) => {
+        if (Math.abs(c - value) <= distance) {
+          result = c;
+          distance = Math.abs(c - value);
+        }
+      });
+
+      return result;
+    };
+
+    return {
+      left: nearest(rect.left, horizontal),
+      top: nearest(rect.top, vertical),
+      right: nearest(rect.right, horizontal),
+      bottom: nearest(rect.bottom, vertical),
+    };
+  }
+
+  select(rect) {
+    this._rect = rect;
+    this.refresh();
+  }
+
+  // Valid sketch nodes on the page.
+  _nodes() {
+    return Array.from(divViewer.querySelectorAll('.dossier_sketch_node[data-node-name]'), (el) => ({
+      name: el.dataset.nodeName,
+      bounds: parseBounds(JSON.parse(el.dataset.nodeBounds)),
+    }));
+  }
+
+  // Update the snippet, e.g. after the sketch nodes changed.
+  refresh() {
+    const relative = rbSnippetReferenceRel.checked;
+    const nodes = this._nodes();
+    const selectedNode = selectSnippetNode.value;
+
+    selectSnippetNode.replaceChildren(...nodes.map((n) =>
+      new Option(n.name, n.name, false, n.name === selectedNode)));
+    selectSnippetNode.disabled = !relative;
+    selectSnippetFeature.disabled = !relative;
+
+    let reference = null;
+
+    if (relative) {
+      const node = nodes.find((n) => n.name === selectSnippetNode.value);
+
+      if (node) {
+        reference = {
+          name: node.name,
+          feature: selectSnippetFeature.value,
+          bounds: node.bounds,
+        };
+      }
+    }
+
+    if (this._rect === null || (relative && reference === null)) {
+      textSnippet.value = '';
+      textSnippet.placeholder = relative && nodes.length === 0
+        ? 'No sketch node found on the page.'
+        : this._placeholder;
+      btnSnippetCopy.disabled = true;
+      return;
+    }
+
+    textSnippet.value = snippet.searchArea(this._rect, defaultunit.get(), reference);
+    btnSnippetCopy.disabled = false;
+  }
+}
+
+function initSnippet() {
+  const tool = new SnippetTool();
+
+  tool.activate();
+
+  return tool;
+}
+
+function initSearch() {
+  const search = new TextSearch(divViewer);
+
+  search.activate();
+
+  return search;
+}
+
+function initReload(search, snippetTool) {
+  const reloader = new PageReloader(() => {
+    search.refresh();
+    snippetTool.refresh();
+  });
+
+  if (formEditor) {
+    const editor = new SketchEditor(reloader);
+
+    editor.activate();
+    reloader.setEditor(editor);
+  }
+
+  reloader.activate();
+}
+
+const snippetTool = initSnippet();
+
 initFilter();
-initViewer();
-initSidebar();
+initViewer(snippetTool);
+initSidebar(divSidebar);
+initReload(initSearch(), snippetTool);
 
 /* vim: set sw=2 sts=2 et : */
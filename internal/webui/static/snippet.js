@@ -0,0 +1,173 @@
+// Generate sketch snippets in the Protocol Buffer text format.
+
+import * as geometry from './geometry.js';
+
+const kFractionDigits = new Map([
+  [geometry.Point.name, 1],
+  [geometry.Millimeter.name, 1],
+  [geometry.Centimeter.name, 2],
+  [geometry.Inch.name, 3],
+]);
+
+const kIndent = '  ';
+
+// Same as used by the sketch formatter.
+const kMaxLineWidth = 80;
+
+// Format a length given in points as a geometry.Length message.
+function formatLength(pt, unit) {
+  const digits = kFractionDigits.get(unit.name) ?? 2;
+  let value = Number(new geometry.Length(pt).toUnit(unit).toFixed(digits));
+
+  if (Object.is(value, -0)) {
+    value = 0;
+  }
+
+  return `{ ${unit.name}: ${value} }`;
+}
+
+// Quote a string for the text format.
+function quote(text) {
+  return '"' + text.replace(/[\\"\n\r\t]/g, (c) => ({
+    '\\': '\\\\',
+    '"': '\\"',
+    '\n': '\\n',
+    '\r': '\\r',
+    '\t': '\\t',
+  })[c]) + '"';
+}
+
+// Escape all characters with a special meaning in regular expressions and
+// allow for variable whitespace.
+function escapeRegex(text) {
+  return text
+    .trim()
+    .split(/\s+/)
+    .map((word) => word.replace(/[\\^$.|?*+()[\]{}]/g, '\\$&'))
+    .join('\\s+');
+}
+
+// Position of a node feature within bounds given in points.
+function featurePosition(bounds, feature) {
+  switch (feature) {
+  case 'TOP_RIGHT':
+    return { left: bounds.right, top: bounds.top };
+  case 'BOTTOM_LEFT':
+    return { left: bounds.left, top: bounds.bottom };
+  case 'BOTTOM_RIGHT':
+    return { left: bounds.right, top: bounds.bottom };
+  }
+
+  return { left: bounds.left, top: bounds.top };
+}
+
+function indent(lines) {
+  return lines.map((line) => kIndent + line);
+}
+
+// Write a message on a single line if it fits like the sketch formatter.
+function inlineMessage(name, fields, width) {
+  const inline = `${name} { ${fields.join(' ')} }`;
+
+  if (width + inline.length <= kMaxLineWidth) {
+    return [inline];
+  }
+
+  return [`${name} {`, ...indent(fields), '}'];
+}
+
+// Lines of a FlexRect message body for a rectangle given in points. The top
+// left vertex is either absolute or relative to a feature of another node
+// given as {name, feature, bounds}.
+function flexRectLines(rect, unit, reference) {
+  const lines = [];
+
+  if (reference) {
+    const origin = featurePosition(reference.bounds, reference.feature);
+
+    lines.push(
+      'top_left {',
+      ...indent([
+        'rel {',
+        ...indent([
+          `node: ${quote(reference.name)}`,
+          `feature: ${reference.feature}`,
+          `offset { width ${formatLength(rect.left - origin.left, unit)} ` +
+            `height ${formatLength(rect.top - origin.top, unit)} }`,
+        ]),
+        '}',
+      ]),
+      '}',
+    );
+  } else {
+    lines.push(
+      `top_left { abs { left ${formatLength(rect.left, unit)} ` +
+        `top ${formatLength(rect.top, unit)} } }`,
+    );
+  }
+
+  lines.push(
+    `width ${formatLength(rect.right - rect.left, unit)}`,
+    `height ${formatLength(rect.bottom - rect.top, unit)}`,
+  );
+
+  return lines;
+}
+
+// Generate a search area for a rectangle given in points.
+function searchArea(rect, unit, reference) {
+  return [
+    'search_areas {',
+    ...indent(flexRectLines(rect, unit, reference)),
+    '}',
+  ].join('\n');
+}
+
+// Derive a node name from text.
+function nodeName(text) {
+  const name = text
+    .toLowerCase()
+    .replace(/[^a-z0-9]+/g, '_')
+    .replace(/^_+|_+$/g, '')
+    .slice(0, 30)
+    .replace(/_+$/, '');
+
+  if (/^[a-z]/.test(name)) {
+    return name;
+  }
+
+  return name ? `node_${name}` : 'node';
+}
+
+// Propose a node matching a line of text. The search area surrounds the line
+// with the given margin in points.
+function lineTextNode(text, bounds, unit, margin) {
+  const area = {
+    left: Math.max(0, bounds.left - margin),
+    top: Math.max(0, bounds.top - margin),
+    right: bounds.right + margin,
+    bottom: bounds.bottom + margin,
+  };
+
+  return [
+    'nodes {',
+    ...indent([
+      `name: ${quote(nodeName(text))}`,
+      'search_areas {',
+      ...indent(flexRectLines(area, unit, null)),
+      '}',
+      ...inlineMessage('line_text', [
+        `regex: ${quote(`^\\s*${escapeRegex(text)}\\s*$`)}`,
+      ], kIndent.length),
+    ]),
+    '}',
+  ].join('\n');
+}
+
+export {
+  escapeRegex,
+  lineTextNode,
+  searchArea,
+};
+
+/* vim: set sw=2 sts=2 et : */
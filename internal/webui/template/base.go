@@ -6,14 +6,49 @@ type TopNavItem int
 
 const (
 	TopNavNone TopNavItem = iota
+	TopNavIndex
 	TopNavOverview
 )
 
+// NavChoice is an entry of a navigation menu.
+type NavChoice struct {
+	Text   string
+	URL    string
+	Active bool
+}
+
+// Message is a notice shown above the content.
+type Message struct {
+	Text string `json:"text"`
+
+	// Optional link to details, e.g. the location of a problem.
+	URL string `json:"url,omitempty"`
+}
+
+// activeChoiceText returns the text of the active menu entry.
+func activeChoiceText(choices []NavChoice) string {
+	for _, i := range choices {
+		if i.Active {
+			return i.Text
+		}
+	}
+
+	return ""
+}
+
 type BaseData struct {
 	HeadTitle    string
 	Scripts      []string
 	TopNavActive TopNavItem
-	Messages     []string
+	Messages     []Message
 	Sidebar      templ.Component
 	Content      templ.Component
+
+	// Overview of the current document, if any.
+	OverviewURL string
+
+	// Menus for switching between documents and sketches. Only shown with
+	// more than one entry.
+	Documents []NavChoice
+	Sketches  []NavChoice
 }
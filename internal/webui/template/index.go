@@ -0,0 +1,28 @@
+package template
+
+// IndexResultData is the result of analyzing a document using a sketch.
+type IndexResultData struct {
+	// Link to the document overview using the sketch.
+	URL string
+
+	// Empty on success.
+	Err string
+
+	Pages        int
+	ValidNodes   int
+	InvalidNodes int
+	ChecksFailed bool
+}
+
+type IndexDocumentData struct {
+	Name    string
+	Path    string
+	URL     string
+	Results []IndexResultData
+}
+
+type IndexContentData struct {
+	// Base names of the sketch files.
+	Sketches  []string
+	Documents []IndexDocumentData
+}
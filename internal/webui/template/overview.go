@@ -5,6 +5,10 @@ import (
 )
 
 type OverviewContentData struct {
+	// Index of the document and the sketch used for analyzing pages.
+	Doc    int
+	Sketch int
+
 	DocFingerprint string
 	Pages          []*dossier.Page
 }
@@ -23,10 +23,36 @@ type SketchNodeData struct {
 	ID string
 }
 
+// SketchEditorData is the content of the sketch being edited.
+type SketchEditorData struct {
+	// Base name of the sketch file.
+	Name    string
+	Content string
+
+	// Location for saving the sketch.
+	URL string
+
+	// Whether the sketch may be written back to its file.
+	AllowSave bool
+}
+
 type PageData struct {
+	// Index of the document.
+	Doc            int
 	DocFingerprint string
 	Page           *dossier.Page
 	SketchNodes    []SketchNodeData
+	Computed       []*sketch.Computed
+
+	// Nil if the editor isn't available.
+	Editor *SketchEditorData
+}
+
+// hasTrace reports whether any node has a search trace.
+func (d *PageData) hasTrace() bool {
+	return slices.ContainsFunc(d.SketchNodes, func(n SketchNodeData) bool {
+		return n.Trace() != nil
+	})
 }
 
 func (d *PageData) size() geometry.Size {
@@ -39,6 +65,7 @@ func (d PageData) widthInCssPixels() int {
 
 func (d PageData) imageData() PageImageData {
 	return PageImageData{
+		Doc:            d.Doc,
 		DocFingerprint: d.DocFingerprint,
 		Page:           d.Page,
 		Width:          d.widthInCssPixels(),
@@ -46,6 +73,17 @@ func (d PageData) imageData() PageImageData {
 	}
 }
 
+// boundsJSON encodes a rectangle using the attribute names of the geometry
+// elements.
+func boundsJSON(r geometry.Rect) string {
+	return toJSON(map[string]float64{
+		"top-pt":    r.Top.Pt(),
+		"left-pt":   r.Left.Pt(),
+		"right-pt":  r.Right.Pt(),
+		"bottom-pt": r.Bottom.Pt(),
+	})
+}
+
 func (d PageData) overlays() []pageViewerOverlayData {
 	var result []pageViewerOverlayData
 
@@ -63,12 +101,7 @@ func (d PageData) overlays() []pageViewerOverlayData {
 		o.Bounds = elem.Bounds()
 		o.ModalTarget = "#dossier_page_node_dialog_template"
 
-		o.DataAttr["node-bounds"] = toJSON(map[string]float64{
-			"top-pt":    o.Bounds.Top.Pt(),
-			"left-pt":   o.Bounds.Left.Pt(),
-			"right-pt":  o.Bounds.Right.Pt(),
-			"bottom-pt": o.Bounds.Bottom.Pt(),
-		})
+		o.DataAttr["node-bounds"] = boundsJSON(o.Bounds)
 
 		var nodeKind string
 		var className string
@@ -120,6 +153,8 @@ func (d PageData) overlays() []pageViewerOverlayData {
 		o.Bounds = node.Bounds()
 		o.Classes = append(o.Classes, "dossier_sketch_node")
 		o.Order = 100
+		o.DataAttr["node-name"] = node.Name()
+		o.DataAttr["node-bounds"] = boundsJSON(o.Bounds)
 
 		if node.ID != "" {
 			o.DataAttr["info-id"] = node.ID
@@ -10,6 +10,8 @@ import (
 )
 
 type PageImageData struct {
+	// Index of the document.
+	Doc            int
 	DocFingerprint string
 
 	Page       *dossier.Page
@@ -30,7 +32,7 @@ func (d PageImageData) imageData() ImageData {
 	}
 
 	return ImageData{
-		Src:        fmt.Sprintf("/page/%d/image?%s", p.Number(), params.Encode()),
+		Src:        fmt.Sprintf("/doc/%d/page/%d/image?%s", d.Doc, p.Number(), params.Encode()),
 		Width:      d.Width,
 		Height:     int(math.Ceil(float64(d.Width) * pageSize.Height.Pt() / pageSize.Width.Pt())),
 		ClassNames: d.ClassNames,
@@ -0,0 +1,6 @@
+package template
+
+type SketchSourceData struct {
+	Path  string
+	Lines []string
+}
@@ -0,0 +1,26 @@
+package template
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// withSketch adds the sketch to a URL path unless it's the default sketch.
+func withSketch(path string, sketch int) string {
+	if sketch == 0 {
+		return path
+	}
+
+	return path + "?" + url.Values{"sketch": {strconv.Itoa(sketch)}}.Encode()
+}
+
+// DocumentURL returns the URL of the overview of a document.
+func DocumentURL(doc, sketch int) string {
+	return withSketch(fmt.Sprintf("/doc/%d", doc), sketch)
+}
+
+// PageURL returns the URL of a page analyzed using a sketch.
+func PageURL(doc, page, sketch int) string {
+	return withSketch(fmt.Sprintf("/doc/%d/page/%d", doc, page), sketch)
+}
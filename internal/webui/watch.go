@@ -0,0 +1,161 @@
+package webui
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Changes are collected for this long before subscribers are notified. Editors
+// often write files in multiple steps.
+const watchSettleDelay = 100 * time.Millisecond
+
+// broadcaster notifies subscribers about changes. Notifications are
+// coalesced for subscribers not keeping up.
+type broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{
+		subscribers: map[chan struct{}]struct{}{},
+	}
+}
+
+// subscribe registers a new subscriber. The returned function must be called
+// to unsubscribe.
+func (b *broadcaster) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+	}
+}
+
+func (b *broadcaster) notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// fileWatcher reports modifications of the sketches and the documents. Other
+// sketch files in the same directories are watched as they may be imported.
+// Imports from other directories below the sketch root, e.g.
+// "../common/header.textproto", are not detected.
+type fileWatcher struct {
+	w             *fsnotify.Watcher
+	sketchDirs    []string
+	sketchExts    []string
+	documentPaths []string
+}
+
+func newFileWatcher(sketchPaths, documentPaths []string) (*fileWatcher, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	fw := &fileWatcher{
+		w:          w,
+		sketchExts: []string{".textproto", ".txtpb", ".json", ".yaml", ".yml"},
+	}
+
+	var dirs []string
+
+	for _, path := range sketchPaths {
+		dir := filepath.Clean(filepath.Dir(path))
+
+		fw.sketchDirs = append(fw.sketchDirs, dir)
+		fw.sketchExts = append(fw.sketchExts, filepath.Ext(path))
+		dirs = append(dirs, dir)
+	}
+
+	for _, path := range documentPaths {
+		path = filepath.Clean(path)
+
+		fw.documentPaths = append(fw.documentPaths, path)
+		dirs = append(dirs, filepath.Dir(path))
+	}
+
+	slices.Sort(dirs)
+
+	// Directories are watched instead of files to also detect files being
+	// replaced, e.g. by editors writing to a temporary file first.
+	for _, dir := range slices.Compact(dirs) {
+		if err := w.Add(dir); err != nil {
+			w.Close()
+			return nil, err
+		}
+	}
+
+	return fw, nil
+}
+
+// relevant reports whether an event concerns a sketch or a document.
+func (fw *fileWatcher) relevant(ev fsnotify.Event) bool {
+	if ev.Op == fsnotify.Chmod {
+		return false
+	}
+
+	name := filepath.Clean(ev.Name)
+
+	if slices.Contains(fw.documentPaths, name) {
+		return true
+	}
+
+	// Editors may create temporary files next to the sketch.
+	return slices.Contains(fw.sketchDirs, filepath.Dir(name)) &&
+		!strings.HasPrefix(filepath.Base(name), ".") &&
+		slices.Contains(fw.sketchExts, filepath.Ext(name))
+}
+
+// run calls the change function after relevant files were modified until the
+// context is cancelled.
+func (fw *fileWatcher) run(ctx context.Context, changed func()) error {
+	defer fw.w.Close()
+
+	settle := time.NewTimer(0)
+	<-settle.C
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err := <-fw.w.Errors:
+			if !errors.Is(err, fsnotify.ErrEventOverflow) {
+				return err
+			}
+
+			// Events were lost
+			settle.Reset(watchSettleDelay)
+
+		case ev := <-fw.w.Events:
+			if fw.relevant(ev) {
+				settle.Reset(watchSettleDelay)
+			}
+
+		case <-settle.C:
+			changed()
+		}
+	}
+}
@@ -46,7 +46,7 @@ func (l *fakeLine) Text() string {
 }
 
 func (l *fakeLine) RangeBounds(start, end int) geometry.Rect {
-	return geometry.Rect{}
+	return l.bounds
 }
 
 type lineVisitor []string
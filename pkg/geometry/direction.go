@@ -10,3 +10,33 @@ const (
 	TopToBottom VerticalDirection = iota
 	BottomToTop
 )
+
+// Direction is one of the four directions on a page.
+type Direction int
+
+const (
+	Up Direction = iota
+	Down
+	Left
+	Right
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	case Left:
+		return "left"
+	case Right:
+		return "right"
+	}
+
+	return "unknown"
+}
+
+// Horizontal reports whether the direction is left or right.
+func (d Direction) Horizontal() bool {
+	return d == Left || d == Right
+}
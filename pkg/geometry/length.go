@@ -6,6 +6,7 @@ import (
 	"math"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/hansmi/dossier/proto/geometrypb"
 	"golang.org/x/exp/maps"
@@ -104,11 +105,33 @@ func LengthFromProto(l *geometrypb.Length) (Length, error) {
 	case *geometrypb.Length_Pt:
 		return Length(v.Pt) * Pt, nil
 
+	case *geometrypb.Length_Param:
+		return 0, fmt.Errorf("length parameter %q is not resolved", v.Param)
+
 	default:
 		return 0, fmt.Errorf("unknown length unit %T", v)
 	}
 }
 
+// ParseLength parses a number followed by a unit name, e.g. "12.5mm" or
+// "-1 in".
+func ParseLength(s string) (Length, error) {
+	s = strings.TrimSpace(s)
+
+	for unit, info := range knownLengthUnits {
+		if value, ok := strings.CutSuffix(s, info.name); ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid length %q: %w", s, err)
+			}
+
+			return Length(f) * unit, nil
+		}
+	}
+
+	return 0, fmt.Errorf("length %q lacks a supported unit", s)
+}
+
 // Name returns the name of the length unit. Panics if the length is not
 // a base value.
 func (l Length) Name() string {
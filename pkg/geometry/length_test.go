@@ -58,6 +58,13 @@ func TestLengthFromProto(t *testing.T) {
 			},
 			want: -1,
 		},
+		{
+			name: "unresolved parameter",
+			input: &geometrypb.Length{
+				Value: &geometrypb.Length_Param{Param: "offset"},
+			},
+			wantErr: cmpopts.AnyError,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			got, err := LengthFromProto(tc.input)
@@ -118,6 +125,39 @@ func TestLengthString(t *testing.T) {
 	}
 }
 
+func TestParseLength(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		want    Length
+		wantErr error
+	}{
+		{input: "0pt"},
+		{input: "12.5mm", want: 12.5 * Mm},
+		{input: " -3 cm ", want: -3 * Cm},
+		{input: "1in", want: Inch},
+		{input: "1e1pt", want: 10 * Pt},
+		{input: "", wantErr: cmpopts.AnyError},
+		{input: "12", wantErr: cmpopts.AnyError},
+		{input: "cm", wantErr: cmpopts.AnyError},
+		{input: "1.2.3mm", wantErr: cmpopts.AnyError},
+		{input: "12km", wantErr: cmpopts.AnyError},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseLength(tc.input)
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(tc.want, got, EquateLength()); diff != "" {
+					t.Errorf("ParseLength() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
 func TestLengthAsProto(t *testing.T) {
 	for _, tc := range []struct {
 		name  string
@@ -0,0 +1,15 @@
+package renderformat
+
+import "io"
+
+type SVG struct {
+	Width  int
+	Height int
+	Output io.Writer
+}
+
+var _ Renderer = (*SVG)(nil)
+
+func (r *SVG) String() string {
+	return "SVG"
+}
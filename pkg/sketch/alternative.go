@@ -0,0 +1,107 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hansmi/dossier/internal/flexrect"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+// nodeAlternative is a fallback for finding a node, pairing search areas with
+// a matcher.
+type nodeAlternative struct {
+	name        string
+	searchAreas []*flexrect.FlexRect
+	adjacent    []*flexrect.AdjacentArea
+	locator     sketchNodeLocator
+}
+
+func nodeAlternativeFromProto(pb *sketchpb.Node_Alternative, norm *normalizer) (*nodeAlternative, error) {
+	var err error
+
+	alt := &nodeAlternative{
+		name: pb.GetName(),
+	}
+
+	if alt.locator, err = newNodeLocatorFromProto(pb.GetMatcher(), norm); err != nil {
+		return nil, err
+	}
+
+	for _, pbArea := range pb.GetSearchAreas() {
+		area, err := flexrect.FromProto(pbArea)
+		if err != nil {
+			return nil, err
+		}
+
+		alt.searchAreas = append(alt.searchAreas, area)
+	}
+
+	for _, pbArea := range pb.GetAdjacentAreas() {
+		area, err := flexrect.AdjacentAreaFromProto(pbArea)
+		if err != nil {
+			return nil, err
+		}
+
+		alt.adjacent = append(alt.adjacent, area)
+	}
+
+	if len(alt.searchAreas) < 1 && len(alt.adjacent) < 1 {
+		return nil, fmt.Errorf("%w: alternative requires at least one search area", sketcherror.ErrIncompleteConfig)
+	}
+
+	return alt, nil
+}
+
+func (a *nodeAlternative) requiredNodeFeatures() []flexrect.NodeFeature {
+	var result []flexrect.NodeFeature
+
+	for _, area := range a.searchAreas {
+		result = append(result, area.RequiredNodeFeatures()...)
+	}
+
+	for _, area := range a.adjacent {
+		result = append(result, area.RequiredNodeFeatures()...)
+	}
+
+	return result
+}
+
+// resolveSearchAreas determines the bounds of search and adjacent areas.
+// Areas referencing nodes of unknown position are skipped and recorded in the
+// trace.
+func resolveSearchAreas(cb sketchNodeSearchCallbacks, trace *NodeTrace, searchAreas []*flexrect.FlexRect, adjacent []*flexrect.AdjacentArea) ([]geometry.Rect, error) {
+	resolvers := make([]func(sketchNodeSearchCallbacks) (geometry.Rect, error), 0, len(searchAreas)+len(adjacent))
+
+	for _, area := range searchAreas {
+		resolvers = append(resolvers, func(cb sketchNodeSearchCallbacks) (geometry.Rect, error) {
+			return area.Resolve(cb)
+		})
+	}
+
+	for _, area := range adjacent {
+		resolvers = append(resolvers, func(cb sketchNodeSearchCallbacks) (geometry.Rect, error) {
+			return area.Resolve(cb)
+		})
+	}
+
+	var result []geometry.Rect
+
+	for _, resolve := range resolvers {
+		bounds, err := resolve(cb)
+		if err != nil {
+			if errors.Is(err, ErrNodePositionUnknown) {
+				trace.unresolved(err)
+				continue
+			}
+
+			return nil, err
+		}
+
+		result = append(result, bounds)
+	}
+
+	return result, nil
+}
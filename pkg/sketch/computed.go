@@ -0,0 +1,257 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/hansmi/dossier/internal/expr"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/proto/reportpb"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"go.uber.org/multierr"
+)
+
+// ComputedStatus is the outcome of evaluating a computed node.
+type ComputedStatus int
+
+const (
+	// A referenced node was not found.
+	ComputedSkipped ComputedStatus = iota
+
+	// The expression produced true or a non-bool value.
+	ComputedPassed
+
+	// The expression produced false.
+	ComputedFailed
+
+	// Evaluating the expression failed.
+	ComputedError
+)
+
+func (s ComputedStatus) asProto() reportpb.Computed_Status {
+	switch s {
+	case ComputedPassed:
+		return reportpb.Computed_PASSED
+	case ComputedFailed:
+		return reportpb.Computed_FAILED
+	case ComputedError:
+		return reportpb.Computed_ERROR
+	}
+
+	return reportpb.Computed_SKIPPED
+}
+
+func (s ComputedStatus) String() string {
+	return strings.ToLower(s.asProto().String())
+}
+
+// computedReference is a reference from an expression to a node.
+type computedReference struct {
+	node string
+
+	// Name of a capture group. Empty for the node text.
+	group string
+
+	// Whether the reference is to another computed node.
+	computed bool
+}
+
+type computedNode struct {
+	name string
+	expr *expr.Expr
+	refs map[string]computedReference
+	tags []string
+}
+
+// computedNodeFromProto builds a computed node. References are resolved
+// against the names of matcher nodes and previously defined computed nodes.
+func computedNodeFromProto(pb *sketchpb.ComputedNode, nodes, computed map[string]bool) (*computedNode, error) {
+	var err error
+
+	c := &computedNode{
+		name: pb.GetName(),
+		refs: map[string]computedReference{},
+	}
+
+	if c.name == "" {
+		return nil, fmt.Errorf("%w: computed node requires name", sketcherror.ErrIncompleteConfig)
+	}
+
+	if c.tags, err = validateTags(pb.GetTags()); err != nil {
+		return nil, multierr.Combine(sketcherror.ErrBadConfig, err)
+	}
+
+	if pb.GetExpr() == "" {
+		return nil, fmt.Errorf("%w: expression required", sketcherror.ErrIncompleteConfig)
+	}
+
+	if c.expr, err = expr.Parse(pb.GetExpr()); err != nil {
+		return nil, multierr.Combine(sketcherror.ErrBadConfig, err)
+	}
+
+	for _, path := range c.expr.References() {
+		key := strings.Join(path, ".")
+
+		switch parent := strings.Join(path[:len(path)-1], "."); {
+		case nodes[key]:
+			c.refs[key] = computedReference{node: key}
+
+		case computed[key]:
+			c.refs[key] = computedReference{node: key, computed: true}
+
+		case len(path) > 1 && nodes[parent]:
+			c.refs[key] = computedReference{node: parent, group: path[len(path)-1]}
+
+		default:
+			return nil, fmt.Errorf("%w: unknown reference %q", sketcherror.ErrBadConfig, key)
+		}
+	}
+
+	return c, nil
+}
+
+// computedEnv resolves references of a computed node on a page.
+type computedEnv struct {
+	c    *computedNode
+	page *PageReport
+}
+
+func (e *computedEnv) Lookup(path []string) (expr.Value, error) {
+	ref := e.c.refs[strings.Join(path, ".")]
+
+	if ref.computed {
+		other := e.page.ComputedByName(ref.node)
+
+		switch {
+		case other == nil || other.status == ComputedSkipped:
+			return nil, fmt.Errorf("%w: computed node %q skipped", expr.ErrUnavailable, ref.node)
+
+		case other.status == ComputedError:
+			return nil, fmt.Errorf("computed node %q: %w", ref.node, other.err)
+		}
+
+		return other.value, nil
+	}
+
+	n := e.page.NodeByName(ref.node)
+	if n == nil || !n.Valid() {
+		return nil, fmt.Errorf("%w: node %q not found", expr.ErrUnavailable, ref.node)
+	}
+
+	if ref.group == "" {
+		return n.Text(), nil
+	}
+
+	var g *TextMatchGroup
+
+	if tm := n.TextMatch(); tm != nil {
+		g = tm.Named(ref.group)
+	}
+
+	if g == nil {
+		return nil, fmt.Errorf("node %q has no group %q", ref.node, ref.group)
+	}
+
+	if g.Start < 0 {
+		return nil, fmt.Errorf("%w: group %q of node %q not matched", expr.ErrUnavailable, ref.group, ref.node)
+	}
+
+	return g.Text, nil
+}
+
+func (c *computedNode) evaluate(page *PageReport) *Computed {
+	result := &Computed{c: c}
+
+	value, err := c.expr.Eval(&computedEnv{c: c, page: page})
+	check, isCheck := value.(bool)
+
+	switch {
+	case errors.Is(err, expr.ErrUnavailable):
+		result.status = ComputedSkipped
+		result.err = err
+
+	case err != nil:
+		result.status = ComputedError
+		result.err = err
+
+	case isCheck && !check:
+		result.status = ComputedFailed
+		result.value = value
+
+	default:
+		result.status = ComputedPassed
+		result.value = value
+	}
+
+	return result
+}
+
+// Computed is the result of evaluating a computed node.
+type Computed struct {
+	c      *computedNode
+	status ComputedStatus
+	value  expr.Value
+	err    error
+}
+
+func (c *Computed) Name() string {
+	return c.c.name
+}
+
+func (c *Computed) Tags() []string {
+	return c.c.tags
+}
+
+func (c *Computed) Status() ComputedStatus {
+	return c.status
+}
+
+// Value returns the result of the expression as a bool, *big.Rat (numbers),
+// string (texts) or time.Time (dates). Nil is returned if the evaluation was
+// skipped or failed.
+func (c *Computed) Value() any {
+	return c.value
+}
+
+// Text returns the result of the expression formatted as text. Dates use the
+// "YYYY-MM-DD" format.
+func (c *Computed) Text() string {
+	if c.value == nil {
+		return ""
+	}
+
+	return expr.Format(c.value)
+}
+
+// Err returns the reason for a skipped or failed evaluation.
+func (c *Computed) Err() error {
+	return c.err
+}
+
+func (c *Computed) AsProto() *reportpb.Computed {
+	pb := &reportpb.Computed{
+		Name:   c.c.name,
+		Status: c.status.asProto(),
+		Tags:   c.c.tags,
+	}
+
+	switch v := c.value.(type) {
+	case bool:
+		pb.Value = &reportpb.Computed_BoolValue{BoolValue: v}
+	case *big.Rat:
+		pb.Value = &reportpb.Computed_NumberValue{NumberValue: expr.FormatNumber(v)}
+	case string:
+		pb.Value = &reportpb.Computed_TextValue{TextValue: v}
+	case time.Time:
+		pb.Value = &reportpb.Computed_DateValue{DateValue: v.Format(expr.DateLayout)}
+	}
+
+	if c.err != nil {
+		pb.Error = c.err.Error()
+	}
+
+	return pb
+}
@@ -0,0 +1,179 @@
+package sketch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/pagerange"
+	"github.com/hansmi/dossier/proto/reportpb"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const computedTestNode = `
+nodes: {
+  name: "total"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 10 }
+    height: { cm: 10 }
+  }
+  line_text: { regex: "^(?P<amount>\\d+)$" }
+}
+`
+
+func TestComputedConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		sketch  string
+		wantErr error
+	}{
+		{
+			name: "node text and group",
+			sketch: computedTestNode + `
+computed: { name: "a" expr: "total > 0" }
+computed: { name: "b" expr: "total.amount * 2" }
+`,
+		},
+		{
+			name: "earlier computed node",
+			sketch: computedTestNode + `
+computed: { name: "a" expr: "total.amount * 2" }
+computed: { name: "b" expr: "a > 10" }
+`,
+		},
+		{
+			name:    "missing name",
+			sketch:  `computed: { expr: "1" }`,
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name:    "missing expression",
+			sketch:  `computed: { name: "a" }`,
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name:    "parse error",
+			sketch:  `computed: { name: "a" expr: "1 +" }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "unknown reference",
+			sketch:  `computed: { name: "a" expr: "total.amount > 0" }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "later computed node",
+			sketch: `
+computed: { name: "a" expr: "b > 0" }
+computed: { name: "b" expr: "1" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "self reference",
+			sketch: `
+computed: { name: "a" expr: "a > 0" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "same name as node",
+			sketch:  computedTestNode + `computed: { name: "total" expr: "1" }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "duplicate name",
+			sketch: `
+computed: { name: "a" expr: "1" }
+computed: { name: "a" expr: "2" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "bad tag",
+			sketch:  `computed: { name: "a" expr: "1" tags: "" }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := CompileFromTextprotoString(tc.sketch)
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestComputedEvaluate(t *testing.T) {
+	s, err := CompileFromTextprotoString(computedTestNode + `
+computed: { name: "sum" expr: "1.5 + 2" tags: "value" }
+computed: { name: "check" expr: "sum == 3.5" }
+computed: { name: "failed" expr: "sum > 10" }
+computed: { name: "error" expr: "sum / 0" }
+computed: { name: "skipped" expr: "total.amount > 0" }
+computed: { name: "chained" expr: "skipped || true" }
+computed: { name: "guarded" expr: "!has(total) ? \"none\" : total" }
+`)
+	if err != nil {
+		t.Fatalf("CompileFromTextprotoString() failed: %v", err)
+	}
+
+	report, err := s.AnalyzeDocument(context.Background(), readTestDocument(t, "corners.xml"), pagerange.All)
+	if err != nil {
+		t.Fatalf("AnalyzeDocument() failed: %v", err)
+	}
+
+	if !report.ChecksFailed() {
+		t.Errorf("ChecksFailed() returned false")
+	}
+
+	var got []*reportpb.Computed
+
+	for _, c := range report.Pages()[0].Computed() {
+		got = append(got, c.AsProto())
+	}
+
+	want := []*reportpb.Computed{
+		{
+			Name:   "sum",
+			Status: reportpb.Computed_PASSED,
+			Value:  &reportpb.Computed_NumberValue{NumberValue: "3.5"},
+			Tags:   []string{"value"},
+		},
+		{
+			Name:   "check",
+			Status: reportpb.Computed_PASSED,
+			Value:  &reportpb.Computed_BoolValue{BoolValue: true},
+		},
+		{
+			Name:   "failed",
+			Status: reportpb.Computed_FAILED,
+			Value:  &reportpb.Computed_BoolValue{BoolValue: false},
+		},
+		{
+			Name:   "error",
+			Status: reportpb.Computed_ERROR,
+		},
+		{
+			Name:   "skipped",
+			Status: reportpb.Computed_SKIPPED,
+		},
+		{
+			Name:   "chained",
+			Status: reportpb.Computed_SKIPPED,
+		},
+		{
+			Name:   "guarded",
+			Status: reportpb.Computed_PASSED,
+			Value:  &reportpb.Computed_TextValue{TextValue: "none"},
+		},
+	}
+
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&reportpb.Computed{}, "error")); diff != "" {
+		t.Errorf("Computed diff (-want +got):\n%s", diff)
+	}
+}
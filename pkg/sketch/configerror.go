@@ -0,0 +1,203 @@
+package sketch
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/internal/textproto"
+	"go.uber.org/multierr"
+)
+
+// ConfigError is a problem with a sketch configuration. The position refers
+// to the offending value if the configuration was read from its text format.
+type ConfigError struct {
+	Pos Position
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	if pos := e.Pos.String(); pos != "" {
+		return pos + ": " + e.Err.Error()
+	}
+
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors lists all problems found while compiling a sketch. Use
+// [errors.As] to retrieve the list from an error returned by the compile
+// functions.
+type ConfigErrors []*ConfigError
+
+func (e ConfigErrors) Error() string {
+	var sb strings.Builder
+
+	for idx, i := range e {
+		if idx > 0 {
+			sb.WriteByte('\n')
+		}
+
+		sb.WriteString(i.Error())
+	}
+
+	return sb.String()
+}
+
+func (e ConfigErrors) Unwrap() []error {
+	result := make([]error, len(e))
+
+	for idx, i := range e {
+		result[idx] = i
+	}
+
+	return result
+}
+
+// asConfigErrors wraps a single configuration error in a list. Other errors
+// are returned unchanged.
+func asConfigErrors(err error) error {
+	if ce, ok := err.(*ConfigError); ok {
+		return ConfigErrors{ce}
+	}
+
+	return err
+}
+
+// pathError annotates an error with the field of the offending value relative
+// to the enclosing message. The error message is left unchanged.
+type pathError struct {
+	elem textproto.PathElem
+	err  error
+}
+
+func (e *pathError) Error() string {
+	return e.err.Error()
+}
+
+func (e *pathError) Unwrap() error {
+	return e.err
+}
+
+// atPath annotates an error with the name and index of the field causing it.
+// Nil errors are returned as-is.
+func atPath(err error, name string, index int) error {
+	if err == nil {
+		return nil
+	}
+
+	return &pathError{
+		elem: textproto.PathElem{Name: name, Index: index},
+		err:  err,
+	}
+}
+
+// errorPath collects the path annotations of an error, outermost first.
+func errorPath(err error) textproto.Path {
+	var path textproto.Path
+
+	for {
+		var pe *pathError
+
+		if !errors.As(err, &pe) {
+			return path
+		}
+
+		path = append(path, pe.elem)
+		err = pe.err
+	}
+}
+
+// sourceFile is a sketch file parsed for the purpose of locating values.
+type sourceFile struct {
+	name string
+
+	// Nil if the content couldn't be parsed.
+	root locator
+}
+
+// newSourceFile parses a sketch file in the text format.
+func newSourceFile(name string, content []byte) *sourceFile {
+	f := &sourceFile{name: name}
+
+	if root, err := textproto.Parse(content); err == nil {
+		f.root = root
+	}
+
+	return f
+}
+
+// sourceRef refers to a message within a sketch file. The zero value refers
+// to an unknown location.
+type sourceRef struct {
+	file *sourceFile
+	path textproto.Path
+}
+
+// position determines the location of a value relative to the referenced
+// message.
+func (r sourceRef) position(path textproto.Path) Position {
+	if r.file == nil {
+		return Position{}
+	}
+
+	result := Position{File: r.file.name}
+
+	if r.file.root != nil {
+		pos, _ := r.file.root.Locate(slices.Concat(r.path, path))
+
+		result.Line = pos.Line
+		result.Column = pos.Column
+	}
+
+	return result
+}
+
+// collectConfigErrors converts all problems combined in an error to
+// [ConfigErrors] positioned using the locate function. Errors unrelated to the
+// configuration, e.g. from reading a file, are returned unchanged instead.
+func collectConfigErrors(err error, locate func(textproto.Path) Position) error {
+	var result ConfigErrors
+
+	for _, err := range multierr.Errors(err) {
+		var errs ConfigErrors
+
+		if errors.As(err, &errs) {
+			result = append(result, errs...)
+			continue
+		}
+
+		ce, ok := newConfigError(err, locate).(*ConfigError)
+		if !ok {
+			return err
+		}
+
+		result = append(result, ce)
+	}
+
+	return result
+}
+
+// newConfigError converts configuration errors annotated with a path to
+// a [ConfigError] positioned using the locate function. Errors already
+// carrying a position and other errors are returned unchanged.
+func newConfigError(err error, locate func(textproto.Path) Position) error {
+	var ce *ConfigError
+
+	if errors.As(err, &ce) {
+		return ce
+	}
+
+	if !(errors.Is(err, sketcherror.ErrBadConfig) || errors.Is(err, sketcherror.ErrIncompleteConfig)) {
+		return err
+	}
+
+	return &ConfigError{
+		Pos: locate(errorPath(err)),
+		Err: err,
+	}
+}
@@ -0,0 +1,265 @@
+package sketch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/internal/sketcherror"
+)
+
+// configErrorPositions extracts the positions of all configuration errors.
+func configErrorPositions(t *testing.T, err error) []Position {
+	t.Helper()
+
+	var errs ConfigErrors
+
+	if !errors.As(err, &errs) {
+		t.Fatalf("Error is not a list of configuration errors: %v", err)
+	}
+
+	var result []Position
+
+	for _, i := range errs {
+		result = append(result, i.Pos)
+	}
+
+	return result
+}
+
+func TestCompileFromTextprotoErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		want    []Position
+		wantErr error
+	}{
+		{
+			name: "node without area",
+			input: `
+nodes {
+  name: "a"
+  line_text {}
+}
+`,
+			want:    []Position{{Line: 2, Column: 1}},
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "multiple problems",
+			input: `nodes {
+  name: "a"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 1 }
+    height { cm: 1 }
+  }
+  search_areas {
+    top_left { abs { left {} top {} } }
+    left { abs { cm: 1 } }
+    width { cm: 1 }
+  }
+  line_text {}
+}
+nodes {
+  name: "b"
+  adjacent_areas { node: "a" direction: DOWN }
+  adjacent_areas { direction: DOWN }
+  line_text {}
+}
+computed { name: "c" expr: "a.value +" }
+`,
+			want: []Position{
+				{Line: 8, Column: 3},
+				{Line: 18, Column: 3},
+				{Line: 21, Column: 1},
+			},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "multiple problems in node",
+			input: `
+nodes {
+  name: "a"
+  tags: ""
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 1 }
+    height { cm: 1 }
+  }
+  search_areas {
+    top_left { abs { left {} top {} } }
+    left { abs { cm: 1 } }
+    width { cm: 1 }
+  }
+  line_text { regex: "(" }
+}
+`,
+			want: []Position{
+				{Line: 4, Column: 3},
+				{Line: 15, Column: 3},
+				{Line: 10, Column: 3},
+			},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "unknown reference",
+			input: `
+nodes {
+  name: "a"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 1 }
+    height { cm: 1 }
+  }
+  line_text {}
+}
+nodes {
+  name: "b"
+  adjacent_areas { node: "missing" direction: DOWN }
+  line_text {}
+}
+`,
+			want:    []Position{{Line: 11, Column: 1}},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "duplicate parameter",
+			input: `
+params { name: "p" string_value: "" }
+params { name: "p" string_value: "" }
+`,
+			want:    []Position{{Line: 3, Column: 1}},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "multiple loader problems",
+			input: `
+templates { name: "t" }
+templates { name: "t" }
+templates {}
+nodes { name: "a" template: "missing" }
+nodes { name: "b" template: "other" }
+params { name: "p" }
+`,
+			want: []Position{
+				{Line: 7, Column: 1},
+				{Line: 3, Column: 1},
+				{Line: 4, Column: 1},
+				{Line: 5, Column: 1},
+				{Line: 6, Column: 1},
+			},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "unknown template",
+			input: `
+nodes { name: "a" template: "missing" }
+`,
+			want:    []Position{{Line: 2, Column: 1}},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := CompileFromTextprotoString(tc.input)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("CompileFromTextprotoString() failed with %v, want %v", err, tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.want, configErrorPositions(t, err)); diff != "" {
+				t.Errorf("Position diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompileFileErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "common/lib.textproto" namespace: "lib" }
+imports { path: "missing.textproto" }
+
+nodes {
+  name: "a"
+  line_text {}
+}
+`)},
+		"common/lib.textproto": &fstest.MapFile{Data: []byte(`
+nodes {
+  name: "b"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 1 }
+    height { cm: 1 }
+  }
+  must_not_match { block_text {} }
+  line_text {}
+}
+`)},
+		"lib.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "common/lib.textproto" namespace: "lib" }
+`)},
+		"cycle.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "cycle.textproto" namespace: "self" }
+`)},
+	}
+
+	for _, tc := range []struct {
+		name string
+		file string
+		want []Position
+	}{
+		{
+			name: "import without namespace",
+			file: "main.textproto",
+			want: []Position{{File: "main.textproto", Line: 3, Column: 1}},
+		},
+		{
+			name: "imported node",
+			file: "lib.textproto",
+			want: []Position{{File: "common/lib.textproto", Line: 9, Column: 3}},
+		},
+		{
+			name: "cycle",
+			file: "cycle.textproto",
+			want: []Position{{File: "cycle.textproto", Line: 2, Column: 1}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := CompileFile(fsys, tc.file)
+
+			if diff := cmp.Diff(tc.want, configErrorPositions(t, err)); diff != "" {
+				t.Errorf("Position diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	_, err := CompileFile(fsys, "lib.textproto")
+
+	if want := "common/lib.textproto:9:3: "; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("CompileFile() failed with %q, want prefix %q", err, want)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	err := ConfigErrors{
+		{Pos: Position{File: "a.textproto", Line: 1, Column: 2}, Err: sketcherror.ErrBadConfig},
+		{Pos: Position{Line: 3, Column: 4}, Err: sketcherror.ErrIncompleteConfig},
+		{Err: errors.New("test")},
+	}
+
+	want := "a.textproto:1:2: bad configuration\n3:4: incomplete configuration\ntest"
+
+	if got := err.Error(); got != want {
+		t.Errorf("Error() returned %q, want %q", got, want)
+	}
+
+	for _, target := range []error{sketcherror.ErrBadConfig, sketcherror.ErrIncompleteConfig} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(%v) returned false", target)
+		}
+	}
+}
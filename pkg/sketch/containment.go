@@ -0,0 +1,220 @@
+package sketch
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+// containment decides whether content is located within a search area. The
+// zero value requires content to be fully contained.
+type containment struct {
+	mode       sketchpb.Containment_Mode
+	minOverlap float64
+	clipText   bool
+}
+
+func containmentFromProto(pb *sketchpb.Containment) (containment, error) {
+	c := containment{
+		mode:       pb.GetMode(),
+		minOverlap: pb.GetMinOverlap(),
+		clipText:   pb.GetClipText(),
+	}
+
+	if _, ok := sketchpb.Containment_Mode_name[int32(c.mode)]; !ok {
+		return containment{}, fmt.Errorf("%w: unsupported containment mode %v", sketcherror.ErrBadConfig, c.mode)
+	}
+
+	if !(c.minOverlap >= 0 && c.minOverlap <= 1) {
+		return containment{}, fmt.Errorf("%w: containment overlap must be in range [0, 1], got %v",
+			sketcherror.ErrBadConfig, c.minOverlap)
+	}
+
+	if c.minOverlap > 0 && c.mode != sketchpb.Containment_INTERSECTING {
+		return containment{}, fmt.Errorf("%w: containment overlap requires %s mode",
+			sketcherror.ErrBadConfig, sketchpb.Containment_INTERSECTING)
+	}
+
+	if c.clipText && c.mode == sketchpb.Containment_CONTAINED {
+		return containment{}, fmt.Errorf("%w: text clipping is not supported in %s mode",
+			sketcherror.ErrBadConfig, c.mode)
+	}
+
+	return c, nil
+}
+
+// intersectRects returns the common area of two rectangles. The returned flag
+// is false if the rectangles don't touch.
+func intersectRects(a, b geometry.Rect) (geometry.Rect, bool) {
+	r := geometry.Rect{
+		Left:   a.Left.Max(b.Left),
+		Top:    a.Top.Max(b.Top),
+		Right:  a.Right.Min(b.Right),
+		Bottom: a.Bottom.Min(b.Bottom),
+	}
+
+	return r, r.Left <= r.Right && r.Top <= r.Bottom
+}
+
+// pointInside reports whether the point is located within the rectangle,
+// including its edges.
+func pointInside(p geometry.Point, r geometry.Rect) bool {
+	return r.Left <= p.Left && p.Left <= r.Right && r.Top <= p.Top && p.Top <= r.Bottom
+}
+
+// overlapRatio returns the fraction of the bounds' area located within the
+// search area. Degenerate bounds touching the area count as fully inside.
+func overlapRatio(area, bounds geometry.Rect) float64 {
+	common, ok := intersectRects(area, bounds)
+	if !ok {
+		return 0
+	}
+
+	if bounds.IsEmpty() {
+		return 1
+	}
+
+	return (common.Width().Pt() * common.Height().Pt()) / (bounds.Width().Pt() * bounds.Height().Pt())
+}
+
+// accepts reports whether content with the given bounds is located within the
+// search area.
+func (c containment) accepts(area, bounds geometry.Rect) bool {
+	switch c.mode {
+	case sketchpb.Containment_INTERSECTING:
+		ratio := overlapRatio(area, bounds)
+
+		return ratio > 0 && ratio >= c.minOverlap
+
+	case sketchpb.Containment_CENTER:
+		return pointInside(bounds.Center(), area)
+	}
+
+	return area.Contains(bounds)
+}
+
+// clipSegment is a contiguous range of the original text retained by
+// clipping.
+type clipSegment struct {
+	// Offset in the clipped text.
+	offset int
+
+	// Range in the original text.
+	start, end int
+}
+
+// clippedText is a text element restricted to the characters located within
+// an area.
+type clippedText struct {
+	orig     content.TextElement
+	text     string
+	bounds   geometry.Rect
+	segments []clipSegment
+}
+
+var _ content.TextElement = (*clippedText)(nil)
+
+// clipTextElement returns the characters of a text element whose center point
+// is located within the area. Line breaks are kept between retained
+// characters. Nil is returned if no character is inside the area.
+func clipTextElement(elem content.TextElement, area geometry.Rect) *clippedText {
+	var buf strings.Builder
+	var pendingNewline bool
+
+	c := &clippedText{
+		orig: elem,
+	}
+
+	text := elem.Text()
+
+	for pos, r := range text {
+		size := utf8.RuneLen(r)
+
+		if r == '\n' {
+			pendingNewline = buf.Len() > 0
+			continue
+		}
+
+		charBounds := elem.RangeBounds(pos, pos+size)
+
+		if !pointInside(charBounds.Center(), area) {
+			continue
+		}
+
+		if pendingNewline {
+			pendingNewline = false
+
+			// Newlines are part of the clipped text, but not of any segment.
+			buf.WriteByte('\n')
+		}
+
+		if len(c.segments) > 0 && c.segments[len(c.segments)-1].end == pos {
+			c.segments[len(c.segments)-1].end += size
+			c.bounds = c.bounds.Union(charBounds)
+		} else {
+			if len(c.segments) == 0 {
+				c.bounds = charBounds
+			} else {
+				c.bounds = c.bounds.Union(charBounds)
+			}
+
+			c.segments = append(c.segments, clipSegment{
+				offset: buf.Len(),
+				start:  pos,
+				end:    pos + size,
+			})
+		}
+
+		buf.WriteString(text[pos : pos+size])
+	}
+
+	if len(c.segments) == 0 {
+		return nil
+	}
+
+	c.text = buf.String()
+
+	return c
+}
+
+func (c *clippedText) Bounds() geometry.Rect {
+	return c.bounds
+}
+
+func (c *clippedText) Text() string {
+	return c.text
+}
+
+func (c *clippedText) RangeBounds(start, end int) geometry.Rect {
+	if start < 0 || end < start || end > len(c.text) {
+		panic(fmt.Sprintf("range %d-%d is not valid", start, end))
+	}
+
+	var started bool
+	var bounds geometry.Rect
+
+	for _, seg := range c.segments {
+		first := max(start, seg.offset)
+		last := min(end, seg.offset+(seg.end-seg.start))
+
+		if first >= last {
+			continue
+		}
+
+		rbounds := c.orig.RangeBounds(seg.start+(first-seg.offset), seg.start+(last-seg.offset))
+
+		if started {
+			bounds = bounds.Union(rbounds)
+		} else {
+			started = true
+			bounds = rbounds
+		}
+	}
+
+	return bounds
+}
@@ -0,0 +1,264 @@
+package sketch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+func TestContainmentFromProto(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		wantErr error
+		want    containment
+	}{
+		{name: "empty"},
+		{
+			name:  "intersecting",
+			input: `mode: INTERSECTING min_overlap: 0.25 clip_text: true`,
+			want: containment{
+				mode:       sketchpb.Containment_INTERSECTING,
+				minOverlap: 0.25,
+				clipText:   true,
+			},
+		},
+		{
+			name:  "center",
+			input: `mode: CENTER`,
+			want: containment{
+				mode: sketchpb.Containment_CENTER,
+			},
+		},
+		{
+			name:    "overlap out of range",
+			input:   `mode: INTERSECTING min_overlap: 1.5`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "overlap without intersecting",
+			input:   `mode: CENTER min_overlap: 0.5`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "clip when contained",
+			input:   `clip_text: true`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "unknown mode",
+			input:   `mode: 100`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := containmentFromProto(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.Containment{}))
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(containment{})); diff != "" {
+					t.Errorf("containmentFromProto() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestContainmentAccepts(t *testing.T) {
+	area := geometry.RectFromCentimeters(0, 0, 10, 10)
+
+	for _, tc := range []struct {
+		name   string
+		c      containment
+		bounds geometry.Rect
+		want   bool
+	}{
+		{
+			name:   "contained",
+			bounds: geometry.RectFromCentimeters(1, 1, 2, 2),
+			want:   true,
+		},
+		{
+			name:   "contained partially inside",
+			bounds: geometry.RectFromCentimeters(8, 1, 12, 2),
+		},
+		{
+			name:   "intersecting",
+			c:      containment{mode: sketchpb.Containment_INTERSECTING},
+			bounds: geometry.RectFromCentimeters(8, 1, 12, 2),
+			want:   true,
+		},
+		{
+			name:   "intersecting touching",
+			c:      containment{mode: sketchpb.Containment_INTERSECTING},
+			bounds: geometry.RectFromCentimeters(10, 1, 12, 2),
+		},
+		{
+			name:   "intersecting outside",
+			c:      containment{mode: sketchpb.Containment_INTERSECTING},
+			bounds: geometry.RectFromCentimeters(11, 1, 12, 2),
+		},
+		{
+			name:   "intersecting below overlap",
+			c:      containment{mode: sketchpb.Containment_INTERSECTING, minOverlap: 0.6},
+			bounds: geometry.RectFromCentimeters(8, 1, 12, 2),
+		},
+		{
+			name:   "intersecting with overlap",
+			c:      containment{mode: sketchpb.Containment_INTERSECTING, minOverlap: 0.5},
+			bounds: geometry.RectFromCentimeters(8, 1, 12, 2),
+			want:   true,
+		},
+		{
+			name:   "intersecting degenerate",
+			c:      containment{mode: sketchpb.Containment_INTERSECTING, minOverlap: 1},
+			bounds: geometry.RectFromCentimeters(5, 1, 5, 2),
+			want:   true,
+		},
+		{
+			name:   "center inside",
+			c:      containment{mode: sketchpb.Containment_CENTER},
+			bounds: geometry.RectFromCentimeters(7, 1, 12, 2),
+			want:   true,
+		},
+		{
+			name:   "center outside",
+			c:      containment{mode: sketchpb.Containment_CENTER},
+			bounds: geometry.RectFromCentimeters(9, 1, 12, 2),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.c.accepts(area, tc.bounds); got != tc.want {
+				t.Errorf("accepts(%v, %v) = %v, want %v", area, tc.bounds, got, tc.want)
+			}
+		})
+	}
+}
+
+// fakeTextElement places each character in a box of 1x1 cm. Lines are
+// stacked vertically.
+type fakeTextElement struct {
+	text string
+}
+
+func (e *fakeTextElement) charBounds(pos int) geometry.Rect {
+	before := e.text[:pos]
+	row := strings.Count(before, "\n")
+	col := len(before) - (strings.LastIndex(before, "\n") + 1)
+
+	return geometry.RectFromCentimeters(float64(col), float64(row), float64(col+1), float64(row+1))
+}
+
+func (e *fakeTextElement) Bounds() geometry.Rect {
+	return e.RangeBounds(0, len(e.text))
+}
+
+func (e *fakeTextElement) Text() string {
+	return e.text
+}
+
+func (e *fakeTextElement) RangeBounds(start, end int) geometry.Rect {
+	var result geometry.Rect
+
+	for pos := start; pos < end; pos++ {
+		if e.text[pos] == '\n' {
+			continue
+		}
+
+		if r := e.charBounds(pos); result.IsEmpty() {
+			result = r
+		} else {
+			result = result.Union(r)
+		}
+	}
+
+	return result
+}
+
+func TestClipTextElement(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		text       string
+		area       geometry.Rect
+		wantNil    bool
+		wantText   string
+		wantBounds geometry.Rect
+		rangeStart int
+		rangeEnd   int
+		wantRange  geometry.Rect
+	}{
+		{
+			name:    "outside",
+			text:    "abc",
+			area:    geometry.RectFromCentimeters(5, 0, 10, 10),
+			wantNil: true,
+		},
+		{
+			name:       "prefix",
+			text:       "Total: 123",
+			area:       geometry.RectFromCentimeters(0, 0, 5.4, 1),
+			wantText:   "Total",
+			wantBounds: geometry.RectFromCentimeters(0, 0, 5, 1),
+			rangeStart: 1,
+			rangeEnd:   3,
+			wantRange:  geometry.RectFromCentimeters(1, 0, 3, 1),
+		},
+		{
+			name:       "column",
+			text:       "ab12\ncd34\nef56",
+			area:       geometry.RectFromCentimeters(2, 0, 4, 3),
+			wantText:   "12\n34\n56",
+			wantBounds: geometry.RectFromCentimeters(2, 0, 4, 3),
+			rangeStart: 1,
+			rangeEnd:   4,
+			wantRange:  geometry.RectFromCentimeters(2, 0, 4, 2),
+		},
+		{
+			name:       "skipped lines",
+			text:       "a\nbb\nccc",
+			area:       geometry.RectFromCentimeters(1, 0, 3, 3),
+			wantText:   "b\ncc",
+			wantBounds: geometry.RectFromCentimeters(1, 1, 3, 3),
+			rangeStart: 2,
+			rangeEnd:   4,
+			wantRange:  geometry.RectFromCentimeters(1, 2, 3, 3),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := clipTextElement(&fakeTextElement{tc.text}, tc.area)
+
+			if tc.wantNil {
+				if got != nil {
+					t.Errorf("clipTextElement() returned %q, want nil", got.Text())
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("clipTextElement() returned nil")
+			}
+
+			if diff := cmp.Diff(tc.wantText, got.Text()); diff != "" {
+				t.Errorf("Text() diff (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.wantBounds, got.Bounds(), geometry.EquateLength()); diff != "" {
+				t.Errorf("Bounds() diff (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.wantRange, got.RangeBounds(tc.rangeStart, tc.rangeEnd), geometry.EquateLength()); diff != "" {
+				t.Errorf("RangeBounds() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,9 +19,22 @@ func (r *DocumentReport) Pages() []*PageReport {
 	return r.pages
 }
 
+// ChecksFailed reports whether any computed node failed or produced an error
+// on any page.
+func (r *DocumentReport) ChecksFailed() bool {
+	for _, p := range r.pages {
+		if p.ChecksFailed() {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *DocumentReport) AsProto(unit geometry.LengthUnit) *reportpb.Document {
 	pb := &reportpb.Document{
-		Tags: r.tags,
+		Tags:         r.tags,
+		ChecksFailed: r.ChecksFailed(),
 	}
 
 	for _, p := range r.pages {
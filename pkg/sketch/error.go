@@ -12,4 +12,5 @@ var (
 
 	ErrNodeFeatureUnavailable = errors.New("node feature unavailable")
 	ErrNodePositionUnknown    = errors.New("node position unknown")
+	ErrAmbiguousMatch         = errors.New("ambiguous match")
 )
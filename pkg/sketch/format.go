@@ -0,0 +1,136 @@
+package sketch
+
+import (
+	"math"
+
+	"github.com/hansmi/dossier/internal/textproto"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/geometrypb"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Converted lengths are rounded to this many decimal places to hide the
+// imprecision of floating point conversions.
+const formatLengthPrecision = 6
+
+// File is a sketch configuration retaining the comments of its source text.
+// The configuration may be modified before formatting; comments are attached
+// to fields by name and position.
+type File struct {
+	Sketch *sketchpb.Sketch
+
+	tree *textproto.Message
+}
+
+// ParseFile parses a sketch in the Protocol Buffer text format.
+func ParseFile(src []byte) (*File, error) {
+	f := &File{
+		Sketch: &sketchpb.Sketch{},
+	}
+
+	if err := prototext.Unmarshal(src, f.Sketch); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	if f.tree, err = textproto.Parse(src); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+type formatOptions struct {
+	lengthUnit geometry.LengthUnit
+}
+
+type FormatOption func(*formatOptions)
+
+// WithLengthUnit converts all lengths to the given unit. Lengths referring to
+// parameters are left unchanged.
+func WithLengthUnit(unit geometry.LengthUnit) FormatOption {
+	return func(o *formatOptions) {
+		o.lengthUnit = unit
+	}
+}
+
+// convertLength replaces a length with its value in another unit.
+func convertLength(pb *geometrypb.Length, unit geometry.LengthUnit) {
+	if pb.GetValue() == nil {
+		return
+	}
+
+	l, err := geometry.LengthFromProto(pb)
+	if err != nil {
+		// Parameter reference
+		return
+	}
+
+	converted := l.AsProto(unit)
+	converted.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if f, ok := v.Interface().(float64); ok {
+			scale := math.Pow10(formatLengthPrecision)
+
+			converted.ProtoReflect().Set(fd, protoreflect.ValueOfFloat64(math.Round(f*scale)/scale))
+		}
+
+		return true
+	})
+
+	pb.Value = converted.Value
+}
+
+// sketchFieldOrder is the canonical order of top-level sketch fields. It
+// differs from the declaration order in the schema such that dependencies come
+// before the nodes using them.
+var sketchFieldOrder = []protoreflect.Name{
+	"imports",
+	"params",
+	"tags",
+	"normalization",
+	"templates",
+	"nodes",
+	"computed",
+}
+
+// Format writes the sketch in a canonical form. Imports, parameters and tags
+// come first, followed by templates, nodes and computed values. Nested fields
+// are ordered as declared in the schema. Indentation uses two spaces and short
+// messages are kept on a single line. Comments are retained.
+func (f *File) Format(opts ...FormatOption) []byte {
+	var o formatOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	pb := f.Sketch
+
+	if o.lengthUnit != nil {
+		pb = proto.Clone(pb).(*sketchpb.Sketch)
+
+		walkMessages(pb.ProtoReflect(), nil, func(m protoreflect.Message, _ textproto.Path) {
+			if l, ok := m.Interface().(*geometrypb.Length); ok {
+				convertLength(l, o.lengthUnit)
+			}
+		})
+	}
+
+	return textproto.Format(pb.ProtoReflect(), f.tree,
+		textproto.WithFieldOrder(pb.ProtoReflect().Descriptor().FullName(), sketchFieldOrder...))
+}
+
+// Format rewrites a sketch in the Protocol Buffer text format into its
+// canonical form. See [File.Format].
+func Format(src []byte, opts ...FormatOption) ([]byte, error) {
+	f, err := ParseFile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Format(opts...), nil
+}
@@ -0,0 +1,146 @@
+package sketch
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestFormat(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		opts    []FormatOption
+		want    string
+		wantErr error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "units",
+			input: `# Lengths
+params { name: "shift" length_value { in: 1 } }
+nodes {
+  name: "a"
+  search_areas {
+    top_left { abs { left {} top { param: "shift" } } }
+    width { cm: 2.5 }  # width
+    height { pt: 72 }
+  }
+}
+`,
+			opts: []FormatOption{WithLengthUnit(geometry.Mm)},
+			want: `# Lengths
+params { name: "shift" length_value { mm: 25.4 } }
+nodes {
+  name: "a"
+  search_areas {
+    top_left { abs { left {} top { param: "shift" } } }
+    width { mm: 25 } # width
+    height { mm: 25.4 }
+  }
+}
+`,
+		},
+		{
+			name: "license header",
+			input: `# Copyright 2024 Example
+# SPDX-License-Identifier: BSD-3-Clause
+
+# Invoice total
+nodes { name: "total" }
+tags: "x"
+`,
+			want: `# Copyright 2024 Example
+# SPDX-License-Identifier: BSD-3-Clause
+
+tags: "x"
+# Invoice total
+nodes { name: "total" }
+`,
+		},
+		{
+			name:    "syntax error",
+			input:   "nodes {",
+			wantErr: cmpopts.AnyError,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Format([]byte(tc.input), tc.opts...)
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+					t.Errorf("Format() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	src, err := os.ReadFile("testdata/acme-invoice.textproto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile() failed: %v", err)
+	}
+
+	formatted := f.Format()
+
+	var got sketchpb.Sketch
+
+	if err := prototext.Unmarshal(formatted, &got); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	if diff := cmp.Diff(f.Sketch, &got, protocmp.Transform()); diff != "" {
+		t.Errorf("Formatted sketch diff (-want +got):\n%s", diff)
+	}
+
+	if again, err := Format(formatted); err != nil {
+		t.Errorf("Format() failed: %v", err)
+	} else if diff := cmp.Diff(string(formatted), string(again)); diff != "" {
+		t.Errorf("Format() is not idempotent (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileFormatModified(t *testing.T) {
+	f, err := ParseFile([]byte(`
+# Nodes
+nodes {
+  name: "a"  # first
+}
+`))
+	if err != nil {
+		t.Fatalf("ParseFile() failed: %v", err)
+	}
+
+	f.Sketch.Nodes[0].Tags = []string{"x"}
+	f.Sketch.Nodes = append(f.Sketch.Nodes, &sketchpb.Node{Name: "b"})
+
+	want := `# Nodes
+nodes {
+  name: "a" # first
+  tags: "x"
+}
+nodes { name: "b" }
+`
+
+	if diff := cmp.Diff(want, string(f.Format())); diff != "" {
+		t.Errorf("Format() diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,258 @@
+package sketch
+
+import (
+	"fmt"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"golang.org/x/text/unicode/norm"
+)
+
+// defaultConfusables are character sequences commonly mistaken for each other
+// by text recognition.
+var defaultConfusables = [][]string{
+	{"0", "O", "o"},
+	{"1", "l", "I", "|"},
+	{"5", "S"},
+	{"8", "B"},
+	{"rn", "m"},
+	{"vv", "w"},
+	{"cl", "d"},
+}
+
+// fuzzyUnit is a folded character along with the byte range of the original
+// text it was derived from.
+type fuzzyUnit struct {
+	r          rune
+	start, end int
+}
+
+type fuzzyFolding struct {
+	ignoreCase       bool
+	ignoreDiacritics bool
+	foldWhitespace   bool
+}
+
+// fold converts text into a sequence of comparable characters.
+func (f fuzzyFolding) fold(text string) []fuzzyUnit {
+	var result []fuzzyUnit
+
+	for pos, r := range text {
+		end := pos + utf8.RuneLen(r)
+
+		if f.foldWhitespace && unicode.IsSpace(r) {
+			if len(result) > 0 && result[len(result)-1].r == ' ' && result[len(result)-1].end == pos {
+				// Extend previous whitespace
+				result[len(result)-1].end = end
+			} else {
+				result = append(result, fuzzyUnit{r: ' ', start: pos, end: end})
+			}
+
+			continue
+		}
+
+		runes := []rune{r}
+
+		if f.ignoreDiacritics {
+			runes = runes[:0]
+
+			for _, d := range norm.NFD.String(string(r)) {
+				if !unicode.Is(unicode.Mn, d) {
+					runes = append(runes, d)
+				}
+			}
+		}
+
+		for _, d := range runes {
+			if f.ignoreCase {
+				d = unicode.ToLower(d)
+			}
+
+			result = append(result, fuzzyUnit{r: d, start: pos, end: end})
+		}
+	}
+
+	return result
+}
+
+func (f fuzzyFolding) foldRunes(text string) []rune {
+	var result []rune
+
+	for _, u := range f.fold(text) {
+		result = append(result, u.r)
+	}
+
+	return result
+}
+
+// fuzzyEquivalence declares that the pattern sequence may be matched by the
+// text sequence at no cost.
+type fuzzyEquivalence struct {
+	pattern, text []rune
+}
+
+type fuzzyMatcher struct {
+	folding      fuzzyFolding
+	pattern      []rune
+	maxDistance  int
+	equivalences []fuzzyEquivalence
+}
+
+func fuzzyMatcherFromProto(pb *sketchpb.Node_Fuzzy) (*fuzzyMatcher, error) {
+	m := &fuzzyMatcher{
+		folding: fuzzyFolding{
+			ignoreCase:       pb.GetIgnoreCase(),
+			ignoreDiacritics: pb.GetIgnoreDiacritics(),
+			foldWhitespace:   pb.GetFoldWhitespace(),
+		},
+		maxDistance: int(pb.GetMaxDistance()),
+	}
+
+	if m.pattern = m.folding.foldRunes(pb.GetText()); len(m.pattern) == 0 {
+		return nil, fmt.Errorf("%w: fuzzy match requires text", sketcherror.ErrIncompleteConfig)
+	}
+
+	if m.maxDistance >= len(m.pattern) {
+		return nil, fmt.Errorf("%w: fuzzy match distance must be less than the text length, got %d",
+			sketcherror.ErrBadConfig, m.maxDistance)
+	}
+
+	var groups [][]string
+
+	if pb.GetDefaultConfusables() {
+		groups = append(groups, defaultConfusables...)
+	}
+
+	for _, c := range pb.GetConfusables() {
+		if len(c.GetVariants()) < 2 {
+			return nil, fmt.Errorf("%w: confusable requires at least two variants, got %q", sketcherror.ErrBadConfig, c.GetVariants())
+		}
+
+		groups = append(groups, c.GetVariants())
+	}
+
+	for _, group := range groups {
+		folded := make([][]rune, 0, len(group))
+
+		for _, variant := range group {
+			f := m.folding.foldRunes(variant)
+			if len(f) == 0 {
+				return nil, fmt.Errorf("%w: confusable variants must not be empty", sketcherror.ErrBadConfig)
+			}
+
+			folded = append(folded, f)
+		}
+
+		for _, a := range folded {
+			for _, b := range folded {
+				if !slices.Equal(a, b) {
+					m.equivalences = append(m.equivalences, fuzzyEquivalence{pattern: a, text: b})
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// hasSuffix reports whether the sequence of units ends with the given runes.
+func hasSuffix(units []fuzzyUnit, suffix []rune) bool {
+	if len(suffix) > len(units) {
+		return false
+	}
+
+	units = units[len(units)-len(suffix):]
+
+	for idx, r := range suffix {
+		if units[idx].r != r {
+			return false
+		}
+	}
+
+	return true
+}
+
+// find searches for the closest occurrence of the pattern in the text. The
+// returned byte offsets refer to the original text. Among occurrences with
+// the same distance the earliest is used.
+func (m *fuzzyMatcher) find(text string) (start, end, distance int, ok bool) {
+	units := m.folding.fold(text)
+
+	rows := len(m.pattern) + 1
+	cols := len(units) + 1
+
+	// Cost of matching the first i pattern characters ending at text
+	// position j along with the text position where the match starts.
+	cost := make([]int, rows*cols)
+	origin := make([]int, rows*cols)
+
+	at := func(i, j int) int {
+		return i*cols + j
+	}
+
+	for j := 0; j < cols; j++ {
+		origin[at(0, j)] = j
+	}
+
+	for j := 0; j < cols; j++ {
+		for i := 1; i < rows; i++ {
+			idx := at(i, j)
+
+			// Pattern character missing from text
+			best, from := cost[at(i-1, j)]+1, origin[at(i-1, j)]
+
+			if j > 0 {
+				sub := cost[at(i-1, j-1)]
+				if m.pattern[i-1] != units[j-1].r {
+					sub++
+				}
+
+				if sub <= best {
+					best, from = sub, origin[at(i-1, j-1)]
+				}
+
+				// Additional character in text
+				if c := cost[at(i, j-1)] + 1; c < best {
+					best, from = c, origin[at(i, j-1)]
+				}
+			}
+
+			for _, eq := range m.equivalences {
+				pi, tj := i-len(eq.pattern), j-len(eq.text)
+
+				if pi < 0 || tj < 0 || !slices.Equal(m.pattern[pi:i], eq.pattern) || !hasSuffix(units[:j], eq.text) {
+					continue
+				}
+
+				if c := cost[at(pi, tj)]; c < best {
+					best, from = c, origin[at(pi, tj)]
+				}
+			}
+
+			cost[idx] = best
+			origin[idx] = from
+		}
+	}
+
+	bestEnd := -1
+
+	for j := 0; j < cols; j++ {
+		if c := cost[at(rows-1, j)]; c <= m.maxDistance && (bestEnd < 0 || c < cost[at(rows-1, bestEnd)]) {
+			bestEnd = j
+		}
+	}
+
+	if bestEnd < 0 {
+		return 0, 0, 0, false
+	}
+
+	// The maximum distance is less than the pattern length, hence at least
+	// one text character is part of the match.
+	first := origin[at(rows-1, bestEnd)]
+	distance = cost[at(rows-1, bestEnd)]
+
+	return units[first].start, units[bestEnd-1].end, distance, true
+}
@@ -0,0 +1,208 @@
+package sketch
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+func TestFuzzyMatcherFromProto(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{
+			name:    "empty",
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name:    "only whitespace",
+			input:   `text: "   " fold_whitespace: true max_distance: 1`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:  "minimal",
+			input: `text: "Total"`,
+		},
+		{
+			name:    "distance too large",
+			input:   `text: "abc" max_distance: 3`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "single variant",
+			input:   `text: "abc" confusables { variants: "a" }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "empty variant",
+			input:   `text: "abc" confusables { variants: "a" variants: "" }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:  "all options",
+			input: `text: "abc" max_distance: 1 ignore_case: true ignore_diacritics: true fold_whitespace: true default_confusables: true confusables { variants: "a" variants: "4" }`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := fuzzyMatcherFromProto(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.Node_Fuzzy{}))
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatcherFind(t *testing.T) {
+	type result struct {
+		Start, End, Distance int
+	}
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		text  string
+		want  *result
+	}{
+		{
+			name:  "exact",
+			input: `text: "Total"`,
+			text:  "Gross Total 12",
+			want:  &result{6, 11, 0},
+		},
+		{
+			name:  "no match",
+			input: `text: "Invoice" max_distance: 1`,
+			text:  "Bill",
+		},
+		{
+			name:  "case mismatch",
+			input: `text: "total"`,
+			text:  "TOTAL",
+		},
+		{
+			name:  "ignore case",
+			input: `text: "total" ignore_case: true`,
+			text:  "TOTAL",
+			want:  &result{0, 5, 0},
+		},
+		{
+			name:  "substitution",
+			input: `text: "Invoice" max_distance: 1`,
+			text:  "Inv0ice no",
+			want:  &result{0, 7, 1},
+		},
+		{
+			name:  "insertion",
+			input: `text: "Invoice" max_distance: 1`,
+			text:  "# Invo ice",
+			want:  &result{2, 10, 1},
+		},
+		{
+			name:  "deletion",
+			input: `text: "Invoice" max_distance: 2`,
+			text:  "Invce",
+			want:  &result{0, 5, 2},
+		},
+		{
+			name:  "closest occurrence",
+			input: `text: "Total" max_distance: 1`,
+			text:  "Tota1 Total",
+			want:  &result{6, 11, 0},
+		},
+		{
+			name:  "default confusables",
+			input: `text: "Invoice number" default_confusables: true`,
+			text:  "lnv0ice nurnber: 123",
+			want:  &result{0, 15, 0},
+		},
+		{
+			name:  "custom confusables",
+			input: `text: "Amount" confusables { variants: "A" variants: "4" }`,
+			text:  "4mount",
+			want:  &result{0, 6, 0},
+		},
+		{
+			name:  "diacritics",
+			input: `text: "Menu" ignore_diacritics: true`,
+			text:  "Menü 5",
+			want:  &result{0, 5, 0},
+		},
+		{
+			name:  "pattern with diacritics",
+			input: `text: "Grösse" ignore_diacritics: true ignore_case: true`,
+			text:  "GROSSE",
+			want:  &result{0, 6, 0},
+		},
+		{
+			name:  "whitespace",
+			input: `text: "Gross total" fold_whitespace: true`,
+			text:  "Gross \t  total:",
+			want:  &result{0, 14, 0},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := fuzzyMatcherFromProto(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.Node_Fuzzy{}))
+			if err != nil {
+				t.Fatalf("fuzzyMatcherFromProto() failed: %v", err)
+			}
+
+			var got *result
+
+			if start, end, distance, ok := m.find(tc.text); ok {
+				got = &result{start, end, distance}
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("find(%q) diff (-want +got):\n%s", tc.text, diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateFuzzyMatch(t *testing.T) {
+	m, err := fuzzyMatcherFromProto(testutil.MustUnmarshalTextproto(t, `
+text: "Invoice number"
+max_distance: 1
+ignore_case: true
+default_confusables: true
+`, &sketchpb.Node_Fuzzy{}))
+	if err != nil {
+		t.Fatalf("fuzzyMatcherFromProto() failed: %v", err)
+	}
+
+	pat := regexp.MustCompile(`^\s*:\s*(?P<value>\d+)`)
+
+	if got := evaluateFuzzyMatch(m, pat, "Invoice number: abc"); got != nil {
+		t.Errorf("evaluateFuzzyMatch() returned %v, want nil", got.Groups())
+	}
+
+	got := evaluateFuzzyMatch(m, pat, "# lnvoice nurnbr: 1234")
+	if got == nil {
+		t.Fatalf("evaluateFuzzyMatch() returned nil")
+	}
+
+	want := []TextMatchGroup{
+		{Start: 2, End: 22, Text: "lnvoice nurnbr: 1234"},
+		{Name: "value", Start: 18, End: 22, Text: "1234"},
+	}
+
+	if diff := cmp.Diff(want, got.Groups()); diff != "" {
+		t.Errorf("Match groups diff (-want +got):\n%s", diff)
+	}
+
+	if distance, ok := got.EditDistance(); !(ok && distance == 1) {
+		t.Errorf("EditDistance() = (%d, %v), want (1, true)", distance, ok)
+	}
+
+	if _, ok := evaluateMatch(pat, ": 1").EditDistance(); ok {
+		t.Errorf("EditDistance() for regular expression match reports fuzzy match")
+	}
+}
@@ -0,0 +1,462 @@
+package sketch
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+
+	"github.com/hansmi/dossier/internal/expr"
+	"github.com/hansmi/dossier/internal/flexrect"
+	"github.com/hansmi/dossier/internal/textproto"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Longest side of the largest common paper sizes (US Tabloid, 11 by 17
+// inches). Areas starting beyond it in either direction are outside any
+// standard page regardless of orientation.
+const lintMaxPageExtent = 17 * geometry.Inch
+
+// Position is a location within a sketch file.
+type Position struct {
+	File string
+
+	// 1-based line and column numbers. Zero if unknown.
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	if p.Line < 1 {
+		return p.File
+	}
+
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Diagnostic is a likely mistake found by [Lint].
+type Diagnostic struct {
+	Pos Position
+
+	// Identifier of the check, e.g. "empty-match".
+	Check string
+
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s (%s)", d.Pos, d.Message, d.Check)
+}
+
+type linter struct {
+	file string
+	root locator
+	pb   *sketchpb.Sketch
+
+	// Nodes defined in the file by name.
+	nodes map[string]*sketchpb.Node
+
+	diagnostics []Diagnostic
+}
+
+// Lint reports likely mistakes in a sketch which are not caught by [Compile].
+// The format is detected from the name like in [CompileFromFile]. Imports are
+// not followed and references to nodes not defined in the file are assumed to
+// be valid.
+func Lint(name string, src []byte) ([]Diagnostic, error) {
+	format := detectSourceFormat(name)
+
+	if format == formatTextproto {
+		// Report syntax errors with their position.
+		if _, err := textproto.Parse(src); err != nil {
+			return nil, fmt.Errorf("%s:%w", name, err)
+		}
+	}
+
+	pb, srcFile, err := decodeSource(format, name, src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	l := &linter{
+		file:  name,
+		root:  srcFile.root,
+		pb:    pb,
+		nodes: map[string]*sketchpb.Node{},
+	}
+
+	for _, n := range pb.GetNodes() {
+		l.nodes[n.GetName()] = n
+	}
+
+	l.checkPatterns()
+	l.checkAreas()
+	l.checkReferences()
+
+	slices.SortStableFunc(l.diagnostics, func(a, b Diagnostic) int {
+		return cmp.Or(
+			cmp.Compare(a.Pos.Line, b.Pos.Line),
+			cmp.Compare(a.Pos.Column, b.Pos.Column),
+		)
+	})
+
+	return l.diagnostics, nil
+}
+
+// LintFile reads a sketch from a file system and lints it.
+func LintFile(fsys fs.FS, name string) ([]Diagnostic, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return Lint(name, content)
+}
+
+func (l *linter) report(path textproto.Path, check, format string, args ...any) {
+	var pos textproto.Position
+
+	if l.root != nil {
+		pos, _ = l.root.Locate(path)
+	}
+
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Pos: Position{
+			File:   l.file,
+			Line:   pos.Line,
+			Column: pos.Column,
+		},
+		Check:   check,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// walkMessages invokes the callback for the message and all messages nested
+// within it.
+func walkMessages(m protoreflect.Message, path textproto.Path, fn func(protoreflect.Message, textproto.Path)) {
+	fn(m, path)
+
+	fields := m.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() || !m.Has(fd) {
+			continue
+		}
+
+		name := string(fd.Name())
+
+		if fd.IsList() {
+			list := m.Get(fd).List()
+
+			for idx := 0; idx < list.Len(); idx++ {
+				walkMessages(list.Get(idx).Message(), path.Append(name, idx), fn)
+			}
+		} else {
+			walkMessages(m.Get(fd).Message(), path.Append(name, 0), fn)
+		}
+	}
+}
+
+// zeroWidth reports whether a regular expression can only match empty text,
+// e.g. because it consists only of anchors.
+func zeroWidth(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine,
+		syntax.OpBeginText, syntax.OpEndText, syntax.OpWordBoundary,
+		syntax.OpNoWordBoundary:
+		return true
+
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest,
+		syntax.OpRepeat, syntax.OpConcat, syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !zeroWidth(sub) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// checkPattern inspects a regular expression. Patterns with parameter
+// references are skipped.
+func (l *linter) checkPattern(path textproto.Path, pattern string, boundsFromMatch bool) {
+	if pattern == "" || paramReferencePattern.MatchString(pattern) {
+		return
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		// Reported by the compiler
+		return
+	}
+
+	if re.MatchString("") {
+		l.report(path, "empty-match", "pattern %q matches the empty string and thereby any text", pattern)
+	}
+
+	if parsed, err := syntax.Parse(pattern, syntax.Perl); err == nil && boundsFromMatch && zeroWidth(parsed) {
+		l.report(path, "zero-width-bounds", "bounds_from_match with pattern %q consisting only of anchors produces empty bounds", pattern)
+	}
+}
+
+func (l *linter) checkPatterns() {
+	walkMessages(l.pb.ProtoReflect(), nil, func(m protoreflect.Message, path textproto.Path) {
+		switch pb := m.Interface().(type) {
+		case *sketchpb.Node_TextMatch:
+			if pb.Fuzzy == nil {
+				l.checkPattern(path.Append("regex", 0), pb.GetRegex(), pb.GetBoundsFromMatch())
+			}
+
+		case *sketchpb.Node_MultiLineMatch:
+			l.checkPattern(path.Append("stop_regex", 0), pb.GetStopRegex(), false)
+		}
+	})
+}
+
+type lintCallbacks struct{}
+
+func (lintCallbacks) NodeFeaturePosition(name string, _ sketchpb.NodeFeature) (geometry.Point, error) {
+	return geometry.Point{}, fmt.Errorf("%w: node %q", ErrNodePositionUnknown, name)
+}
+
+// resolveAbsolute returns the bounds of an area not depending on any node.
+// The second return value is false for areas depending on nodes and invalid
+// areas.
+func resolveAbsolute(pb *sketchpb.FlexRect) (geometry.Rect, bool) {
+	area, err := flexrect.FromProto(pb)
+	if err != nil || len(area.RequiredNodeFeatures()) > 0 {
+		return geometry.Rect{}, false
+	}
+
+	bounds, err := area.Resolve(lintCallbacks{})
+	if err != nil {
+		return geometry.Rect{}, false
+	}
+
+	return bounds, true
+}
+
+// outsidePage reports whether an area with absolute position can't overlap
+// any standard page.
+func outsidePage(pb *sketchpb.FlexRect) bool {
+	bounds, ok := resolveAbsolute(pb)
+
+	return ok && (bounds.Left >= lintMaxPageExtent || bounds.Top >= lintMaxPageExtent ||
+		bounds.Right <= 0 || bounds.Bottom <= 0)
+}
+
+// checkSearchAreas looks for search areas which are never searched or never
+// contribute candidates. Areas are searched in order until candidates are
+// found. A later area located within an earlier one therefore never finds
+// anything new.
+func (l *linter) checkSearchAreas(path textproto.Path, areas []*sketchpb.FlexRect) {
+	for idx, area := range areas {
+		areaPath := path.Append("search_areas", idx)
+
+		if outsidePage(area) {
+			l.report(areaPath, "area-outside-page", "search area is outside of all standard page sizes")
+			continue
+		}
+
+		bounds, ok := resolveAbsolute(area)
+
+		for prevIdx, prev := range areas[:idx] {
+			if proto.Equal(prev, area) {
+				l.report(areaPath, "duplicate-area", "search area is identical to search area %d", prevIdx+1)
+				break
+			}
+
+			if prevBounds, prevOK := resolveAbsolute(prev); ok && prevOK && prevBounds.Contains(bounds) {
+				l.report(areaPath, "duplicate-area", "search area is contained in search area %d and never yields additional candidates", prevIdx+1)
+				break
+			}
+		}
+	}
+}
+
+func (l *linter) checkAreas() {
+	for nodeIdx, n := range l.pb.GetNodes() {
+		nodePath := textproto.Path{}.Append("nodes", nodeIdx)
+
+		l.checkSearchAreas(nodePath, n.GetSearchAreas())
+
+		for altIdx, alt := range n.GetAlternatives() {
+			l.checkSearchAreas(nodePath.Append("alternatives", altIdx), alt.GetSearchAreas())
+		}
+
+		for idx, area := range n.GetExclusions() {
+			if outsidePage(area) {
+				l.report(nodePath.Append("exclusions", idx), "area-outside-page", "exclusion area is outside of all standard page sizes")
+			}
+		}
+	}
+}
+
+// nodeReference is a reference to a node by name.
+type nodeReference struct {
+	path textproto.Path
+	node string
+}
+
+// collectReferences returns all references to nodes defined in the file,
+// including those made by computed nodes.
+func (l *linter) collectReferences() []nodeReference {
+	var result []nodeReference
+
+	walkMessages(l.pb.ProtoReflect(), nil, func(m protoreflect.Message, path textproto.Path) {
+		fd := m.Descriptor().Fields().ByName("node")
+		if fd == nil || fd.Kind() != protoreflect.StringKind {
+			return
+		}
+
+		if name := m.Get(fd).String(); l.nodes[name] != nil {
+			result = append(result, nodeReference{
+				path: path.Append("node", 0),
+				node: name,
+			})
+		}
+	})
+
+	for idx, c := range l.pb.GetComputed() {
+		parsed, err := expr.Parse(c.GetExpr())
+		if err != nil {
+			continue
+		}
+
+		for _, ref := range parsed.References() {
+			for end := len(ref); end > 0; end-- {
+				if name := strings.Join(ref[:end], "."); l.nodes[name] != nil {
+					result = append(result, nodeReference{
+						path: textproto.Path{}.Append("computed", idx).Append("expr", 0),
+						node: name,
+					})
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// areaNeverSearched reports whether an area is outside any page or depends
+// on a node which is never found.
+func areaNeverSearched(pb *sketchpb.FlexRect, never map[string]bool) bool {
+	area, err := flexrect.FromProto(pb)
+	if err != nil {
+		return false
+	}
+
+	for _, f := range area.RequiredNodeFeatures() {
+		if never[f.NodeName()] {
+			return true
+		}
+	}
+
+	return outsidePage(pb)
+}
+
+// allNeverSearched reports whether none of the areas are ever searched. Nodes
+// without areas are not considered.
+func allNeverSearched(areas []*sketchpb.FlexRect, adjacent []*sketchpb.AdjacentArea, never map[string]bool) bool {
+	if len(areas) == 0 && len(adjacent) == 0 {
+		return false
+	}
+
+	for _, area := range areas {
+		if !areaNeverSearched(area, never) {
+			return false
+		}
+	}
+
+	for _, area := range adjacent {
+		if !never[area.GetNode()] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// neverFound reports whether a node can't be found on any page because none
+// of its areas are ever searched.
+func neverFound(n *sketchpb.Node, never map[string]bool) bool {
+	if n.GetTemplate() != "" {
+		// Areas may be inherited
+		return false
+	}
+
+	switch {
+	case n.Neighbor != nil:
+		if !never[n.GetNeighbor().GetNode()] {
+			return false
+		}
+
+	case len(n.GetSearchAreas()) > 0 || len(n.GetAdjacentAreas()) > 0:
+		if !allNeverSearched(n.GetSearchAreas(), n.GetAdjacentAreas(), never) {
+			return false
+		}
+
+	case len(n.GetAlternatives()) == 0:
+		return false
+	}
+
+	for _, alt := range n.GetAlternatives() {
+		if !allNeverSearched(alt.GetSearchAreas(), alt.GetAdjacentAreas(), never) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *linter) checkReferences() {
+	refs := l.collectReferences()
+
+	referenced := map[string]bool{}
+
+	for _, ref := range refs {
+		referenced[ref.node] = true
+	}
+
+	for idx, n := range l.pb.GetNodes() {
+		if !referenced[n.GetName()] && len(n.GetTags()) == 0 && n.GetTemplate() == "" {
+			l.report(textproto.Path{}.Append("nodes", idx).Append("name", 0), "unused-node",
+				"node %q is neither referenced nor tagged", n.GetName())
+		}
+	}
+
+	never := map[string]bool{}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, n := range l.pb.GetNodes() {
+			if !never[n.GetName()] && neverFound(n, never) {
+				never[n.GetName()] = true
+				changed = true
+			}
+		}
+	}
+
+	for _, ref := range refs {
+		if never[ref.node] {
+			l.report(ref.path, "unreachable", "node %q is never found as none of its areas can be searched", ref.node)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package sketch
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestLint(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		want    []Diagnostic
+		wantErr error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "clean",
+			input: `
+nodes {
+  name: "label"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  line_text { regex: "^Total$" bounds_from_match: true }
+}
+
+nodes {
+  name: "value"
+  adjacent_areas { node: "label" direction: RIGHT }
+  line_text { regex: "\\d+" }
+  tags: "value"
+}
+
+nodes {
+  name: "param"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  line_text { regex: "${pattern}" }
+  tags: "param"
+}
+`,
+		},
+		{
+			name: "patterns",
+			input: `nodes {
+  name: "a"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  line_text { regex: "^\\s*" }
+  must_not_match {
+    search_area {
+      top_left { abs { left {} top {} } }
+      width { cm: 1 }
+      height { cm: 1 }
+    }
+    block_text { regex: "^$" bounds_from_match: true }
+  }
+  tags: "a"
+}
+nodes {
+  name: "b"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  multi_line {
+    anchor { regex: "^Address" }
+    text { regex: "(?P<addr>.+)" }
+    stop_regex: "x*"
+  }
+  tags: "b"
+}
+`,
+			want: []Diagnostic{
+				{
+					Pos:     Position{File: "test", Line: 8, Column: 15},
+					Check:   "empty-match",
+					Message: `pattern "^\\s*" matches the empty string and thereby any text`,
+				},
+				{
+					Pos:     Position{File: "test", Line: 15, Column: 18},
+					Check:   "empty-match",
+					Message: `pattern "^$" matches the empty string and thereby any text`,
+				},
+				{
+					Pos:     Position{File: "test", Line: 15, Column: 18},
+					Check:   "zero-width-bounds",
+					Message: `bounds_from_match with pattern "^$" consisting only of anchors produces empty bounds`,
+				},
+				{
+					Pos:     Position{File: "test", Line: 29, Column: 5},
+					Check:   "empty-match",
+					Message: `pattern "x*" matches the empty string and thereby any text`,
+				},
+			},
+		},
+		{
+			name: "areas",
+			input: `nodes {
+  name: "a"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  search_areas {
+    top_left { abs { left { cm: 1 } top { cm: 1 } } }
+    width { cm: 2 }
+    height { cm: 2 }
+  }
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  search_areas {
+    top_left { abs { left { cm: 100 } top {} } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  line_text {}
+  tags: "a"
+}
+`,
+			want: []Diagnostic{
+				{
+					Pos:     Position{File: "test", Line: 8, Column: 3},
+					Check:   "duplicate-area",
+					Message: "search area is contained in search area 1 and never yields additional candidates",
+				},
+				{
+					Pos:     Position{File: "test", Line: 13, Column: 3},
+					Check:   "duplicate-area",
+					Message: "search area is identical to search area 1",
+				},
+				{
+					Pos:     Position{File: "test", Line: 18, Column: 3},
+					Check:   "area-outside-page",
+					Message: "search area is outside of all standard page sizes",
+				},
+			},
+		},
+		{
+			name: "references",
+			input: `nodes {
+  name: "lost"
+  search_areas {
+    top_left { abs { left {} top { cm: 200 } } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  line_text {}
+}
+nodes {
+  name: "dependent"
+  adjacent_areas { node: "lost" direction: DOWN }
+  line_text {}
+}
+nodes {
+  name: "neighbor"
+  neighbor { node: "dependent" direction: RIGHT }
+  line_text {}
+  tags: "neighbor"
+}
+nodes {
+  name: "unused"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 5 }
+  }
+  line_text {}
+}
+computed { name: "c" expr: "has(neighbor.value)" }
+`,
+			want: []Diagnostic{
+				{
+					Pos:     Position{File: "test", Line: 3, Column: 3},
+					Check:   "area-outside-page",
+					Message: "search area is outside of all standard page sizes",
+				},
+				{
+					Pos:     Position{File: "test", Line: 12, Column: 20},
+					Check:   "unreachable",
+					Message: `node "lost" is never found as none of its areas can be searched`,
+				},
+				{
+					Pos:     Position{File: "test", Line: 17, Column: 14},
+					Check:   "unreachable",
+					Message: `node "dependent" is never found as none of its areas can be searched`,
+				},
+				{
+					Pos:     Position{File: "test", Line: 22, Column: 3},
+					Check:   "unused-node",
+					Message: `node "unused" is neither referenced nor tagged`,
+				},
+				{
+					Pos:     Position{File: "test", Line: 30, Column: 22},
+					Check:   "unreachable",
+					Message: `node "neighbor" is never found as none of its areas can be searched`,
+				},
+			},
+		},
+		{
+			name:    "syntax error",
+			input:   "nodes {",
+			wantErr: cmpopts.AnyError,
+		},
+		{
+			name:    "unknown field",
+			input:   "foo: 1",
+			wantErr: cmpopts.AnyError,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Lint("test", []byte(tc.input))
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("Lint() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dir/sketch.textproto": &fstest.MapFile{Data: []byte(`
+nodes { name: "a" }
+`)},
+	}
+
+	got, err := LintFile(fsys, "dir/sketch.textproto")
+	if err != nil {
+		t.Fatalf("LintFile() failed: %v", err)
+	}
+
+	want := []Diagnostic{
+		{
+			Pos:     Position{File: "dir/sketch.textproto", Line: 2, Column: 9},
+			Check:   "unused-node",
+			Message: `node "a" is neither referenced nor tagged`,
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LintFile() diff (-want +got):\n%s", diff)
+	}
+
+	if got := want[0].String(); got != `dir/sketch.textproto:2:9: node "a" is neither referenced nor tagged (unused-node)` {
+		t.Errorf("String() returned %q", got)
+	}
+}
+
+func TestLintYAML(t *testing.T) {
+	got, err := Lint("sketch.yaml", []byte(`
+nodes:
+  - name: a
+    tags: [a]
+  - name: b
+`))
+	if err != nil {
+		t.Fatalf("Lint() failed: %v", err)
+	}
+
+	want := []Diagnostic{
+		{
+			Pos:     Position{File: "sketch.yaml", Line: 5, Column: 5},
+			Check:   "unused-node",
+			Message: `node "b" is neither referenced nor tagged`,
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Lint() diff (-want +got):\n%s", diff)
+	}
+}
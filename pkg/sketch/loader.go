@@ -0,0 +1,440 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hansmi/dossier/internal/expr"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/internal/textproto"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"go.uber.org/multierr"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// nodeReferenceMessages are messages with a "node" field referring to another
+// node by name.
+var nodeReferenceMessages = []protoreflect.FullName{
+	(*sketchpb.RelativePosition1D)(nil).ProtoReflect().Descriptor().FullName(),
+	(*sketchpb.RelativePosition2D)(nil).ProtoReflect().Descriptor().FullName(),
+	(*sketchpb.AdjacentArea)(nil).ProtoReflect().Descriptor().FullName(),
+	(*sketchpb.NeighborSearch)(nil).ProtoReflect().Descriptor().FullName(),
+}
+
+// namespaced prefixes a name with a namespace.
+func namespaced(namespace, name string) string {
+	return namespace + "." + name
+}
+
+// namespaceNodeReferences prefixes all non-empty references to other nodes
+// within a message.
+func namespaceNodeReferences(m protoreflect.Message, namespace string) {
+	if slices.Contains(nodeReferenceMessages, m.Descriptor().FullName()) {
+		fd := m.Descriptor().Fields().ByName("node")
+
+		if name := m.Get(fd).String(); name != "" {
+			m.Set(fd, protoreflect.ValueOfString(namespaced(namespace, name)))
+		}
+	}
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsMap() {
+			return true
+		}
+
+		if fd.IsList() {
+			for idx := range v.List().Len() {
+				namespaceNodeReferences(v.List().Get(idx).Message(), namespace)
+			}
+		} else {
+			namespaceNodeReferences(v.Message(), namespace)
+		}
+
+		return true
+	})
+}
+
+// mergeOverride copies all populated fields from src to dst. Message fields
+// are merged recursively and lists are replaced.
+func mergeOverride(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() && dst.Has(fd) {
+			mergeOverride(dst.Mutable(fd).Message(), v.Message())
+		} else {
+			dst.Set(fd, v)
+		}
+
+		return true
+	})
+}
+
+// sketchLoader resolves imports and templates.
+type sketchLoader struct {
+	// File system for imports. Nil if imports aren't supported.
+	fsys fs.FS
+
+	// Files currently being loaded, used to detect cycles.
+	active []string
+
+	// Parameter overrides by namespaced name.
+	params map[string]any
+
+	// Names of overridden parameters declared by a sketch.
+	usedParams map[string]bool
+}
+
+// resolvedSketch is a sketch with all imports and templates expanded.
+type resolvedSketch struct {
+	pb        *sketchpb.Sketch
+	templates map[string]*sketchpb.Node
+
+	// File of the sketch itself. Nil if not loaded from a file.
+	source *sourceFile
+
+	// Locations of nodes and computed nodes, by index.
+	nodeSources     []sourceRef
+	computedSources []sourceRef
+}
+
+// position determines the location of a value in the resolved sketch. Paths
+// referring to nodes are mapped to the file the node was defined in.
+func (r *resolvedSketch) position(path textproto.Path) Position {
+	if len(path) > 0 {
+		var refs []sourceRef
+
+		switch path[0].Name {
+		case "nodes":
+			refs = r.nodeSources
+		case "computed":
+			refs = r.computedSources
+		}
+
+		if idx := path[0].Index; idx < len(refs) {
+			return refs[idx].position(path[1:])
+		}
+	}
+
+	return sourceRef{file: r.source}.position(path)
+}
+
+func (l *sketchLoader) loadFile(name, namespace string) (*resolvedSketch, error) {
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("%w: invalid sketch path %q", sketcherror.ErrBadConfig, name)
+	}
+
+	if slices.Contains(l.active, name) {
+		return nil, fmt.Errorf("%w: import cycle: %s -> %s", sketcherror.ErrBadConfig, strings.Join(l.active, " -> "), name)
+	}
+
+	content, err := fs.ReadFile(l.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	pb, src, err := decodeSource(detectSourceFormat(name), name, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	l.active = append(l.active, name)
+	defer func() {
+		l.active = l.active[:len(l.active)-1]
+	}()
+
+	return l.resolve(pb, src, path.Dir(name), namespace)
+}
+
+// importSketch loads an imported sketch and adds its nodes and templates
+// under the import namespace.
+func (l *sketchLoader) importSketch(result *resolvedSketch, imp *sketchpb.Sketch_Import, dir, parentNamespace string) error {
+	namespace := imp.GetNamespace()
+
+	if namespace == "" {
+		return fmt.Errorf("%w: import of %q requires namespace", sketcherror.ErrIncompleteConfig, imp.GetPath())
+	}
+
+	fullNamespace := namespace
+
+	if parentNamespace != "" {
+		fullNamespace = namespaced(parentNamespace, namespace)
+	}
+
+	if l.fsys == nil {
+		return fmt.Errorf("%w: imports are not supported without a loader", sketcherror.ErrBadConfig)
+	}
+
+	sub, err := l.loadFile(path.Join(dir, imp.GetPath()), fullNamespace)
+	if err != nil {
+		var errs ConfigErrors
+
+		if errors.As(err, &errs) {
+			// The positions already name the imported file.
+			return errs
+		}
+
+		return fmt.Errorf("import %q: %w", imp.GetPath(), err)
+	}
+
+	for _, node := range sub.pb.GetNodes() {
+		if node.Normalization == nil && sub.pb.Normalization != nil {
+			node.Normalization = proto.Clone(sub.pb.GetNormalization()).(*sketchpb.Normalization)
+		}
+
+		node.Name = namespaced(namespace, node.GetName())
+		namespaceNodeReferences(node.ProtoReflect(), namespace)
+
+		result.pb.Nodes = append(result.pb.Nodes, node)
+	}
+
+	result.nodeSources = append(result.nodeSources, sub.nodeSources...)
+
+	var errs error
+
+	for idx, c := range sub.pb.GetComputed() {
+		e, err := expr.Parse(c.GetExpr())
+		if err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("computed node %q: %w", c.GetName(),
+				multierr.Combine(sketcherror.ErrBadConfig, err)), "computed", idx))
+			continue
+		}
+
+		c.Name = namespaced(namespace, c.GetName())
+		c.Expr = e.RewriteReferences(func(path []string) []string {
+			return append([]string{namespace}, path...)
+		})
+
+		result.pb.Computed = append(result.pb.Computed, c)
+	}
+
+	result.computedSources = append(result.computedSources, sub.computedSources...)
+
+	for name, tmpl := range sub.templates {
+		if base := tmpl.GetTemplate(); base != "" {
+			tmpl = proto.Clone(tmpl).(*sketchpb.Node)
+			tmpl.Template = namespaced(namespace, base)
+		}
+
+		result.templates[namespaced(namespace, name)] = tmpl
+	}
+
+	if errs != nil {
+		return collectConfigErrors(errs, sub.position)
+	}
+
+	return nil
+}
+
+// expand applies the template referenced by a node, if any. Templates may be
+// based on other templates.
+func (r *resolvedSketch) expand(node *sketchpb.Node, seen []string) (*sketchpb.Node, error) {
+	name := node.GetTemplate()
+	if name == "" {
+		return node, nil
+	}
+
+	if slices.Contains(seen, name) {
+		return nil, fmt.Errorf("%w: template cycle: %s -> %s", sketcherror.ErrBadConfig, strings.Join(seen, " -> "), name)
+	}
+
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown template %q", sketcherror.ErrBadConfig, name)
+	}
+
+	base, err := r.expand(tmpl, append(seen, name))
+	if err != nil {
+		return nil, err
+	}
+
+	result := proto.Clone(base).(*sketchpb.Node)
+
+	mergeOverride(result.ProtoReflect(), proto.Clone(node).ProtoReflect())
+
+	result.Template = ""
+
+	return result, nil
+}
+
+// resolveParams determines the parameter values of a sketch file loaded with
+// the given namespace.
+func (l *sketchLoader) resolveParams(params []*sketchpb.Sketch_Param, namespace string) (paramSet, error) {
+	var errs error
+
+	result := paramSet{}
+
+	for idx, pb := range params {
+		name := pb.GetName()
+
+		if name == "" {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("%w: parameter requires name", sketcherror.ErrIncompleteConfig), "params", idx))
+			continue
+		}
+
+		if _, ok := result[name]; ok {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("%w: duplicate parameter %q", sketcherror.ErrBadConfig, name), "params", idx))
+			continue
+		}
+
+		value, err := paramValueFromProto(pb)
+		if err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("parameter %q: %w", name, err), "params", idx))
+			continue
+		}
+
+		fullName := name
+
+		if namespace != "" {
+			fullName = namespaced(namespace, name)
+		}
+
+		if override, ok := l.params[fullName]; ok {
+			if value, err = value.override(override); err != nil {
+				errs = multierr.Append(errs, atPath(fmt.Errorf("parameter %q: %w", fullName, err), "params", idx))
+			}
+
+			if l.usedParams == nil {
+				l.usedParams = map[string]bool{}
+			}
+
+			l.usedParams[fullName] = true
+		}
+
+		result[name] = value
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	return result, nil
+}
+
+// checkParams verifies that all parameter overrides were used. Unknown
+// parameters are reported in order of their name.
+func (l *sketchLoader) checkParams() error {
+	var errs ConfigErrors
+
+	for _, name := range slices.Sorted(maps.Keys(l.params)) {
+		if !l.usedParams[name] {
+			errs = append(errs, &ConfigError{
+				Err: fmt.Errorf("%w: unknown parameter %q", sketcherror.ErrBadConfig, name),
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// resolve expands parameters, imports and templates of a sketch read from the
+// given file, if any. Relative import paths are based on the given directory.
+// Names of parameter overrides are prefixed with the namespace, if any.
+// Problems are reported as [ConfigErrors].
+func (l *sketchLoader) resolve(pb *sketchpb.Sketch, src *sourceFile, dir, namespace string) (*resolvedSketch, error) {
+	result, err := l.resolveFields(pb, src, dir, namespace)
+	if err != nil {
+		return nil, collectConfigErrors(err, sourceRef{file: src}.position)
+	}
+
+	return result, nil
+}
+
+// resolveFields checks all parameters, imports, templates and nodes before
+// returning the combined problems.
+func (l *sketchLoader) resolveFields(pb *sketchpb.Sketch, src *sourceFile, dir, namespace string) (*resolvedSketch, error) {
+	var errs error
+
+	pb = proto.Clone(pb).(*sketchpb.Sketch)
+
+	// Parameter references can only be verified if all parameters are valid.
+	if params, err := l.resolveParams(pb.GetParams(), namespace); err != nil {
+		errs = multierr.Append(errs, err)
+	} else {
+		for idx, node := range pb.GetNodes() {
+			if err := params.apply(node.ProtoReflect()); err != nil {
+				errs = multierr.Append(errs, atPath(fmt.Errorf("node %q: %w", node.GetName(), err), "nodes", idx))
+			}
+		}
+
+		for idx, tmpl := range pb.GetTemplates() {
+			if err := params.apply(tmpl.ProtoReflect()); err != nil {
+				errs = multierr.Append(errs, atPath(fmt.Errorf("node %q: %w", tmpl.GetName(), err), "templates", idx))
+			}
+		}
+
+		for idx, c := range pb.GetComputed() {
+			if err := params.apply(c.ProtoReflect()); err != nil {
+				errs = multierr.Append(errs, atPath(fmt.Errorf("computed node %q: %w", c.GetName(), err), "computed", idx))
+			}
+		}
+	}
+
+	result := &resolvedSketch{
+		pb: &sketchpb.Sketch{
+			Tags:          pb.GetTags(),
+			Normalization: pb.GetNormalization(),
+		},
+		templates: map[string]*sketchpb.Node{},
+		source:    src,
+	}
+
+	for idx, imp := range pb.GetImports() {
+		if err := l.importSketch(result, imp, dir, namespace); err != nil {
+			errs = multierr.Append(errs, atPath(err, "imports", idx))
+		}
+	}
+
+	for idx, tmpl := range pb.GetTemplates() {
+		name := tmpl.GetName()
+
+		if name == "" {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("%w: template requires name", sketcherror.ErrIncompleteConfig), "templates", idx))
+			continue
+		}
+
+		if _, ok := result.templates[name]; ok {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("%w: duplicate template %q", sketcherror.ErrBadConfig, name), "templates", idx))
+			continue
+		}
+
+		result.templates[name] = tmpl
+	}
+
+	for idx, node := range pb.GetNodes() {
+		expanded, err := result.expand(proto.Clone(node).(*sketchpb.Node), nil)
+		if err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("node %q: %w", node.GetName(), err), "nodes", idx))
+			continue
+		}
+
+		result.pb.Nodes = append(result.pb.Nodes, expanded)
+		result.nodeSources = append(result.nodeSources, sourceRef{
+			file: src,
+			path: textproto.Path{}.Append("nodes", idx),
+		})
+	}
+
+	for idx := range pb.GetComputed() {
+		result.computedSources = append(result.computedSources, sourceRef{
+			file: src,
+			path: textproto.Path{}.Append("computed", idx),
+		})
+	}
+
+	result.pb.Computed = append(result.pb.Computed, pb.GetComputed()...)
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	return result, nil
+}
@@ -0,0 +1,364 @@
+package sketch
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestSketchLoader(t *testing.T) {
+	footer := `
+normalization { nfkc: true }
+
+nodes {
+  name: "bank"
+  search_areas {
+    top_left { abs { left {} top { cm: 25 } } }
+    width { cm: 10 }
+    height { cm: 3 }
+  }
+  line_text { regex: "^Bank" }
+}
+
+nodes {
+  name: "iban"
+  adjacent_areas { node: "bank" direction: DOWN lines: 1 }
+  line_text { regex: "IBAN: (?P<iban>\\w+)" }
+  normalization {}
+}
+
+templates {
+  name: "label"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 21 }
+    height { cm: 30 }
+  }
+  line_text { regex: "^Label" bounds_from_match: true }
+  tags: "label"
+}
+
+templates {
+  name: "total"
+  template: "label"
+  line_text { regex: "^Total" }
+}
+`
+
+	fsys := fstest.MapFS{
+		"common/footer.textproto": &fstest.MapFile{Data: []byte(footer)},
+		"common/company.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "footer.textproto" namespace: "footer" }
+`)},
+		"common/checks.textproto": &fstest.MapFile{Data: []byte(`
+nodes { name: "total" line_text {} }
+computed { name: "positive" expr: "total > 0 && has(total.amount)" }
+computed { name: "check" expr: "positive" }
+`)},
+		"cycle/a.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "b.textproto" namespace: "b" }
+`)},
+		"cycle/b.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "a.textproto" namespace: "a" }
+`)},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		input   string
+		wantErr error
+		want    string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "import",
+			input: `
+imports { path: "common/footer.textproto" namespace: "footer" }
+
+nodes {
+  name: "invoice"
+  template: "footer.label"
+  line_text { regex: "^Invoice" }
+  neighbor { node: "footer.bank" direction: UP }
+}
+`,
+			want: `
+nodes {
+  name: "footer.bank"
+  search_areas {
+    top_left { abs { left {} top { cm: 25 } } }
+    width { cm: 10 }
+    height { cm: 3 }
+  }
+  line_text { regex: "^Bank" }
+  normalization { nfkc: true }
+}
+nodes {
+  name: "footer.iban"
+  adjacent_areas { node: "footer.bank" direction: DOWN lines: 1 }
+  line_text { regex: "IBAN: (?P<iban>\\w+)" }
+  normalization {}
+}
+nodes {
+  name: "invoice"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 21 }
+    height { cm: 30 }
+  }
+  line_text { regex: "^Invoice" bounds_from_match: true }
+  neighbor { node: "footer.bank" direction: UP }
+  tags: "label"
+}
+`,
+		},
+		{
+			name: "nested import with template chain",
+			input: `
+imports { path: "common/company.textproto" namespace: "company" }
+
+nodes {
+  name: "total"
+  template: "company.footer.total"
+  search_areas {
+    top_left { abs { left {} top { cm: 20 } } }
+    width { cm: 21 }
+    height { cm: 5 }
+  }
+  tags: "total"
+}
+`,
+			want: `
+nodes {
+  name: "company.footer.bank"
+  search_areas {
+    top_left { abs { left {} top { cm: 25 } } }
+    width { cm: 10 }
+    height { cm: 3 }
+  }
+  line_text { regex: "^Bank" }
+  normalization { nfkc: true }
+}
+nodes {
+  name: "company.footer.iban"
+  adjacent_areas { node: "company.footer.bank" direction: DOWN lines: 1 }
+  line_text { regex: "IBAN: (?P<iban>\\w+)" }
+  normalization {}
+}
+nodes {
+  name: "total"
+  search_areas {
+    top_left { abs { left {} top { cm: 20 } } }
+    width { cm: 21 }
+    height { cm: 5 }
+  }
+  line_text { regex: "^Total" bounds_from_match: true }
+  tags: "total"
+}
+`,
+		},
+		{
+			name: "import with computed nodes",
+			input: `
+imports { path: "common/checks.textproto" namespace: "checks" }
+computed { name: "all" expr: "checks.check" }
+`,
+			want: `
+nodes { name: "checks.total" line_text {} }
+computed { name: "checks.positive" expr: "checks.total > 0 && has(checks.total.amount)" }
+computed { name: "checks.check" expr: "checks.positive" }
+computed { name: "all" expr: "checks.check" }
+`,
+		},
+		{
+			name: "import without namespace",
+			input: `
+imports { path: "common/footer.textproto" }
+`,
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "import cycle",
+			input: `
+imports { path: "cycle/a.textproto" namespace: "a" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "missing file",
+			input: `
+imports { path: "missing.textproto" namespace: "missing" }
+`,
+			wantErr: fs.ErrNotExist,
+		},
+		{
+			name: "path outside file system",
+			input: `
+imports { path: "../outside.textproto" namespace: "outside" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "unknown template",
+			input: `
+nodes { name: "test" template: "missing" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "template cycle",
+			input: `
+templates { name: "a" template: "b" }
+templates { name: "b" template: "a" }
+nodes { name: "test" template: "a" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "duplicate template",
+			input: `
+templates { name: "a" }
+templates { name: "a" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "template without name",
+			input: `
+templates { line_text {} }
+`,
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := &sketchLoader{fsys: fsys}
+
+			got, err := l.resolve(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.Sketch{}), nil, ".", "")
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				want := testutil.MustUnmarshalTextproto(t, tc.want, &sketchpb.Sketch{})
+
+				if diff := cmp.Diff(want, got.pb, protocmp.Transform()); diff != "" {
+					t.Errorf("resolve() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"footer.textproto": &fstest.MapFile{Data: []byte(`
+nodes {
+  name: "bank"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 3 }
+  }
+  line_text {}
+}
+`)},
+		"sketch.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "footer.textproto" namespace: "footer" }
+
+nodes {
+  name: "value"
+  adjacent_areas { node: "footer.bank" direction: RIGHT }
+  line_text {}
+}
+`)},
+		"duplicate.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "footer.textproto" namespace: "footer" }
+
+nodes {
+  name: "footer.bank"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { cm: 10 }
+    height { cm: 3 }
+  }
+  line_text {}
+}
+`)},
+		"unknown-reference.textproto": &fstest.MapFile{Data: []byte(`
+imports { path: "footer.textproto" namespace: "footer" }
+
+nodes {
+  name: "value"
+  adjacent_areas { node: "bank" direction: RIGHT }
+  line_text {}
+}
+`)},
+		"syntax.textproto": &fstest.MapFile{Data: []byte(`nodes {`)},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		file      string
+		wantErr   error
+		wantNodes []string
+	}{
+		{
+			name:      "success",
+			file:      "sketch.textproto",
+			wantNodes: []string{"footer.bank", "value"},
+		},
+		{
+			name:    "duplicate",
+			file:    "duplicate.textproto",
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "unknown reference",
+			file:    "unknown-reference.textproto",
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "missing",
+			file:    "missing.textproto",
+			wantErr: fs.ErrNotExist,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := CompileFile(fsys, tc.file)
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				var got []string
+
+				for _, n := range s.nodes {
+					got = append(got, n.name)
+				}
+
+				if diff := cmp.Diff(tc.wantNodes, got); diff != "" {
+					t.Errorf("Node names diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+
+	if _, err := CompileFile(fsys, "syntax.textproto"); err == nil {
+		t.Errorf("CompileFile() with syntax error succeeded")
+	}
+
+	if _, err := CompileFromTextprotoString(`imports { path: "footer.textproto" namespace: "footer" }`); !cmp.Equal(sketcherror.ErrBadConfig, err, cmpopts.EquateErrors()) {
+		t.Errorf("CompileFromTextprotoString() with import returned %v, want %v", err, sketcherror.ErrBadConfig)
+	}
+}
@@ -0,0 +1,305 @@
+package sketch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+const defaultMultiLineLeftTolerance = 2 * geometry.Pt
+
+// mergedLines combines consecutive lines into a single text element. Lines are
+// separated by newline characters.
+type mergedLines struct {
+	lines  []content.Line
+	text   string
+	bounds geometry.Rect
+}
+
+var _ content.TextElement = (*mergedLines)(nil)
+
+func newMergedLines(lines []content.Line) *mergedLines {
+	m := &mergedLines{
+		lines: lines,
+	}
+
+	texts := make([]string, 0, len(lines))
+
+	for idx, l := range lines {
+		texts = append(texts, l.Text())
+
+		if idx == 0 {
+			m.bounds = l.Bounds()
+		} else {
+			m.bounds = m.bounds.Union(l.Bounds())
+		}
+	}
+
+	m.text = strings.Join(texts, "\n")
+
+	return m
+}
+
+func (m *mergedLines) Bounds() geometry.Rect {
+	return m.bounds
+}
+
+func (m *mergedLines) Text() string {
+	return m.text
+}
+
+func (m *mergedLines) RangeBounds(start, end int) geometry.Rect {
+	if start < 0 || end < start || end > len(m.text) {
+		panic(fmt.Sprintf("range %d-%d is not valid", start, end))
+	}
+
+	var started bool
+	var bounds geometry.Rect
+
+	pos := 0
+
+	for idx, l := range m.lines {
+		text := l.Text()
+
+		if idx > 0 {
+			// Line separator
+			pos++
+		}
+
+		first := max(start-pos, 0)
+		last := min(end-pos, len(text))
+
+		if first < last {
+			if rbounds := l.RangeBounds(first, last); started {
+				bounds = bounds.Union(rbounds)
+			} else {
+				started = true
+				bounds = rbounds
+			}
+		}
+
+		if pos += len(text); pos >= end {
+			break
+		}
+	}
+
+	return bounds
+}
+
+func (m *mergedLines) lineTexts() []string {
+	result := make([]string, 0, len(m.lines))
+
+	for _, l := range m.lines {
+		result = append(result, l.Text())
+	}
+
+	return result
+}
+
+// multiLineLocator collects consecutive lines following an anchor line.
+type multiLineLocator struct {
+	anchor        *textLocator
+	text          *textLocator
+	includeAnchor bool
+	leftTolerance geometry.Length
+	stop          *regexp.Regexp
+	maxLines      int
+
+	// Maximum vertical gap below the previous line.
+	maxLineGap func(prev geometry.Rect) geometry.Length
+}
+
+func newMultiLineLocatorFromProto(pb *sketchpb.Node_MultiLineMatch, norm *normalizer) (*multiLineLocator, error) {
+	var err error
+
+	l := &multiLineLocator{
+		includeAnchor: pb.GetIncludeAnchor(),
+		leftTolerance: defaultMultiLineLeftTolerance,
+		maxLines:      int(pb.GetMaxLines()),
+	}
+
+	if pb.Anchor == nil {
+		return nil, fmt.Errorf("%w: multi-line match requires anchor", sketcherror.ErrIncompleteConfig)
+	}
+
+	if l.anchor, err = newTextLocatorFromProto(pb.GetAnchor(), true, norm); err != nil {
+		return nil, fmt.Errorf("multi-line anchor: %w", err)
+	}
+
+	if l.text, err = newTextLocatorFromProto(pb.GetText(), false, norm); err != nil {
+		return nil, fmt.Errorf("multi-line text: %w", err)
+	}
+
+	if pb.LeftTolerance != nil {
+		if l.leftTolerance, err = geometry.LengthFromProto(pb.GetLeftTolerance()); err != nil {
+			return nil, fmt.Errorf("multi-line left tolerance: %w", err)
+		}
+	}
+
+	var maxLineGap geometry.Length
+
+	if pb.MaxLineGap == nil {
+		l.maxLineGap = func(prev geometry.Rect) geometry.Length {
+			return prev.Height() / 2
+		}
+	} else {
+		if maxLineGap, err = geometry.LengthFromProto(pb.GetMaxLineGap()); err != nil {
+			return nil, fmt.Errorf("multi-line gap: %w", err)
+		}
+
+		// An explicit zero permits no gap at all.
+		l.maxLineGap = func(geometry.Rect) geometry.Length {
+			return maxLineGap
+		}
+	}
+
+	if l.leftTolerance < 0 || maxLineGap < 0 {
+		return nil, fmt.Errorf("%w: multi-line tolerance and gap must not be negative", sketcherror.ErrBadConfig)
+	}
+
+	if pb.GetStopRegex() != "" {
+		if l.stop, err = regexp.Compile(pb.GetStopRegex()); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// nextLine finds the line immediately below the given line. Lines must start
+// below the vertical center of the previous line.
+func (l *multiLineLocator) nextLine(cb documentPage, anchor, prev content.Line) (content.Line, error) {
+	prevBounds := prev.Bounds()
+	left := anchor.Bounds().Left
+
+	maxGap := l.maxLineGap(prevBounds)
+
+	area := geometry.Rect{
+		Left:   left - l.leftTolerance,
+		Top:    prevBounds.Center().Top,
+		Right:  prevBounds.Right.Max(left + l.leftTolerance),
+		Bottom: prevBounds.Bottom + maxGap,
+	}
+
+	var result content.Line
+
+	if err := cb.VisitElementsIntersecting(area, func(elem content.Element) error {
+		line, ok := elem.(content.Line)
+		if !ok {
+			return nil
+		}
+
+		bounds := line.Bounds()
+
+		if bounds.Top <= area.Top || bounds.Top-prevBounds.Bottom > maxGap ||
+			(bounds.Left-left).Abs() > l.leftTolerance {
+			return nil
+		}
+
+		if result == nil || bounds.Top < result.Bounds().Top {
+			result = line
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// collect gathers the lines following the anchor.
+func (l *multiLineLocator) collect(cb documentPage, anchor content.Line) ([]content.Line, error) {
+	var lines []content.Line
+
+	if l.includeAnchor {
+		lines = append(lines, anchor)
+	}
+
+	for prev := anchor; l.maxLines == 0 || len(lines) < l.maxLines; {
+		next, err := l.nextLine(cb, anchor, prev)
+		if err != nil {
+			return nil, err
+		}
+
+		if next == nil || (l.stop != nil && l.stop.MatchString(next.Text())) {
+			break
+		}
+
+		lines = append(lines, next)
+		prev = next
+	}
+
+	return lines, nil
+}
+
+func (l *multiLineLocator) evaluate(cb documentPage, elem content.Element, trace *AreaTrace) (*locatorMatch, error) {
+	line, ok := elem.(content.Line)
+	if !ok {
+		return nil, nil
+	}
+
+	if l.anchor.evaluateText(line) == nil {
+		trace.add(line, RejectedNoMatch)
+		return nil, nil
+	}
+
+	lines, err := l.collect(cb, line)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		trace.add(line, RejectedNoMatch)
+		return nil, nil
+	}
+
+	merged := newMergedLines(lines)
+
+	m := l.text.evaluateTraced(merged, trace)
+	if m == nil {
+		return nil, nil
+	}
+
+	apply := m.apply
+	texts := merged.lineTexts()
+
+	m.apply = func(n *Node) {
+		apply(n)
+		n.lines = texts
+	}
+
+	return m, nil
+}
+
+func (l *multiLineLocator) locate(cb documentPage, bounds geometry.Rect, policy containment, trace *AreaTrace) ([]*locatorMatch, error) {
+	var result []*locatorMatch
+
+	visitor := func(elem content.Element) error {
+		if !policy.accepts(bounds, elem.Bounds()) {
+			if line, ok := elem.(content.Line); ok {
+				trace.add(line, RejectedOutsideArea)
+			}
+
+			return nil
+		}
+
+		m, err := l.evaluate(cb, elem, trace)
+		if err == nil && m != nil {
+			result = append(result, m)
+		}
+
+		return err
+	}
+
+	if err := cb.VisitElementsIntersecting(bounds, visitor); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hansmi/dossier/internal/flexrect"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+// negativeMatch rejects candidates if matching content is found in a related
+// area.
+type negativeMatch struct {
+	// Fixed area. Nil if the area is relative to the candidate.
+	area *flexrect.FlexRect
+
+	// Area next to the candidate. Nil if a fixed area is used.
+	relative *flexrect.AdjacentArea
+
+	locator sketchNodeLocator
+}
+
+func negativeMatchFromProto(pb *sketchpb.Node_NegativeMatch, norm *normalizer) (*negativeMatch, error) {
+	var err error
+
+	m := &negativeMatch{}
+
+	switch a := pb.GetArea().(type) {
+	case *sketchpb.Node_NegativeMatch_SearchArea:
+		m.area, err = flexrect.FromProto(a.SearchArea)
+
+	case *sketchpb.Node_NegativeMatch_AdjacentArea:
+		m.relative, err = flexrect.RelativeAreaFromProto(a.AdjacentArea)
+
+	default:
+		err = fmt.Errorf("%w: negative match requires area", sketcherror.ErrIncompleteConfig)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("negative match: %w", err)
+	}
+
+	switch t := pb.GetMatcher().(type) {
+	case *sketchpb.Node_NegativeMatch_BlockText:
+		m.locator, err = newTextLocatorFromProto(t.BlockText, false, norm)
+
+	case *sketchpb.Node_NegativeMatch_LineText:
+		m.locator, err = newTextLocatorFromProto(t.LineText, true, norm)
+
+	default:
+		err = fmt.Errorf("%w: negative match has unsupported match type %T", sketcherror.ErrBadConfig, t)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *negativeMatch) requiredNodeFeatures() []flexrect.NodeFeature {
+	if m.area != nil {
+		return m.area.RequiredNodeFeatures()
+	}
+
+	return m.relative.RequiredNodeFeatures()
+}
+
+// matches reports whether content matching the condition is found in the area
+// related to the candidate. Conditions with areas referencing nodes of unknown
+// position never match.
+func (m *negativeMatch) matches(cb sketchNodeSearchCallbacks, trace *NodeTrace, candidate geometry.Rect, policy containment, excluded func(geometry.Rect) bool) (bool, error) {
+	var area geometry.Rect
+	var err error
+
+	if m.area != nil {
+		area, err = m.area.Resolve(cb)
+	} else {
+		area, err = m.relative.ResolveFrom(candidate, cb.Size())
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrNodePositionUnknown) {
+			trace.unresolved(err)
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	found, err := m.locator.locate(cb, area, policy, nil)
+	if err != nil {
+		return false, err
+	}
+
+	for _, f := range found {
+		if !excluded(f.bounds) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
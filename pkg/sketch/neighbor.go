@@ -0,0 +1,86 @@
+package sketch
+
+import (
+	"fmt"
+
+	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/internal/flexrect"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+// neighborSearch looks for content next to another node.
+type neighborSearch struct {
+	node      string
+	direction geometry.Direction
+	opts      dossier.DirectionalSearchOptions
+
+	// Area covered by the search relative to the referenced node.
+	area *flexrect.AdjacentArea
+}
+
+func neighborSearchFromProto(pb *sketchpb.NeighborSearch) (*neighborSearch, error) {
+	var err error
+
+	s := &neighborSearch{
+		node: pb.GetNode(),
+		opts: dossier.DirectionalSearchOptions{
+			MinOverlap: pb.GetMinOverlap(),
+		},
+	}
+
+	if s.node == "" {
+		return nil, fmt.Errorf("%w: neighbor search requires node name", sketcherror.ErrIncompleteConfig)
+	}
+
+	if s.direction, err = flexrect.DirectionFromProto(pb.GetDirection()); err != nil {
+		return nil, fmt.Errorf("neighbor search: %w", err)
+	}
+
+	if pb.MaxDistance != nil {
+		if s.opts.MaxDistance, err = geometry.LengthFromProto(pb.GetMaxDistance()); err != nil {
+			return nil, err
+		}
+
+		if s.opts.MaxDistance <= 0 {
+			return nil, fmt.Errorf("%w: neighbor search requires positive maximum distance, got %s",
+				sketcherror.ErrBadConfig, s.opts.MaxDistance.String())
+		}
+	}
+
+	if !(s.opts.MinOverlap >= 0 && s.opts.MinOverlap <= 1) {
+		return nil, fmt.Errorf("%w: neighbor search overlap must be in range [0, 1], got %v",
+			sketcherror.ErrBadConfig, s.opts.MinOverlap)
+	}
+
+	areaPb := &sketchpb.AdjacentArea{
+		Direction: pb.GetDirection(),
+	}
+
+	if pb.MaxDistance != nil {
+		areaPb.Extent = &sketchpb.AdjacentArea_Distance{Distance: pb.GetMaxDistance()}
+	}
+
+	if s.area, err = flexrect.RelativeAreaFromProto(areaPb); err != nil {
+		return nil, fmt.Errorf("neighbor search: %w", err)
+	}
+
+	return s, nil
+}
+
+// requiredNodeFeatures returns the features on the referenced node which are
+// used to determine its bounds.
+func (s *neighborSearch) requiredNodeFeatures() []flexrect.NodeFeature {
+	return flexrect.NodeBoundsFeatures(s.node)
+}
+
+func (s *neighborSearch) referenceBounds(cb flexrect.Callbacks) (geometry.Rect, error) {
+	return flexrect.ResolveNodeBounds(cb, s.node)
+}
+
+// searchArea returns the area covered by the search. Without a maximum
+// distance the area extends to the edge of the page.
+func (s *neighborSearch) searchArea(ref geometry.Rect, size geometry.Size) (geometry.Rect, error) {
+	return s.area.ResolveFrom(ref, size)
+}
@@ -14,6 +14,10 @@ type Node struct {
 	searchAreas []geometry.Rect
 	text        *string
 	textMatch   *TextMatch
+	lines       []string
+	alternative *nodeAlternative
+	err         error
+	trace       *NodeTrace
 }
 
 func (n *Node) Name() string {
@@ -44,6 +48,12 @@ func (n *Node) FeaturePosition(feature sketchpb.NodeFeature) (geometry.Point, er
 	return n.s.featurePosition(n.bounds, feature)
 }
 
+// Err returns the reason for a node being invalid if it's anything other
+// than not finding a match, e.g. [ErrAmbiguousMatch].
+func (n *Node) Err() error {
+	return n.err
+}
+
 func (n *Node) Text() string {
 	if n.text != nil {
 		return *n.text
@@ -56,6 +66,40 @@ func (n *Node) TextMatch() *TextMatch {
 	return n.textMatch
 }
 
+// Lines returns the text of individual lines for multi-line matches. Nil is
+// returned for other matches.
+func (n *Node) Lines() []string {
+	return n.lines
+}
+
+// EditDistance returns the number of edits between the matched and the
+// expected text for fuzzy matches. The second return value is false for other
+// matches.
+func (n *Node) EditDistance() (int, bool) {
+	if n.textMatch == nil {
+		return 0, false
+	}
+
+	return n.textMatch.EditDistance()
+}
+
+// Alternative returns the name of the node alternative whose candidates were
+// used. The second return value is false if the node's own matcher was used
+// or no candidates were found.
+func (n *Node) Alternative() (string, bool) {
+	if n.alternative == nil {
+		return "", false
+	}
+
+	return n.alternative.name, true
+}
+
+// Trace returns how the node was searched. Nil is returned unless tracing was
+// enabled using [WithTrace].
+func (n *Node) Trace() *NodeTrace {
+	return n.trace
+}
+
 func (n *Node) AsProto(unit geometry.LengthUnit) *reportpb.Node {
 	pb := &reportpb.Node{
 		Name:  n.s.name,
@@ -67,6 +111,18 @@ func (n *Node) AsProto(unit geometry.LengthUnit) *reportpb.Node {
 		pb.SearchAreas = append(pb.SearchAreas, area.AsProto(unit))
 	}
 
+	if n.err != nil {
+		pb.Error = n.err.Error()
+	}
+
+	if name, ok := n.Alternative(); ok {
+		pb.Alternative = wrapperspb.String(name)
+	}
+
+	if n.trace != nil {
+		pb.Trace = n.trace.AsProto(unit)
+	}
+
 	if pb.GetValid() {
 		pb.Bounds = n.bounds.AsProto(unit)
 
@@ -74,10 +130,16 @@ func (n *Node) AsProto(unit geometry.LengthUnit) *reportpb.Node {
 			pb.Text = wrapperspb.String(*n.text)
 		}
 
+		pb.Lines = n.lines
+
 		if n.textMatch != nil {
 			for _, g := range n.textMatch.Groups() {
 				pb.TextMatchGroups = append(pb.TextMatchGroups, g.AsProto())
 			}
+
+			if distance, ok := n.textMatch.EditDistance(); ok {
+				pb.EditDistance = wrapperspb.UInt32(uint32(distance))
+			}
 		}
 	}
 
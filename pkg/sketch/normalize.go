@@ -0,0 +1,297 @@
+package sketch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"golang.org/x/text/unicode/norm"
+)
+
+// textSpan is a byte range in the original text.
+type textSpan struct {
+	start, end int
+}
+
+// normalizedText is the result of normalizing text. Each byte of the
+// normalized text is associated with the range of the original text it was
+// derived from.
+type normalizedText struct {
+	text    string
+	spans   []textSpan
+	origLen int
+}
+
+func newNormalizedText(text string) *normalizedText {
+	spans := make([]textSpan, len(text))
+
+	for pos, r := range text {
+		end := pos + utf8.RuneLen(r)
+
+		for idx := pos; idx < end; idx++ {
+			spans[idx] = textSpan{pos, end}
+		}
+	}
+
+	return &normalizedText{
+		text:    text,
+		spans:   spans,
+		origLen: len(text),
+	}
+}
+
+// originalStart returns the offset in the original text corresponding to the
+// start of a range in the normalized text.
+func (t *normalizedText) originalStart(pos int) int {
+	if pos < len(t.spans) {
+		return t.spans[pos].start
+	}
+
+	return t.origLen
+}
+
+// originalEnd returns the offset in the original text corresponding to the
+// end of a range in the normalized text.
+func (t *normalizedText) originalEnd(pos int) int {
+	if pos <= 0 {
+		return t.originalStart(0)
+	}
+
+	return t.spans[pos-1].end
+}
+
+// originalRange maps a range of the normalized text to the original text.
+// Empty ranges remain empty.
+func (t *normalizedText) originalRange(start, end int) (int, int) {
+	if start == end {
+		pos := t.originalStart(start)
+		return pos, pos
+	}
+
+	return t.originalStart(start), t.originalEnd(end)
+}
+
+// normalizedTextBuilder produces normalized text from the output of
+// a previous step while maintaining the mapping to the original text.
+type normalizedTextBuilder struct {
+	in    *normalizedText
+	buf   strings.Builder
+	spans []textSpan
+}
+
+// emit appends text replacing the input from start to end.
+func (b *normalizedTextBuilder) emit(s string, start, end int) {
+	var span textSpan
+
+	if start < end {
+		span = textSpan{b.in.spans[start].start, b.in.spans[end-1].end}
+	} else {
+		pos := b.in.originalStart(start)
+		span = textSpan{pos, pos}
+	}
+
+	b.buf.WriteString(s)
+
+	for range len(s) {
+		b.spans = append(b.spans, span)
+	}
+}
+
+// copy appends the input from start to end unmodified.
+func (b *normalizedTextBuilder) copy(start, end int) {
+	b.buf.WriteString(b.in.text[start:end])
+	b.spans = append(b.spans, b.in.spans[start:end]...)
+}
+
+func (b *normalizedTextBuilder) result() *normalizedText {
+	return &normalizedText{
+		text:    b.buf.String(),
+		spans:   b.spans,
+		origLen: b.in.origLen,
+	}
+}
+
+const softHyphen = '\u00ad'
+
+func isDash(r rune) bool {
+	switch r {
+	case '\u2010', '\u2011', '\u2012', '\u2013', '\u2014', '\u2015',
+		'\u2212', '\ufe58', '\ufe63', '\uff0d':
+		return true
+	}
+
+	return false
+}
+
+func isHyphen(r rune) bool {
+	return r == '-' || r == '\u2010' || r == softHyphen
+}
+
+// normalizer applies configurable transformations to text before matching.
+type normalizer struct {
+	nfkc               bool
+	unifyDashes        bool
+	joinHyphenation    bool
+	collapseWhitespace bool
+}
+
+func normalizerFromProto(pb *sketchpb.Normalization) *normalizer {
+	if pb == nil {
+		return nil
+	}
+
+	return &normalizer{
+		nfkc:               pb.GetNfkc(),
+		unifyDashes:        pb.GetUnifyDashes(),
+		joinHyphenation:    pb.GetJoinHyphenation(),
+		collapseWhitespace: pb.GetCollapseWhitespace(),
+	}
+}
+
+func applyNFKC(in *normalizedText) *normalizedText {
+	var it norm.Iter
+
+	b := normalizedTextBuilder{in: in}
+
+	it.InitString(norm.NFKC, in.text)
+
+	var pending []byte
+
+	start := 0
+
+	for !it.Done() {
+		// Expansions may be returned as multiple segments without advancing
+		// the input position.
+		pending = append(pending, it.Next()...)
+
+		if end := it.Pos(); end > start {
+			if string(pending) == in.text[start:end] {
+				b.copy(start, end)
+			} else {
+				b.emit(string(pending), start, end)
+			}
+
+			pending = pending[:0]
+			start = end
+		}
+	}
+
+	if len(pending) > 0 {
+		b.emit(string(pending), start, len(in.text))
+	}
+
+	return b.result()
+}
+
+func applyUnifyDashes(in *normalizedText) *normalizedText {
+	b := normalizedTextBuilder{in: in}
+
+	for pos, r := range in.text {
+		end := pos + utf8.RuneLen(r)
+
+		if isDash(r) {
+			b.emit("-", pos, end)
+		} else {
+			b.copy(pos, end)
+		}
+	}
+
+	return b.result()
+}
+
+// applyJoinHyphenation removes soft hyphens and joins words hyphenated at the
+// end of a line if the next line continues with a lowercase letter.
+func applyJoinHyphenation(in *normalizedText) *normalizedText {
+	b := normalizedTextBuilder{in: in}
+	text := in.text
+
+	var prev rune
+
+	for pos := 0; pos < len(text); {
+		r, size := utf8.DecodeRuneInString(text[pos:])
+
+		if isHyphen(r) && unicode.IsLetter(prev) && strings.HasPrefix(text[pos+size:], "\n") {
+			if next, _ := utf8.DecodeRuneInString(text[pos+size+1:]); unicode.IsLower(next) {
+				// Drop hyphen and line break
+				pos += size + 1
+				continue
+			}
+		}
+
+		if r == softHyphen {
+			pos += size
+			continue
+		}
+
+		b.copy(pos, pos+size)
+		prev = r
+		pos += size
+	}
+
+	return b.result()
+}
+
+// applyCollapseWhitespace replaces runs of whitespace with a single space or,
+// if the run contains a line break, a single line break.
+func applyCollapseWhitespace(in *normalizedText) *normalizedText {
+	b := normalizedTextBuilder{in: in}
+	text := in.text
+
+	for pos := 0; pos < len(text); {
+		r, size := utf8.DecodeRuneInString(text[pos:])
+
+		if !unicode.IsSpace(r) {
+			b.copy(pos, pos+size)
+			pos += size
+			continue
+		}
+
+		end := pos
+		newline := false
+
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+
+			newline = newline || r == '\n'
+			end += size
+		}
+
+		if newline {
+			b.emit("\n", pos, end)
+		} else {
+			b.emit(" ", pos, end)
+		}
+
+		pos = end
+	}
+
+	return b.result()
+}
+
+// normalize transforms text according to the configured steps. The result
+// maps back to offsets in the original text.
+func (n *normalizer) normalize(text string) *normalizedText {
+	result := newNormalizedText(text)
+
+	if n.nfkc {
+		result = applyNFKC(result)
+	}
+
+	if n.unifyDashes {
+		result = applyUnifyDashes(result)
+	}
+
+	if n.joinHyphenation {
+		result = applyJoinHyphenation(result)
+	}
+
+	if n.collapseWhitespace {
+		result = applyCollapseWhitespace(result)
+	}
+
+	return result
+}
@@ -0,0 +1,153 @@
+package sketch
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+func TestNormalize(t *testing.T) {
+	type mapping struct {
+		Start, End         int
+		OrigStart, OrigEnd int
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     string
+		text     string
+		want     string
+		mappings []mapping
+	}{
+		{
+			name: "disabled",
+			text: "\ufb01nal\u2013",
+			want: "\ufb01nal\u2013",
+			mappings: []mapping{
+				{0, 3, 0, 3},
+				{3, 6, 3, 6},
+			},
+		},
+		{
+			name: "ligature",
+			opts: `nfkc: true`,
+			text: "\ufb01nal",
+			want: "final",
+			mappings: []mapping{
+				{0, 1, 0, 3},
+				{1, 2, 0, 3},
+				{2, 5, 3, 6},
+				{5, 5, 6, 6},
+			},
+		},
+		{
+			name: "decomposed umlaut",
+			opts: `nfkc: true`,
+			text: "u\u0308ber",
+			want: "\u00fcber",
+			mappings: []mapping{
+				{0, 2, 0, 3},
+				{2, 5, 3, 6},
+			},
+		},
+		{
+			name: "non-breaking and thin space",
+			opts: `nfkc: true`,
+			text: "a\u00a0b\u2009c",
+			want: "a b c",
+			mappings: []mapping{
+				{1, 2, 1, 3},
+				{2, 5, 3, 8},
+			},
+		},
+		{
+			name: "dashes",
+			opts: `unify_dashes: true`,
+			text: "1\u20132 \u2212x",
+			want: "1-2 -x",
+			mappings: []mapping{
+				{0, 3, 0, 5},
+				{4, 6, 6, 10},
+			},
+		},
+		{
+			name: "hyphenation",
+			opts: `join_hyphenation: true`,
+			text: "Rech-\nnung, Ober-\nStadt, Soft\u00adhyphen",
+			want: "Rechnung, Ober-\nStadt, Softhyphen",
+			mappings: []mapping{
+				{0, 8, 0, 10},
+				{4, 8, 6, 10},
+				{23, 33, 25, 37},
+			},
+		},
+		{
+			name: "whitespace",
+			opts: `collapse_whitespace: true`,
+			text: "a \t b\n  c\u00a0\u00a0d",
+			want: "a b\nc d",
+			mappings: []mapping{
+				{1, 2, 1, 4},
+				{3, 4, 5, 8},
+				{4, 7, 8, 14},
+			},
+		},
+		{
+			name: "combined",
+			opts: `nfkc: true unify_dashes: true join_hyphenation: true collapse_whitespace: true`,
+			text: "Pro\ufb01t\u2010\nmargin:\u2002\u2212 12",
+			want: "Profitmargin: - 12",
+			mappings: []mapping{
+				{0, 12, 0, 17},
+				{14, 18, 21, 27},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			n := normalizerFromProto(testutil.MustUnmarshalTextproto(t, tc.opts, &sketchpb.Normalization{}))
+
+			got := n.normalize(tc.text)
+
+			if diff := cmp.Diff(tc.want, got.text); diff != "" {
+				t.Errorf("normalize() diff (-want +got):\n%s", diff)
+			}
+
+			for _, m := range tc.mappings {
+				start, end := got.originalRange(m.Start, m.End)
+
+				if diff := cmp.Diff(m, mapping{m.Start, m.End, start, end}); diff != "" {
+					t.Errorf("originalRange() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizedTextMatch(t *testing.T) {
+	n := normalizerFromProto(&sketchpb.Normalization{
+		Nfkc:        true,
+		UnifyDashes: true,
+	})
+
+	text := "\ufb01le no\u2013 42"
+	normalized := n.normalize(text)
+
+	m := evaluateMatch(regexp.MustCompile(`file no- (?P<num>\d+)`), normalized.text)
+	if m == nil {
+		t.Fatalf("evaluateMatch() returned nil")
+	}
+
+	m.normalized = normalized
+
+	want := []TextMatchGroup{
+		{End: len(text), Text: "file no- 42"},
+		{Name: "num", Start: len(text) - 2, End: len(text), Text: "42"},
+	}
+
+	if diff := cmp.Diff(want, m.Groups()); diff != "" {
+		t.Errorf("Match groups diff (-want +got):\n%s", diff)
+	}
+}
@@ -14,13 +14,17 @@ type PageReport struct {
 	size   geometry.Size
 	nodes  []*Node
 	byName map[string]*Node
+
+	computed       []*Computed
+	computedByName map[string]*Computed
 }
 
 func newPageReport(p *dossier.Page) *PageReport {
 	return &PageReport{
-		num:    p.Number(),
-		size:   p.Size(),
-		byName: map[string]*Node{},
+		num:            p.Number(),
+		size:           p.Size(),
+		byName:         map[string]*Node{},
+		computedByName: map[string]*Computed{},
 	}
 }
 
@@ -29,6 +33,11 @@ func (p *PageReport) appendNode(n *Node) {
 	p.byName[n.Name()] = n
 }
 
+func (p *PageReport) appendComputed(c *Computed) {
+	p.computed = append(p.computed, c)
+	p.computedByName[c.Name()] = c
+}
+
 // Number returns the 1-based page number.
 func (p *PageReport) Number() int {
 	return p.num
@@ -46,13 +55,40 @@ func (p *PageReport) NodeByName(name string) *Node {
 	return p.byName[name]
 }
 
+// Computed returns the results of all computed nodes in the order of their
+// definition.
+func (p *PageReport) Computed() []*Computed {
+	return p.computed
+}
+
+func (p *PageReport) ComputedByName(name string) *Computed {
+	return p.computedByName[name]
+}
+
+// ChecksFailed reports whether any computed node failed or produced an
+// error.
+func (p *PageReport) ChecksFailed() bool {
+	for _, c := range p.computed {
+		if c.status == ComputedFailed || c.status == ComputedError {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p *PageReport) NodeFeaturePosition(name string, feature sketchpb.NodeFeature) (geometry.Point, error) {
 	n := p.NodeByName(name)
 	if n == nil {
 		return geometry.Point{}, fmt.Errorf("%w: node %q not found", ErrNodePositionUnknown, name)
 	}
 
-	return n.FeaturePosition(feature)
+	pos, err := n.FeaturePosition(feature)
+	if err != nil {
+		return geometry.Point{}, fmt.Errorf("node %q: %w", name, err)
+	}
+
+	return pos, nil
 }
 
 func (p *PageReport) AsProto(unit geometry.LengthUnit) *reportpb.Page {
@@ -65,5 +101,9 @@ func (p *PageReport) AsProto(unit geometry.LengthUnit) *reportpb.Page {
 		pb.Nodes = append(pb.Nodes, n.AsProto(unit))
 	}
 
+	for _, c := range p.computed {
+		pb.Computed = append(pb.Computed, c.AsProto())
+	}
+
 	return pb
 }
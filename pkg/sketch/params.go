@@ -0,0 +1,174 @@
+package sketch
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/geometrypb"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"go.uber.org/multierr"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// paramReferencePattern matches parameter references in texts, e.g.
+// "${customer}".
+var paramReferencePattern = regexp.MustCompile(`\$\{([^{}]*)\}`)
+
+var lengthMessageName = (*geometrypb.Length)(nil).ProtoReflect().Descriptor().FullName()
+
+// paramTextFields are string fields in which parameter references are
+// expanded.
+var paramTextFields = map[protoreflect.FullName]protoreflect.Name{
+	(*sketchpb.Node_TextMatch)(nil).ProtoReflect().Descriptor().FullName():      "regex",
+	(*sketchpb.Node_Fuzzy)(nil).ProtoReflect().Descriptor().FullName():          "text",
+	(*sketchpb.Node_MultiLineMatch)(nil).ProtoReflect().Descriptor().FullName(): "stop_regex",
+	(*sketchpb.ComputedNode)(nil).ProtoReflect().Descriptor().FullName():        "expr",
+}
+
+// paramValue is the value of a sketch parameter. Exactly one of the fields is
+// used.
+type paramValue struct {
+	text   string
+	length *geometrypb.Length
+}
+
+// paramValueFromProto returns the default value of a parameter.
+func paramValueFromProto(pb *sketchpb.Sketch_Param) (paramValue, error) {
+	switch v := pb.GetValue().(type) {
+	case *sketchpb.Sketch_Param_StringValue:
+		return paramValue{text: v.StringValue}, nil
+
+	case *sketchpb.Sketch_Param_LengthValue:
+		if _, err := geometry.LengthFromProto(v.LengthValue); err != nil {
+			return paramValue{}, multierr.Combine(sketcherror.ErrBadConfig, err)
+		}
+
+		return paramValue{length: v.LengthValue}, nil
+
+	case nil:
+		return paramValue{}, fmt.Errorf("%w: default value required", sketcherror.ErrIncompleteConfig)
+	}
+
+	return paramValue{}, fmt.Errorf("%w: unsupported value type %T", sketcherror.ErrBadConfig, pb.GetValue())
+}
+
+// override replaces the value with one of the same type. Lengths may be given
+// as strings, e.g. "12mm".
+func (v paramValue) override(value any) (paramValue, error) {
+	if v.length == nil {
+		if s, ok := value.(string); ok {
+			return paramValue{text: s}, nil
+		}
+
+		return v, fmt.Errorf("%w: string required, got %T", sketcherror.ErrBadConfig, value)
+	}
+
+	var length geometry.Length
+
+	switch value := value.(type) {
+	case geometry.Length:
+		length = value
+
+	case string:
+		var err error
+
+		if length, err = geometry.ParseLength(value); err != nil {
+			return v, multierr.Combine(sketcherror.ErrBadConfig, err)
+		}
+
+	default:
+		return v, fmt.Errorf("%w: length required, got %T", sketcherror.ErrBadConfig, value)
+	}
+
+	return paramValue{length: length.AsProto(geometry.Pt)}, nil
+}
+
+// paramSet contains the parameters of a single sketch file.
+type paramSet map[string]paramValue
+
+// expandText replaces all parameter references in a text with the parameter
+// values.
+func (p paramSet) expandText(text string) (string, error) {
+	var err error
+
+	result := paramReferencePattern.ReplaceAllStringFunc(text, func(ref string) string {
+		name := paramReferencePattern.FindStringSubmatch(ref)[1]
+
+		value, ok := p[name]
+
+		switch {
+		case err != nil:
+		case !ok:
+			err = fmt.Errorf("%w: unknown parameter %q", sketcherror.ErrBadConfig, name)
+		case value.length != nil:
+			err = fmt.Errorf("%w: length parameter %q can't be used in text", sketcherror.ErrBadConfig, name)
+		default:
+			return value.text
+		}
+
+		return ref
+	})
+
+	return result, err
+}
+
+// apply expands all parameter references within a message.
+func (p paramSet) apply(m protoreflect.Message) error {
+	desc := m.Descriptor()
+
+	if desc.FullName() == lengthMessageName {
+		l := m.Interface().(*geometrypb.Length)
+
+		if ref, ok := l.GetValue().(*geometrypb.Length_Param); ok {
+			value, ok := p[ref.Param]
+
+			if !ok {
+				return fmt.Errorf("%w: unknown parameter %q", sketcherror.ErrBadConfig, ref.Param)
+			}
+
+			if value.length == nil {
+				return fmt.Errorf("%w: parameter %q is not a length", sketcherror.ErrBadConfig, ref.Param)
+			}
+
+			l.Value = proto.Clone(value.length).(*geometrypb.Length).GetValue()
+		}
+
+		return nil
+	}
+
+	if name, ok := paramTextFields[desc.FullName()]; ok {
+		fd := desc.Fields().ByName(name)
+
+		text, err := p.expandText(m.Get(fd).String())
+		if err != nil {
+			return err
+		}
+
+		if text != "" {
+			m.Set(fd, protoreflect.ValueOfString(text))
+		}
+	}
+
+	var err error
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsMap() {
+			return true
+		}
+
+		if fd.IsList() {
+			for idx := 0; err == nil && idx < v.List().Len(); idx++ {
+				err = p.apply(v.List().Get(idx).Message())
+			}
+		} else {
+			err = p.apply(v.Message())
+		}
+
+		return err == nil
+	})
+
+	return err
+}
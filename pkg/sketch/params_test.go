@@ -0,0 +1,290 @@
+package sketch
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestSketchParams(t *testing.T) {
+	fsys := fstest.MapFS{
+		"footer.textproto": &fstest.MapFile{Data: []byte(`
+params { name: "label" string_value: "IBAN" }
+
+templates {
+  name: "iban"
+  line_text { regex: "^${label}: (\\w+)" }
+}
+`)},
+	}
+
+	const sketch = `
+params { name: "customer" string_value: "C-\\d{5}" }
+params { name: "shift" length_value { mm: 3 } }
+
+nodes {
+  name: "customer"
+  search_areas {
+    top_left { abs { left { param: "shift" } top { cm: 1 } } }
+    width { cm: 10 }
+    height { cm: 2 }
+  }
+  line_text { regex: "Customer: (?P<id>${customer})" }
+}
+`
+
+	for _, tc := range []struct {
+		name    string
+		input   string
+		params  map[string]any
+		wantErr error
+		want    string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "defaults",
+			input: sketch,
+			want: `
+nodes {
+  name: "customer"
+  search_areas {
+    top_left { abs { left { mm: 3 } top { cm: 1 } } }
+    width { cm: 10 }
+    height { cm: 2 }
+  }
+  line_text { regex: "Customer: (?P<id>C-\\d{5})" }
+}
+`,
+		},
+		{
+			name:  "overrides",
+			input: sketch,
+			params: map[string]any{
+				"customer": `K\d+`,
+				"shift":    "12pt",
+			},
+			want: `
+nodes {
+  name: "customer"
+  search_areas {
+    top_left { abs { left { pt: 12 } top { cm: 1 } } }
+    width { cm: 10 }
+    height { cm: 2 }
+  }
+  line_text { regex: "Customer: (?P<id>K\\d+)" }
+}
+`,
+		},
+		{
+			name:  "length value",
+			input: sketch,
+			params: map[string]any{
+				"shift": 2 * geometry.Pt,
+			},
+			want: `
+nodes {
+  name: "customer"
+  search_areas {
+    top_left { abs { left { pt: 2 } top { cm: 1 } } }
+    width { cm: 10 }
+    height { cm: 2 }
+  }
+  line_text { regex: "Customer: (?P<id>C-\\d{5})" }
+}
+`,
+		},
+		{
+			name: "imported",
+			input: `
+imports { path: "footer.textproto" namespace: "footer" }
+params { name: "label" string_value: "Account" }
+nodes {
+  name: "iban"
+  template: "footer.iban"
+  multi_line {
+    anchor { regex: "^${label}" }
+    stop_regex: "${label}$"
+    text { fuzzy { text: "${label}" } }
+  }
+}
+`,
+			params: map[string]any{
+				"footer.label": "Konto",
+			},
+			want: `
+nodes {
+  name: "iban"
+  multi_line {
+    anchor { regex: "^Account" }
+    stop_regex: "Account$"
+    text { fuzzy { text: "Account" } }
+  }
+}
+`,
+		},
+		{
+			name: "template from import",
+			input: `
+imports { path: "footer.textproto" namespace: "footer" }
+nodes { name: "iban" template: "footer.iban" }
+`,
+			params: map[string]any{
+				"footer.label": "Konto",
+			},
+			want: `
+nodes {
+  name: "iban"
+  line_text { regex: "^Konto: (\\w+)" }
+}
+`,
+		},
+		{
+			name:  "unknown override",
+			input: sketch,
+			params: map[string]any{
+				"other": "",
+			},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:  "string instead of length",
+			input: sketch,
+			params: map[string]any{
+				"shift": "wide",
+			},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:  "length instead of string",
+			input: sketch,
+			params: map[string]any{
+				"customer": geometry.Cm,
+			},
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "unknown reference in text",
+			input:   `nodes { name: "a" line_text { regex: "${a}" } }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "unknown reference in length",
+			input: `
+nodes {
+  name: "a"
+  search_areas { width { param: "a" } }
+}
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "length in text",
+			input: `
+params { name: "a" length_value { cm: 1 } }
+nodes { name: "a" line_text { regex: "${a}" } }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "string in length",
+			input: `
+params { name: "a" string_value: "" }
+nodes {
+  name: "a"
+  search_areas { width { param: "a" } }
+}
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "default refers to parameter",
+			input:   `params { name: "a" length_value { param: "b" } }`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name:    "missing default",
+			input:   `params { name: "a" }`,
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name:    "missing name",
+			input:   `params { string_value: "" }`,
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "duplicate",
+			input: `
+params { name: "a" string_value: "" }
+params { name: "a" string_value: "" }
+`,
+			wantErr: sketcherror.ErrBadConfig,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := &sketchLoader{fsys: fsys, params: tc.params}
+
+			got, err := l.resolve(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.Sketch{}), nil, ".", "")
+
+			if err == nil {
+				err = l.checkParams()
+			}
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				want := testutil.MustUnmarshalTextproto(t, tc.want, &sketchpb.Sketch{})
+
+				if diff := cmp.Diff(want, got.pb, protocmp.Transform()); diff != "" {
+					t.Errorf("resolve() diff (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileWithParams(t *testing.T) {
+	pb := testutil.MustUnmarshalTextproto(t, `
+params { name: "shift" length_value { cm: 0 } }
+nodes {
+  name: "test"
+  search_areas {
+    top_left { abs { left { param: "shift" } top {} } }
+    width { cm: 1 }
+    height { cm: 1 }
+  }
+  line_text {}
+}
+`, &sketchpb.Sketch{})
+
+	if _, err := CompileWithParams(pb, map[string]any{"shift": "1cm"}); err != nil {
+		t.Errorf("CompileWithParams() failed: %v", err)
+	}
+
+	if _, err := CompileWithParams(pb, map[string]any{"other": "1cm"}); !cmp.Equal(sketcherror.ErrBadConfig, err, cmpopts.EquateErrors()) {
+		t.Errorf("CompileWithParams() with unknown parameter returned %v, want %v", err, sketcherror.ErrBadConfig)
+	}
+
+	_, err := CompileWithParams(pb, map[string]any{"other": "1cm", "another": "2cm"})
+
+	var errs ConfigErrors
+
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("CompileWithParams() with two unknown parameters returned %v, want two errors", err)
+	}
+
+	if got := pb.GetNodes()[0].GetSearchAreas()[0].GetTopLeft().GetAbs().GetLeft().GetParam(); got != "shift" {
+		t.Errorf("CompileWithParams() modified its input: %q", got)
+	}
+}
@@ -0,0 +1,562 @@
+package sketch
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/reportpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// DiffOption configures the comparison of reports.
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	boundsTolerance geometry.Length
+}
+
+// WithBoundsTolerance sets the largest movement of a node edge not reported
+// as a change. Defaults to one millimeter like [geometry.EquateLength].
+func WithBoundsTolerance(margin geometry.Length) DiffOption {
+	return func(o *diffOptions) {
+		o.boundsTolerance = margin
+	}
+}
+
+// NodeDiff describes how a node differs between two reports.
+type NodeDiff struct {
+	// 1-based page number.
+	Page int
+
+	// Sketch node name.
+	Name string
+
+	// Node in the old and the new report. Nil if the node is missing.
+	Old *reportpb.Node
+	New *reportpb.Node
+
+	// Names of the changed report fields, e.g. "text" or "bounds". Empty for
+	// added and removed nodes.
+	Changed []string
+
+	// Movement of the node edges from the old to the new report. Only set if
+	// "bounds" is among the changed fields.
+	BoundsDelta geometry.Rect
+}
+
+// Added reports whether the node is only present in the new report.
+func (d *NodeDiff) Added() bool {
+	return d.Old == nil
+}
+
+// Removed reports whether the node is only present in the old report.
+func (d *NodeDiff) Removed() bool {
+	return d.New == nil
+}
+
+// ComputedDiff describes how a computed value differs between two reports.
+type ComputedDiff struct {
+	// 1-based page number.
+	Page int
+
+	// Computed node name.
+	Name string
+
+	// Computed value in the old and the new report. Nil if the value is
+	// missing.
+	Old *reportpb.Computed
+	New *reportpb.Computed
+
+	// Names of the changed report fields, i.e. "status", "value" or "error".
+	// Empty for added and removed values.
+	Changed []string
+}
+
+// Added reports whether the computed value is only present in the new report.
+func (d *ComputedDiff) Added() bool {
+	return d.Old == nil
+}
+
+// Removed reports whether the computed value is only present in the old
+// report.
+func (d *ComputedDiff) Removed() bool {
+	return d.New == nil
+}
+
+// ReportDiff lists the nodes and computed values added, removed or changed
+// between two reports.
+type ReportDiff struct {
+	Nodes    []NodeDiff
+	Computed []ComputedDiff
+}
+
+// Empty reports whether the reports are equivalent.
+func (d *ReportDiff) Empty() bool {
+	return len(d.Nodes) == 0 && len(d.Computed) == 0
+}
+
+// DiffReports compares the nodes of two document reports, e.g. produced by
+// two versions of a sketch.
+func DiffReports(a, b *DocumentReport, opts ...DiffOption) (*ReportDiff, error) {
+	return DiffReportProtos(a.AsProto(geometry.Pt), b.AsProto(geometry.Pt), opts...)
+}
+
+// DiffReportProtos compares the nodes and computed values of two document
+// reports in their Protocol Buffer form. Entries are matched by page number and
+// name. Changed and removed entries are listed in the order of the old report,
+// followed by added entries.
+func DiffReportProtos(a, b *reportpb.Document, opts ...DiffOption) (*ReportDiff, error) {
+	o := diffOptions{
+		boundsTolerance: geometry.Millimeter,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	oldPages := pagesByNumber(a)
+	newPages := pagesByNumber(b)
+
+	var numbers []int
+
+	for num := range oldPages {
+		numbers = append(numbers, num)
+	}
+
+	for num := range newPages {
+		if _, ok := oldPages[num]; !ok {
+			numbers = append(numbers, num)
+		}
+	}
+
+	slices.Sort(numbers)
+
+	result := &ReportDiff{}
+
+	for _, num := range numbers {
+		if err := matchByName(oldPages[num].GetNodes(), newPages[num].GetNodes(), func(a, b *reportpb.Node) error {
+			d := NodeDiff{
+				Page: num,
+				Old:  a,
+				New:  b,
+			}
+
+			if a != nil && b != nil {
+				if err := d.compare(o); err != nil {
+					return fmt.Errorf("page %d node %q: %w", num, a.GetName(), err)
+				}
+
+				if len(d.Changed) == 0 {
+					return nil
+				}
+			}
+
+			d.Name = cmp.Or(a, b).GetName()
+
+			result.Nodes = append(result.Nodes, d)
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		if err := matchByName(oldPages[num].GetComputed(), newPages[num].GetComputed(), func(a, b *reportpb.Computed) error {
+			d := ComputedDiff{
+				Page: num,
+				Name: cmp.Or(a, b).GetName(),
+				Old:  a,
+				New:  b,
+			}
+
+			if a != nil && b != nil {
+				if d.compare(); len(d.Changed) == 0 {
+					return nil
+				}
+			}
+
+			result.Computed = append(result.Computed, d)
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// matchByName calls the function for pairs of entries with the same name.
+// Entries only present in one of the lists are paired with nil. Pairs are
+// visited in the order of the old list, followed by new entries.
+func matchByName[T interface {
+	comparable
+	GetName() string
+}](oldItems, newItems []T, fn func(a, b T) error) error {
+	var zero T
+
+	for _, a := range oldItems {
+		b := zero
+
+		if idx := slices.IndexFunc(newItems, func(item T) bool {
+			return item.GetName() == a.GetName()
+		}); idx >= 0 {
+			b = newItems[idx]
+		}
+
+		if err := fn(a, b); err != nil {
+			return err
+		}
+	}
+
+	for _, b := range newItems {
+		if !slices.ContainsFunc(oldItems, func(item T) bool {
+			return item.GetName() == b.GetName()
+		}) {
+			if err := fn(zero, b); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func pagesByNumber(doc *reportpb.Document) map[int]*reportpb.Page {
+	result := map[int]*reportpb.Page{}
+
+	for _, p := range doc.GetPages() {
+		result[int(p.GetNumber())] = p
+	}
+
+	return result
+}
+
+// equalTextMatchGroup compares the captured text of two groups. Offsets are
+// ignored.
+func equalTextMatchGroup(a, b *reportpb.TextMatchGroup) bool {
+	return a.GetName() == b.GetName() && a.GetText() == b.GetText()
+}
+
+// compare records the changed fields of a node present in both reports.
+func (d *NodeDiff) compare(o diffOptions) error {
+	a, b := d.Old, d.New
+
+	for _, i := range []struct {
+		field string
+		equal bool
+	}{
+		{"valid", a.GetValid() == b.GetValid()},
+		{"error", a.GetError() == b.GetError()},
+		{"text", proto.Equal(a.GetText(), b.GetText())},
+		{"text_match_groups", slices.EqualFunc(a.GetTextMatchGroups(), b.GetTextMatchGroups(), equalTextMatchGroup)},
+		{"lines", slices.Equal(a.GetLines(), b.GetLines())},
+		{"edit_distance", proto.Equal(a.GetEditDistance(), b.GetEditDistance())},
+		{"alternative", proto.Equal(a.GetAlternative(), b.GetAlternative())},
+	} {
+		if !i.equal {
+			d.Changed = append(d.Changed, i.field)
+		}
+	}
+
+	// Bounds are only meaningful for valid nodes.
+	if !(a.GetValid() && b.GetValid()) {
+		return nil
+	}
+
+	oldBounds, err := geometry.RectFromProto(a.GetBounds())
+	if err != nil {
+		return err
+	}
+
+	newBounds, err := geometry.RectFromProto(b.GetBounds())
+	if err != nil {
+		return err
+	}
+
+	delta := geometry.Rect{
+		Left:   newBounds.Left - oldBounds.Left,
+		Top:    newBounds.Top - oldBounds.Top,
+		Right:  newBounds.Right - oldBounds.Right,
+		Bottom: newBounds.Bottom - oldBounds.Bottom,
+	}
+
+	if max(delta.Left.Abs(), delta.Top.Abs(), delta.Right.Abs(), delta.Bottom.Abs()) > o.boundsTolerance {
+		d.Changed = append(d.Changed, "bounds")
+		d.BoundsDelta = delta
+	}
+
+	return nil
+}
+
+// compare records the changed fields of a computed value present in both
+// reports.
+func (d *ComputedDiff) compare() {
+	a, b := d.Old, d.New
+
+	for _, i := range []struct {
+		field string
+		equal bool
+	}{
+		{"status", a.GetStatus() == b.GetStatus()},
+		{"value", formatComputedValue(a) == formatComputedValue(b)},
+		{"error", a.GetError() == b.GetError()},
+	} {
+		if !i.equal {
+			d.Changed = append(d.Changed, i.field)
+		}
+	}
+}
+
+// formatComputedValue returns the result of a computed node. The
+// representation differs between value types.
+func formatComputedValue(pb *reportpb.Computed) string {
+	switch v := pb.GetValue().(type) {
+	case *reportpb.Computed_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+
+	case *reportpb.Computed_NumberValue:
+		return v.NumberValue
+
+	case *reportpb.Computed_TextValue:
+		return strconv.Quote(v.TextValue)
+
+	case *reportpb.Computed_DateValue:
+		return v.DateValue
+	}
+
+	return "none"
+}
+
+func formatOptionalText(valid bool, text string) string {
+	if !valid {
+		return "none"
+	}
+
+	return strconv.Quote(text)
+}
+
+func formatDelta(value geometry.Length, unit geometry.LengthUnit) string {
+	s := value.UnitString(unit)
+
+	if value > 0 {
+		s = "+" + s
+	}
+
+	return s
+}
+
+func formatBounds(pb *reportpb.Node, unit geometry.LengthUnit) string {
+	r, err := geometry.RectFromProto(pb.GetBounds())
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("left %s, top %s, right %s, bottom %s",
+		r.Left.UnitString(unit), r.Top.UnitString(unit),
+		r.Right.UnitString(unit), r.Bottom.UnitString(unit))
+}
+
+func textMatchGroupLabel(idx int, g *reportpb.TextMatchGroup) string {
+	if g.GetName() != "" {
+		return strconv.Quote(g.GetName())
+	}
+
+	return strconv.Itoa(idx)
+}
+
+// describeNode lists the extracted values of a node.
+func describeNode(pb *reportpb.Node, unit geometry.LengthUnit) []string {
+	if !pb.GetValid() {
+		if pb.GetError() != "" {
+			return []string{"invalid: " + pb.GetError()}
+		}
+
+		return []string{"invalid"}
+	}
+
+	var lines []string
+
+	if pb.Text != nil {
+		lines = append(lines, "text: "+strconv.Quote(pb.GetText().GetValue()))
+	}
+
+	for idx, g := range pb.GetTextMatchGroups() {
+		lines = append(lines, fmt.Sprintf("group %s: %q", textMatchGroupLabel(idx, g), g.GetText()))
+	}
+
+	return append(lines, "bounds: "+formatBounds(pb, unit))
+}
+
+// describeChanges lists the changed values of a node present in both
+// reports.
+func (d *NodeDiff) describeChanges(unit geometry.LengthUnit) []string {
+	var lines []string
+
+	a, b := d.Old, d.New
+
+	for _, field := range d.Changed {
+		switch field {
+		case "valid":
+			lines = append(lines, fmt.Sprintf("valid: %t -> %t", a.GetValid(), b.GetValid()))
+
+		case "error":
+			lines = append(lines, fmt.Sprintf("error: %q -> %q", a.GetError(), b.GetError()))
+
+		case "text":
+			lines = append(lines, fmt.Sprintf("text: %s -> %s",
+				formatOptionalText(a.Text != nil, a.GetText().GetValue()),
+				formatOptionalText(b.Text != nil, b.GetText().GetValue())))
+
+		case "text_match_groups":
+			oldGroups := a.GetTextMatchGroups()
+			newGroups := b.GetTextMatchGroups()
+
+			for idx := range max(len(oldGroups), len(newGroups)) {
+				var oldGroup, newGroup *reportpb.TextMatchGroup
+
+				if idx < len(oldGroups) {
+					oldGroup = oldGroups[idx]
+				}
+
+				if idx < len(newGroups) {
+					newGroup = newGroups[idx]
+				}
+
+				if oldGroup != nil && newGroup != nil && equalTextMatchGroup(oldGroup, newGroup) {
+					continue
+				}
+
+				lines = append(lines, fmt.Sprintf("group %s: %s -> %s",
+					textMatchGroupLabel(idx, cmp.Or(newGroup, oldGroup)),
+					formatOptionalText(oldGroup != nil, oldGroup.GetText()),
+					formatOptionalText(newGroup != nil, newGroup.GetText())))
+			}
+
+		case "lines":
+			lines = append(lines, fmt.Sprintf("lines: %q -> %q", a.GetLines(), b.GetLines()))
+
+		case "edit_distance":
+			lines = append(lines, fmt.Sprintf("edit distance: %s -> %s",
+				formatOptionalText(a.EditDistance != nil, strconv.Itoa(int(a.GetEditDistance().GetValue()))),
+				formatOptionalText(b.EditDistance != nil, strconv.Itoa(int(b.GetEditDistance().GetValue())))))
+
+		case "alternative":
+			lines = append(lines, fmt.Sprintf("alternative: %s -> %s",
+				formatOptionalText(a.Alternative != nil, a.GetAlternative().GetValue()),
+				formatOptionalText(b.Alternative != nil, b.GetAlternative().GetValue())))
+
+		case "bounds":
+			lines = append(lines, fmt.Sprintf("bounds: left %s, top %s, right %s, bottom %s",
+				formatDelta(d.BoundsDelta.Left, unit), formatDelta(d.BoundsDelta.Top, unit),
+				formatDelta(d.BoundsDelta.Right, unit), formatDelta(d.BoundsDelta.Bottom, unit)))
+		}
+	}
+
+	return lines
+}
+
+// describeComputed lists the status and result of a computed value.
+func describeComputed(pb *reportpb.Computed) []string {
+	lines := []string{
+		"status: " + pb.GetStatus().String(),
+	}
+
+	if pb.GetValue() != nil {
+		lines = append(lines, "value: "+formatComputedValue(pb))
+	}
+
+	if pb.GetError() != "" {
+		lines = append(lines, "error: "+pb.GetError())
+	}
+
+	return lines
+}
+
+// describeChanges lists the changed fields of a computed value present in
+// both reports.
+func (d *ComputedDiff) describeChanges() []string {
+	var lines []string
+
+	a, b := d.Old, d.New
+
+	for _, field := range d.Changed {
+		switch field {
+		case "status":
+			lines = append(lines, fmt.Sprintf("status: %s -> %s", a.GetStatus(), b.GetStatus()))
+
+		case "value":
+			lines = append(lines, fmt.Sprintf("value: %s -> %s", formatComputedValue(a), formatComputedValue(b)))
+
+		case "error":
+			lines = append(lines, fmt.Sprintf("error: %q -> %q", a.GetError(), b.GetError()))
+		}
+	}
+
+	return lines
+}
+
+func writeDiffEntry(buf *strings.Builder, heading string, lines []string) {
+	buf.WriteString(heading)
+	buf.WriteByte('\n')
+
+	for _, line := range lines {
+		fmt.Fprintf(buf, "    %s\n", line)
+	}
+}
+
+// Format writes a human-readable description of the differences. Lengths are
+// written using the given unit.
+func (d *ReportDiff) Format(w io.Writer, unit geometry.LengthUnit) error {
+	var buf strings.Builder
+
+	for _, n := range d.Nodes {
+		var marker string
+		var lines []string
+
+		switch {
+		case n.Added():
+			marker = "+"
+			lines = describeNode(n.New, unit)
+
+		case n.Removed():
+			marker = "-"
+			lines = describeNode(n.Old, unit)
+
+		default:
+			marker = "~"
+			lines = n.describeChanges(unit)
+		}
+
+		writeDiffEntry(&buf, fmt.Sprintf("%s page %d node %q", marker, n.Page, n.Name), lines)
+	}
+
+	for _, c := range d.Computed {
+		var marker string
+		var lines []string
+
+		switch {
+		case c.Added():
+			marker = "+"
+			lines = describeComputed(c.New)
+
+		case c.Removed():
+			marker = "-"
+			lines = describeComputed(c.Old)
+
+		default:
+			marker = "~"
+			lines = c.describeChanges()
+		}
+
+		writeDiffEntry(&buf, fmt.Sprintf("%s page %d computed %q", marker, c.Page, c.Name), lines)
+	}
+
+	_, err := io.WriteString(w, buf.String())
+
+	return err
+}
@@ -0,0 +1,384 @@
+package sketch
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/pkg/pagerange"
+	"github.com/hansmi/dossier/proto/geometrypb"
+	"github.com/hansmi/dossier/proto/reportpb"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func diffTestRect(left, top, right, bottom geometry.Length, unit geometry.LengthUnit) *geometrypb.Rect {
+	return geometry.Rect{Left: left, Top: top, Right: right, Bottom: bottom}.AsProto(unit)
+}
+
+func diffTestNode(name, text string, bounds *geometrypb.Rect) *reportpb.Node {
+	return &reportpb.Node{
+		Name:   name,
+		Valid:  true,
+		Bounds: bounds,
+		Text:   wrapperspb.String(text),
+	}
+}
+
+func diffTestDocument(pages ...[]*reportpb.Node) *reportpb.Document {
+	doc := &reportpb.Document{}
+
+	for idx, nodes := range pages {
+		doc.Pages = append(doc.Pages, &reportpb.Page{
+			Number: int32(1 + idx),
+			Nodes:  nodes,
+		})
+	}
+
+	return doc
+}
+
+func TestDiffReportProtos(t *testing.T) {
+	bounds := diffTestRect(10, 20, 100, 30, geometry.Pt)
+
+	amountOld := &reportpb.Node{
+		Name:   "amount",
+		Valid:  true,
+		Bounds: bounds,
+		Text:   wrapperspb.String("12.00"),
+		TextMatchGroups: []*reportpb.TextMatchGroup{
+			{Start: 0, End: 5, Text: "12.00"},
+			{Name: "amount", Start: 0, End: 5, Text: "12.00"},
+		},
+	}
+
+	amountNew := &reportpb.Node{
+		Name:   "amount",
+		Valid:  true,
+		Bounds: bounds,
+		Text:   wrapperspb.String("13.50"),
+		TextMatchGroups: []*reportpb.TextMatchGroup{
+			{Start: 0, End: 5, Text: "13.50"},
+			{Name: "amount", Start: 0, End: 5, Text: "13.50"},
+		},
+	}
+
+	amountMoved := &reportpb.Node{
+		Name:   "amount",
+		Valid:  true,
+		Bounds: bounds,
+		Text:   wrapperspb.String("Total 12.00"),
+		TextMatchGroups: []*reportpb.TextMatchGroup{
+			{Start: 6, End: 11, Text: "12.00"},
+			{Name: "amount", Start: 6, End: 11, Text: "12.00"},
+		},
+	}
+
+	invalid := &reportpb.Node{
+		Name:  "a",
+		Error: "ambiguous match",
+	}
+
+	passed := &reportpb.Computed{
+		Name:   "check",
+		Status: reportpb.Computed_PASSED,
+		Value:  &reportpb.Computed_BoolValue{BoolValue: true},
+	}
+
+	failed := &reportpb.Computed{
+		Name:   "check",
+		Status: reportpb.Computed_FAILED,
+		Value:  &reportpb.Computed_BoolValue{BoolValue: false},
+	}
+
+	textValue := &reportpb.Computed{
+		Name:   "check",
+		Status: reportpb.Computed_PASSED,
+		Value:  &reportpb.Computed_TextValue{TextValue: "true"},
+	}
+
+	withComputed := func(doc *reportpb.Document, computed ...*reportpb.Computed) *reportpb.Document {
+		doc.Pages[0].Computed = computed
+		return doc
+	}
+
+	for _, tc := range []struct {
+		name         string
+		a            *reportpb.Document
+		b            *reportpb.Document
+		opts         []DiffOption
+		want         []NodeDiff
+		wantComputed []ComputedDiff
+	}{
+		{
+			name: "empty",
+			a:    &reportpb.Document{},
+			b:    &reportpb.Document{},
+		},
+		{
+			name: "identical",
+			a:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", bounds)}),
+			b:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", bounds)}),
+		},
+		{
+			name: "different units",
+			a: diffTestDocument([]*reportpb.Node{
+				diffTestNode("a", "text", diffTestRect(geometry.Cm, geometry.Cm, 3*geometry.Cm, 2*geometry.Cm, geometry.Millimeter)),
+			}),
+			b: diffTestDocument([]*reportpb.Node{
+				diffTestNode("a", "text", diffTestRect(geometry.Cm, geometry.Cm, 3*geometry.Cm, 2*geometry.Cm, geometry.Pt)),
+			}),
+		},
+		{
+			name: "bounds within tolerance",
+			a:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", bounds)}),
+			b:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", diffTestRect(11, 20, 101, 30, geometry.Pt))}),
+		},
+		{
+			name: "bounds beyond tolerance",
+			a:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", bounds)}),
+			b:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", diffTestRect(14, 20, 100, 25, geometry.Pt))}),
+			want: []NodeDiff{{
+				Page:        1,
+				Name:        "a",
+				Old:         diffTestNode("a", "text", bounds),
+				New:         diffTestNode("a", "text", diffTestRect(14, 20, 100, 25, geometry.Pt)),
+				Changed:     []string{"bounds"},
+				BoundsDelta: geometry.Rect{Left: 4, Bottom: -5},
+			}},
+		},
+		{
+			name: "custom tolerance",
+			a:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", bounds)}),
+			b:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", diffTestRect(14, 20, 100, 25, geometry.Pt))}),
+			opts: []DiffOption{WithBoundsTolerance(geometry.Cm)},
+		},
+		{
+			name: "text and groups",
+			a:    diffTestDocument([]*reportpb.Node{amountOld}),
+			b:    diffTestDocument([]*reportpb.Node{amountNew}),
+			want: []NodeDiff{{
+				Page:    1,
+				Name:    "amount",
+				Old:     amountOld,
+				New:     amountNew,
+				Changed: []string{"text", "text_match_groups"},
+			}},
+		},
+		{
+			name: "group offsets",
+			a:    diffTestDocument([]*reportpb.Node{amountOld}),
+			b:    diffTestDocument([]*reportpb.Node{amountMoved}),
+			want: []NodeDiff{{
+				Page:    1,
+				Name:    "amount",
+				Old:     amountOld,
+				New:     amountMoved,
+				Changed: []string{"text"},
+			}},
+		},
+		{
+			name: "now invalid",
+			a:    diffTestDocument([]*reportpb.Node{diffTestNode("a", "text", bounds)}),
+			b:    diffTestDocument([]*reportpb.Node{invalid}),
+			want: []NodeDiff{{
+				Page:    1,
+				Name:    "a",
+				Old:     diffTestNode("a", "text", bounds),
+				New:     invalid,
+				Changed: []string{"valid", "error", "text"},
+			}},
+		},
+		{
+			name: "added and removed",
+			a: diffTestDocument([]*reportpb.Node{
+				diffTestNode("removed", "old", bounds),
+				diffTestNode("same", "text", bounds),
+			}),
+			b: diffTestDocument(
+				[]*reportpb.Node{
+					diffTestNode("added", "new", bounds),
+					diffTestNode("same", "text", bounds),
+				},
+				[]*reportpb.Node{
+					diffTestNode("second", "page", bounds),
+				},
+			),
+			want: []NodeDiff{
+				{Page: 1, Name: "removed", Old: diffTestNode("removed", "old", bounds)},
+				{Page: 1, Name: "added", New: diffTestNode("added", "new", bounds)},
+				{Page: 2, Name: "second", New: diffTestNode("second", "page", bounds)},
+			},
+		},
+		{
+			name: "computed identical",
+			a:    withComputed(diffTestDocument(nil), passed),
+			b:    withComputed(diffTestDocument(nil), passed),
+		},
+		{
+			name: "computed status",
+			a:    withComputed(diffTestDocument(nil), passed),
+			b:    withComputed(diffTestDocument(nil), failed),
+			wantComputed: []ComputedDiff{{
+				Page:    1,
+				Name:    "check",
+				Old:     passed,
+				New:     failed,
+				Changed: []string{"status", "value"},
+			}},
+		},
+		{
+			name: "computed value type",
+			a:    withComputed(diffTestDocument(nil), passed),
+			b:    withComputed(diffTestDocument(nil), textValue),
+			wantComputed: []ComputedDiff{{
+				Page:    1,
+				Name:    "check",
+				Old:     passed,
+				New:     textValue,
+				Changed: []string{"value"},
+			}},
+		},
+		{
+			name: "computed added and removed",
+			a:    withComputed(diffTestDocument(nil), &reportpb.Computed{Name: "old"}),
+			b:    withComputed(diffTestDocument(nil), passed),
+			wantComputed: []ComputedDiff{
+				{Page: 1, Name: "old", Old: &reportpb.Computed{Name: "old"}},
+				{Page: 1, Name: "check", New: passed},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DiffReportProtos(tc.a, tc.b, tc.opts...)
+			if err != nil {
+				t.Fatalf("DiffReportProtos() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(len(tc.want) == 0 && len(tc.wantComputed) == 0, got.Empty()); diff != "" {
+				t.Errorf("Empty() diff (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want, got.Nodes, protocmp.Transform(), cmpopts.EquateEmpty(), geometry.EquateLengthApprox(0.001)); diff != "" {
+				t.Errorf("DiffReportProtos() diff (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.wantComputed, got.Computed, protocmp.Transform(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("DiffReportProtos() computed diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffReports(t *testing.T) {
+	const common = `
+nodes {
+  name: "left"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { pt: 80 }
+    height { pt: 300 }
+  }
+  line_text { regex: "^B(?P<side>[LR])$" }
+}
+computed { name: "side" expr: "top.side" }
+computed { name: "is_left" expr: "top.side == \"L\"" }
+`
+
+	a, err := CompileFromTextprotoString(common + `
+nodes {
+  name: "top"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { pt: 80 }
+    height { pt: 80 }
+  }
+  line_text { regex: "^T(?P<side>[LR])$" }
+}
+nodes {
+  name: "removed"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { pt: 300 }
+    height { pt: 300 }
+  }
+  line_text { regex: "^TR$" }
+}
+`)
+	if err != nil {
+		t.Fatalf("CompileFromTextprotoString() failed: %v", err)
+	}
+
+	b, err := CompileFromTextprotoString(common + `
+nodes {
+  name: "top"
+  search_areas {
+    top_left { abs { left { pt: 100 } top {} } }
+    width { pt: 80 }
+    height { pt: 80 }
+  }
+  line_text { regex: "^T(?P<side>[LR])$" }
+}
+nodes {
+  name: "added"
+  search_areas {
+    top_left { abs { left {} top {} } }
+    width { pt: 300 }
+    height { pt: 300 }
+  }
+  line_text { regex: "^BR$" }
+}
+`)
+	if err != nil {
+		t.Fatalf("CompileFromTextprotoString() failed: %v", err)
+	}
+
+	var reports []*DocumentReport
+
+	for _, s := range []*Sketch{a, b} {
+		report, err := s.AnalyzeDocument(context.Background(), readTestDocument(t, "corners.xml"), pagerange.All)
+		if err != nil {
+			t.Fatalf("AnalyzeDocument() failed: %v", err)
+		}
+
+		reports = append(reports, report)
+	}
+
+	got, err := DiffReports(reports[0], reports[1])
+	if err != nil {
+		t.Fatalf("DiffReports() failed: %v", err)
+	}
+
+	var buf strings.Builder
+
+	if err := got.Format(&buf, geometry.Pt); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+
+	want := `~ page 1 node "top"
+    text: "TL" -> "TR"
+    group 0: "TL" -> "TR"
+    group "side": "L" -> "R"
+    bounds: left +106pt, top 0, right +108pt, bottom 0
+- page 1 node "removed"
+    text: "TR"
+    group 0: "TR"
+    bounds: left 135pt, top 26.4pt, right 148pt, bottom 38pt
++ page 1 node "added"
+    text: "BR"
+    group 0: "BR"
+    bounds: left 134pt, top 212pt, right 148pt, bottom 223pt
+~ page 1 computed "side"
+    value: "L" -> "R"
+~ page 1 computed "is_left"
+    status: PASSED -> FAILED
+    value: true -> false
+`
+
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Format() diff (-want +got):\n%s", diff)
+	}
+}
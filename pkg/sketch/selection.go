@@ -0,0 +1,163 @@
+package sketch
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hansmi/dossier/internal/flexrect"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+// locatorMatch is a candidate found by a locator.
+type locatorMatch struct {
+	bounds geometry.Rect
+	apply  func(*Node)
+
+	// Nil if tracing is disabled.
+	trace *CandidateTrace
+}
+
+// reject records why the candidate was not used.
+func (m *locatorMatch) reject(reason RejectReason) {
+	if m.trace != nil {
+		m.trace.Rejected = reason
+	}
+}
+
+type selection struct {
+	strategy sketchpb.SelectionStrategy
+	anchor   *flexrect.Anchor
+}
+
+func selectionFromProto(pb *sketchpb.Selection) (*selection, error) {
+	var err error
+
+	s := &selection{
+		strategy: pb.GetStrategy(),
+	}
+
+	if _, ok := sketchpb.SelectionStrategy_name[int32(s.strategy)]; !ok {
+		return nil, fmt.Errorf("%w: unsupported selection strategy %v", sketcherror.ErrBadConfig, s.strategy)
+	}
+
+	if pb.Anchor != nil {
+		if s.strategy != sketchpb.SelectionStrategy_CLOSEST {
+			return nil, fmt.Errorf("%w: selection anchor requires %s strategy", sketcherror.ErrBadConfig, sketchpb.SelectionStrategy_CLOSEST)
+		}
+
+		if s.anchor, err = flexrect.AnchorFromProto(pb.GetAnchor(), "anchor"); err != nil {
+			return nil, fmt.Errorf("selection: %w", err)
+		}
+	} else if s.strategy == sketchpb.SelectionStrategy_CLOSEST {
+		return nil, fmt.Errorf("%w: %s strategy requires anchor", sketcherror.ErrIncompleteConfig, s.strategy)
+	}
+
+	return s, nil
+}
+
+func (s *selection) requiredNodeFeatures() []flexrect.NodeFeature {
+	if s == nil || s.anchor == nil {
+		return nil
+	}
+
+	return s.anchor.RequiredNodeFeatures()
+}
+
+// distanceToRect returns the distance from a point to the closest point of
+// a rectangle. Points within the rectangle have a distance of zero.
+func distanceToRect(p geometry.Point, r geometry.Rect) geometry.Length {
+	dx := (r.Left - p.Left).Max(0).Max(p.Left - r.Right)
+	dy := (r.Top - p.Top).Max(0).Max(p.Top - r.Bottom)
+
+	return geometry.Length(math.Hypot(float64(dx), float64(dy)))
+}
+
+// pick chooses one of the candidates. Candidates are assumed to be given in
+// a meaningful order already if ordered is true, e.g. by distance. Otherwise
+// they're sorted in reading order. Nil is returned if there are no
+// candidates.
+func (s *selection) pick(cb flexrect.Callbacks, candidates []*locatorMatch, ordered bool) (*locatorMatch, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	strategy := sketchpb.SelectionStrategy_READING_ORDER
+
+	if s != nil {
+		strategy = s.strategy
+	}
+
+	candidates = slices.Clone(candidates)
+
+	if !ordered {
+		compareRect := geometry.MakeRectRowColumnCompare(geometry.TopToBottom, geometry.LeftToRight)
+
+		slices.SortStableFunc(candidates, func(a, b *locatorMatch) int {
+			return cmp.Or(
+				compareRect(a.bounds, b.bounds),
+				cmp.Compare(a.bounds.Left, b.bounds.Left),
+			)
+		})
+	}
+
+	var key func(geometry.Rect) float64
+
+	switch strategy {
+	case sketchpb.SelectionStrategy_READING_ORDER:
+
+	case sketchpb.SelectionStrategy_TOPMOST:
+		key = func(r geometry.Rect) float64 {
+			return r.Top.Pt()
+		}
+
+	case sketchpb.SelectionStrategy_BOTTOMMOST:
+		key = func(r geometry.Rect) float64 {
+			return -r.Bottom.Pt()
+		}
+
+	case sketchpb.SelectionStrategy_LEFTMOST:
+		key = func(r geometry.Rect) float64 {
+			return r.Left.Pt()
+		}
+
+	case sketchpb.SelectionStrategy_RIGHTMOST:
+		key = func(r geometry.Rect) float64 {
+			return -r.Right.Pt()
+		}
+
+	case sketchpb.SelectionStrategy_LARGEST:
+		key = func(r geometry.Rect) float64 {
+			return -(r.Width().Pt() * r.Height().Pt())
+		}
+
+	case sketchpb.SelectionStrategy_CLOSEST:
+		anchor, err := s.anchor.Resolve(cb)
+		if err != nil {
+			return nil, err
+		}
+
+		key = func(r geometry.Rect) float64 {
+			return distanceToRect(anchor, r).Pt()
+		}
+
+	case sketchpb.SelectionStrategy_UNIQUE:
+		if len(candidates) > 1 {
+			return nil, fmt.Errorf("%w: %d candidates", ErrAmbiguousMatch, len(candidates))
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: unsupported selection strategy %v", sketcherror.ErrBadConfig, strategy)
+	}
+
+	if key != nil {
+		slices.SortStableFunc(candidates, func(a, b *locatorMatch) int {
+			return cmp.Compare(key(a.bounds), key(b.bounds))
+		})
+	}
+
+	return candidates[0], nil
+}
@@ -0,0 +1,149 @@
+package sketch
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
+)
+
+func TestSelectionFromProto(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "empty"},
+		{
+			name:  "topmost",
+			input: `strategy: TOPMOST`,
+		},
+		{
+			name:  "closest",
+			input: `strategy: CLOSEST anchor { abs { left { cm: 1 } top { cm: 2 } } }`,
+		},
+		{
+			name:    "closest without anchor",
+			input:   `strategy: CLOSEST`,
+			wantErr: ErrIncompleteConfig,
+		},
+		{
+			name:    "anchor without closest",
+			input:   `strategy: LARGEST anchor { abs {} }`,
+			wantErr: ErrBadConfig,
+		},
+		{
+			name:    "unknown strategy",
+			input:   `strategy: 1000`,
+			wantErr: ErrBadConfig,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := selectionFromProto(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.Selection{}))
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSelectionPick(t *testing.T) {
+	candidates := []*locatorMatch{
+		{bounds: geometry.RectFromCentimeters(10, 5, 12, 6)},
+		{bounds: geometry.RectFromCentimeters(1, 8, 9, 10)},
+		{bounds: geometry.RectFromCentimeters(4, 1, 5, 1.5)},
+		{bounds: geometry.RectFromCentimeters(2, 5.2, 3, 6)},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		input   string
+		ordered bool
+		want    int
+		wantErr error
+	}{
+		{
+			name: "reading order",
+			want: 2,
+		},
+		{
+			name:    "ordered",
+			ordered: true,
+			want:    0,
+		},
+		{
+			name:  "topmost",
+			input: `strategy: TOPMOST`,
+			want:  2,
+		},
+		{
+			name:  "bottommost",
+			input: `strategy: BOTTOMMOST`,
+			want:  1,
+		},
+		{
+			name:  "leftmost",
+			input: `strategy: LEFTMOST`,
+			want:  1,
+		},
+		{
+			name:  "rightmost",
+			input: `strategy: RIGHTMOST`,
+			want:  0,
+		},
+		{
+			name:  "largest",
+			input: `strategy: LARGEST`,
+			want:  1,
+		},
+		{
+			name:  "closest",
+			input: `strategy: CLOSEST anchor { abs { left { cm: 3.5 } top { cm: 4 } } }`,
+			want:  3,
+		},
+		{
+			name:    "unique",
+			input:   `strategy: UNIQUE`,
+			wantErr: ErrAmbiguousMatch,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := selectionFromProto(testutil.MustUnmarshalTextproto(t, tc.input, &sketchpb.Selection{}))
+			if err != nil {
+				t.Fatalf("selectionFromProto() failed: %v", err)
+			}
+
+			got, err := s.pick(&fakeSearchCallbacks{}, candidates, tc.ordered)
+
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Error diff (-want +got):\n%s", diff)
+			}
+
+			if err == nil && got != candidates[tc.want] {
+				t.Errorf("pick() returned %v, want %v", got.bounds, candidates[tc.want].bounds)
+			}
+		})
+	}
+}
+
+func TestSelectionPickSingle(t *testing.T) {
+	var s *selection
+
+	if got, err := s.pick(nil, nil, false); !(got == nil && err == nil) {
+		t.Errorf("pick() without candidates returned (%v, %v)", got, err)
+	}
+
+	candidates := []*locatorMatch{
+		{bounds: geometry.RectFromCentimeters(1, 1, 2, 2)},
+	}
+
+	s = &selection{strategy: sketchpb.SelectionStrategy_UNIQUE}
+
+	if got, err := s.pick(nil, candidates, false); err != nil || got != candidates[0] {
+		t.Errorf("pick() returned (%v, %v)", got, err)
+	}
+}
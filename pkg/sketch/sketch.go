@@ -3,6 +3,9 @@ package sketch
 import (
 	"context"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"slices"
 
 	"github.com/hansmi/dossier"
@@ -11,63 +14,204 @@ import (
 	"github.com/hansmi/dossier/pkg/pagerange"
 	"github.com/hansmi/dossier/proto/sketchpb"
 	"go.uber.org/multierr"
-	"google.golang.org/protobuf/encoding/prototext"
 )
 
 type Sketch struct {
 	tags        []string
 	nodes       []*sketchNode
+	computed    []*computedNode
 	searchOrder []int
 }
 
+// Compile builds a sketch from its configuration. Imports are not supported;
+// use [CompileFile] instead. Configuration problems are reported as
+// [ConfigErrors].
 func Compile(pb *sketchpb.Sketch) (*Sketch, error) {
-	var err error
+	return CompileWithParams(pb, nil)
+}
+
+// CompileWithParams builds a sketch from its configuration while overriding
+// parameter values. String parameters require string values. Length
+// parameters accept geometry.Length values and strings such as "12mm".
+func CompileWithParams(pb *sketchpb.Sketch, params map[string]any) (*Sketch, error) {
+	return compileSource(pb, nil, params)
+}
+
+// CompileFile loads a sketch from a file system. The format is detected like
+// in [CompileFromFile]. Imports are resolved relative to the file within the
+// same file system. Configuration problems are reported as [ConfigErrors] with the
+// file, line and column of the offending values.
+func CompileFile(fsys fs.FS, name string) (*Sketch, error) {
+	return CompileFileWithParams(fsys, name, nil)
+}
+
+// CompileFileWithParams loads a sketch like [CompileFile] while overriding
+// parameter values like [CompileWithParams]. Parameters of imported sketches
+// use their namespaced names, e.g. "footer.customer".
+func CompileFileWithParams(fsys fs.FS, name string, params map[string]any) (*Sketch, error) {
+	l := &sketchLoader{fsys: fsys, params: params}
+
+	resolved, err := l.loadFile(name, "")
+	if err != nil {
+		return nil, asConfigErrors(err)
+	}
+
+	return l.compile(resolved)
+}
+
+// compileSource builds a sketch from a configuration read from the given
+// file, if any.
+func compileSource(pb *sketchpb.Sketch, src *sourceFile, params map[string]any) (*Sketch, error) {
+	l := &sketchLoader{params: params}
+
+	resolved, err := l.resolve(pb, src, ".", "")
+	if err != nil {
+		return nil, asConfigErrors(err)
+	}
+
+	return l.compile(resolved)
+}
+
+// compile verifies the use of all parameter overrides and builds the sketch.
+func (l *sketchLoader) compile(resolved *resolvedSketch) (*Sketch, error) {
+	if err := l.checkParams(); err != nil {
+		return nil, err
+	}
+
+	return compile(resolved)
+}
+
+// compile builds a sketch from a resolved configuration. All nodes are
+// checked before returning their problems as [ConfigErrors].
+func compile(resolved *resolvedSketch) (*Sketch, error) {
+	var errs ConfigErrors
+
+	fail := func(err error) {
+		errs = append(errs, &ConfigError{
+			Pos: resolved.position(errorPath(err)),
+			Err: err,
+		})
+	}
+
+	pb := resolved.pb
 
 	s := &Sketch{
 		nodes: make([]*sketchNode, 0, len(pb.GetNodes())),
 	}
 
-	if s.tags, err = validateTags(pb.GetTags()); err != nil {
-		return nil, multierr.Combine(sketcherror.ErrBadConfig, err)
+	if tags, err := validateTags(pb.GetTags()); err != nil {
+		fail(atPath(multierr.Combine(sketcherror.ErrBadConfig, err), "tags", 0))
+	} else {
+		s.tags = tags
 	}
 
-	for _, pn := range pb.GetNodes() {
-		n, err := sketchNodeFromProto(pn)
+	names := map[string]bool{}
+
+	for idx, pn := range pb.GetNodes() {
+		names[pn.GetName()] = true
+
+		n, err := sketchNodeFromProto(pn, pb.GetNormalization())
 		if err != nil {
-			return nil, fmt.Errorf("node %s: %w", pn.GetName(), err)
+			for _, err := range multierr.Errors(err) {
+				fail(atPath(fmt.Errorf("node %s: %w", pn.GetName(), err), "nodes", idx))
+			}
+
+			continue
 		}
 
 		s.nodes = append(s.nodes, n)
 	}
 
-	if order, err := determineNodeOrder(s.nodes); err != nil {
-		return nil, err
-	} else {
-		s.searchOrder = order
+	// References between nodes can only be verified if all nodes are valid.
+	if len(errs) == 0 {
+		if order, err := determineNodeOrder(s.nodes); err != nil {
+			fail(err)
+		} else {
+			s.searchOrder = order
+		}
+	}
+
+	computed := map[string]bool{}
+
+	for idx, pc := range pb.GetComputed() {
+		c, err := computedNodeFromProto(pc, names, computed)
+		if err != nil {
+			fail(atPath(fmt.Errorf("computed node %s: %w", pc.GetName(), err), "computed", idx))
+			continue
+		}
+
+		if names[c.name] || computed[c.name] {
+			fail(atPath(fmt.Errorf("%w: multiple nodes with name %q", sketcherror.ErrBadConfig, c.name), "computed", idx))
+			continue
+		}
+
+		computed[c.name] = true
+
+		s.computed = append(s.computed, c)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
 	return s, nil
 }
 
-func CompileFromTextproto(b []byte) (*Sketch, error) {
-	var msg sketchpb.Sketch
-
-	if err := prototext.Unmarshal(b, &msg); err != nil {
+// compileFromBytes builds a sketch from its configuration in the given
+// format. Imports are not supported.
+func compileFromBytes(format sourceFormat, b []byte) (*Sketch, error) {
+	pb, src, err := decodeSource(format, "", b)
+	if err != nil {
 		return nil, err
 	}
 
-	return Compile(&msg)
+	return compileSource(pb, src, nil)
+}
+
+// CompileFromTextproto builds a sketch from its configuration in the Protocol
+// Buffer text format. Configuration problems are reported as [ConfigErrors]
+// with the line and column of the offending values.
+func CompileFromTextproto(b []byte) (*Sketch, error) {
+	return compileFromBytes(formatTextproto, b)
 }
 
 func CompileFromTextprotoString(s string) (*Sketch, error) {
 	return CompileFromTextproto([]byte(s))
 }
 
+// CompileFromJSON builds a sketch from its configuration in the JSON mapping
+// of Protocol Buffers, i.e. the format used by
+// [google.golang.org/protobuf/encoding/protojson]. Fields may use their
+// original or lowerCamelCase names.
+func CompileFromJSON(b []byte) (*Sketch, error) {
+	return compileFromBytes(formatJSON, b)
+}
+
+// CompileFromYAML builds a sketch from its configuration written in YAML
+// using the same mapping as [CompileFromJSON].
+func CompileFromYAML(b []byte) (*Sketch, error) {
+	return compileFromBytes(formatYAML, b)
+}
+
+// CompileFromFile loads a sketch from the local file system. The format is
+// detected using the file extension: ".json" for JSON, ".yaml" or ".yml" for
+// YAML and the text format otherwise. The same applies to imports, which are
+// resolved within the directory of the file.
+func CompileFromFile(path string) (*Sketch, error) {
+	return CompileFile(os.DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
 func (s *Sketch) Tags() []string {
 	return s.tags
 }
 
-func (s *Sketch) AnalyzePage(p *dossier.Page) (*PageReport, error) {
+func (s *Sketch) AnalyzePage(p *dossier.Page, opts ...AnalyzeOption) (*PageReport, error) {
+	var o analyzeOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	r := newPageReport(p)
 
 	callbacks := struct {
@@ -81,7 +225,7 @@ func (s *Sketch) AnalyzePage(p *dossier.Page) (*PageReport, error) {
 	for _, i := range s.searchOrder {
 		node := s.nodes[i]
 
-		match, err := node.search(&callbacks)
+		match, err := node.search(&callbacks, o.trace)
 		if err != nil {
 			return nil, fmt.Errorf("search for node %q on page %d: %w", node.name, r.Number(), err)
 		}
@@ -89,14 +233,20 @@ func (s *Sketch) AnalyzePage(p *dossier.Page) (*PageReport, error) {
 		r.appendNode(match)
 	}
 
+	for _, c := range s.computed {
+		r.appendComputed(c.evaluate(r))
+	}
+
 	return r, nil
 }
 
-func (s *Sketch) AnalyzePages(pages []*dossier.Page) ([]*PageReport, error) {
-	return mapOrFirstError(pages, s.AnalyzePage)
+func (s *Sketch) AnalyzePages(pages []*dossier.Page, opts ...AnalyzeOption) ([]*PageReport, error) {
+	return mapOrFirstError(pages, func(p *dossier.Page) (*PageReport, error) {
+		return s.AnalyzePage(p, opts...)
+	})
 }
 
-func (s *Sketch) AnalyzeDocument(ctx context.Context, doc *dossier.Document, r pagerange.Range) (*DocumentReport, error) {
+func (s *Sketch) AnalyzeDocument(ctx context.Context, doc *dossier.Document, r pagerange.Range, opts ...AnalyzeOption) (*DocumentReport, error) {
 	pages, err := doc.ParsePages(ctx, r)
 	if err != nil {
 		return nil, err
@@ -106,7 +256,7 @@ func (s *Sketch) AnalyzeDocument(ctx context.Context, doc *dossier.Document, r p
 		tags: slices.Clone(s.tags),
 	}
 
-	if result.pages, err = s.AnalyzePages(pages); err != nil {
+	if result.pages, err = s.AnalyzePages(pages, opts...); err != nil {
 		return nil, err
 	}
 
@@ -58,6 +58,7 @@ func TestSketchGolden(t *testing.T) {
 		name     string
 		sketch   *sketchpb.Sketch
 		document string
+		opts     []AnalyzeOption
 	}{
 		{
 			name:     "empty",
@@ -125,6 +126,572 @@ nodes: {
 
   tags: "top right"
 }
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "neighbor",
+			document: "corners.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "tl"
+  search_areas {
+    top_left {
+      abs: { left: { mm: 1 } top: { mm: 1 } }
+    }
+    width: { cm: 3 }
+    height: { cm: 2 }
+  }
+  block_text: {
+    regex: "(?i)^\\s*tl\\b"
+  }
+}
+
+nodes: {
+  name: "tr"
+  neighbor: {
+    node: "tl"
+    direction: RIGHT
+  }
+  line_text: {
+    regex: "\\w"
+  }
+}
+
+nodes: {
+  name: "bl"
+  neighbor: {
+    node: "tl"
+    direction: DOWN
+    min_overlap: 0.5
+  }
+  line_text: {
+    regex: "\\w"
+  }
+}
+
+nodes: {
+  name: "missing"
+  neighbor: {
+    node: "tl"
+    direction: DOWN
+    max_distance: { cm: 1 }
+  }
+  line_text: {
+    regex: "\\w"
+  }
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "adjacent",
+			document: "corners.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "tl"
+  search_areas {
+    top_left {
+      abs: { left: { mm: 1 } top: { mm: 1 } }
+    }
+    width: { cm: 3 }
+    height: { cm: 2 }
+  }
+  block_text: {
+    regex: "(?i)^\\s*tl\\b"
+  }
+}
+
+nodes: {
+  name: "tr"
+  adjacent_areas: {
+    node: "tl"
+    direction: RIGHT
+    margin: { pt: 1 }
+  }
+  line_text: {
+    regex: "\\w"
+  }
+}
+
+nodes: {
+  name: "bl"
+  adjacent_areas: {
+    node: "tl"
+    direction: DOWN
+    lines: 5
+  }
+  adjacent_areas: {
+    node: "tl"
+    direction: DOWN
+    margin: { pt: 2 }
+  }
+  line_text: {
+    regex: "\\w"
+  }
+}
+
+nodes: {
+  name: "br"
+  adjacent_areas: {
+    node: "bl"
+    direction: RIGHT
+    distance: { cm: 4 }
+    span_page: true
+  }
+  line_text: {
+    regex: "\\w"
+  }
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "selection",
+			document: "lorem-mixed.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "default"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 20 } } }
+  }
+  line_text: { regex: "Lorem" }
+}
+
+nodes: {
+  name: "bottommost"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 20 } } }
+  }
+  line_text: { regex: "Lorem" }
+  selection: { strategy: BOTTOMMOST }
+}
+
+nodes: {
+  name: "closest"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 20 } } }
+  }
+  line_text: { regex: "Lorem" }
+  selection: {
+    strategy: CLOSEST
+    anchor: {
+      rel: { node: "bottommost" feature: BOTTOM_RIGHT }
+    }
+  }
+}
+
+nodes: {
+  name: "unique"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 20 } } }
+  }
+  line_text: { regex: "Lorem" }
+  selection: { strategy: UNIQUE }
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "containment",
+			document: "corners.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "contained"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { pt: 35 } top: { pt: 40 } } }
+  }
+  line_text: { regex: "\\w" }
+}
+
+nodes: {
+  name: "intersecting"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { pt: 35 } top: { pt: 40 } } }
+  }
+  line_text: { regex: "\\w" }
+  containment: { mode: INTERSECTING min_overlap: 0.5 }
+}
+
+nodes: {
+  name: "center"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { pt: 35 } top: { pt: 40 } } }
+  }
+  line_text: { regex: "\\w" }
+  containment: { mode: CENTER }
+}
+
+nodes: {
+  name: "clipped"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { pt: 35 } top: { pt: 40 } } }
+  }
+  line_text: { regex: "\\w+" }
+  containment: { mode: INTERSECTING clip_text: true }
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "exclusions",
+			document: "acme-invoice-11321-19.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "amount"
+  search_areas {
+    top_left { abs: { left: { pt: 480 } top: { pt: 260 } } }
+    bottom_right { abs: { left: { pt: 560 } top: { pt: 440 } } }
+  }
+  exclusions {
+    top_left { abs: { left: { pt: 60 } top: { pt: 285 } } }
+    bottom_right { abs: { left: { pt: 560 } top: { pt: 372 } } }
+  }
+  must_not_match {
+    adjacent_area { direction: LEFT distance: { cm: 4 } }
+    line_text: { regex: "(?i)^\\s*net\\b" }
+  }
+  line_text: { regex: "^\\d+\\.\\d+$" }
+}
+
+nodes: {
+  name: "bank"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 30 } } }
+  }
+  exclusions {
+    top_left { abs: { left: {} top: { pt: 760 } } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 30 } } }
+  }
+  line_text: { regex: "^ABC Bank$" }
+  selection: { strategy: BOTTOMMOST }
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "fuzzy",
+			document: "acme-invoice-11321-19.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "total"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 30 } } }
+  }
+  line_text: {
+    fuzzy: {
+      text: "GROS T0TAL"
+      max_distance: 1
+      ignore_case: true
+      default_confusables: true
+    }
+    regex: "^\\s*\u20AC?(?P<amount>[,.\\d]+)"
+  }
+}
+
+nodes: {
+  name: "customer"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 30 } } }
+  }
+  line_text: {
+    fuzzy: {
+      text: "Custorner"
+      default_confusables: true
+    }
+    bounds_from_match: true
+  }
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "normalization",
+			document: "unicode1.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+normalization: {
+  nfkc: true
+  unify_dashes: true
+}
+
+nodes: {
+  name: "em_dash"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 30 } } }
+  }
+  line_text: {
+    regex: "^U\\+2014 = (?P<dash>-)$"
+    bounds_from_match: true
+  }
+}
+
+nodes: {
+  name: "without_normalization"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 30 } top: { cm: 30 } } }
+  }
+  line_text: {
+    regex: "^U\\+2014 = (?P<dash>-)$"
+  }
+  normalization: {}
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "multiline",
+			document: "acme-invoice-11321-19.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "address"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 10 } top: { cm: 10 } } }
+  }
+  multi_line: {
+    anchor: { regex: "^John Doe$" }
+    text: { regex: "^(?P<name>.+)\\n(?P<street>.+)\\n(?P<city>.+)$" }
+    include_anchor: true
+    stop_regex: "^Country$"
+  }
+}
+
+nodes: {
+  name: "bank"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 15 } top: { cm: 20 } } }
+  }
+  multi_line: {
+    anchor: { regex: "^The amount is payable" }
+    text: {
+      regex: "IBAN: (?P<iban>\\w+)"
+      bounds_from_match: true
+    }
+    max_line_gap: { pt: 20 }
+  }
+}
+
+nodes: {
+  name: "limited"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 15 } top: { cm: 20 } } }
+  }
+  multi_line: {
+    anchor: { regex: "^The amount is payable" }
+    max_line_gap: { pt: 20 }
+    max_lines: 1
+  }
+}
+
+nodes: {
+  name: "without_gap"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    bottom_right { abs: { left: { cm: 10 } top: { cm: 10 } } }
+  }
+  multi_line: {
+    anchor: { regex: "^John Doe$" }
+    include_anchor: true
+    max_line_gap: { mm: 0 }
+  }
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "computed",
+			document: "acme-invoice-11321-19.xml",
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "net_label"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 21 }
+    height: { cm: 29.7 }
+  }
+  line_text: { regex: "^Net total$" }
+}
+
+nodes: {
+  name: "net"
+  neighbor: { node: "net_label" direction: RIGHT }
+  line_text: { regex: "^(?P<amount>[\\d.]+)$" }
+}
+
+nodes: {
+  name: "vat_label"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 21 }
+    height: { cm: 29.7 }
+  }
+  line_text: { regex: "^VAT$" }
+}
+
+nodes: {
+  name: "vat"
+  neighbor: { node: "vat_label" direction: RIGHT }
+  line_text: { regex: "^(?P<amount>[\\d.]+)$" }
+}
+
+nodes: {
+  name: "gross"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 21 }
+    height: { cm: 29.7 }
+  }
+  line_text: { regex: "^Gross total \\x{20ac}(?P<amount>[\\d.]+)$" }
+}
+
+nodes: {
+  name: "date_label"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 21 }
+    height: { cm: 29.7 }
+  }
+  line_text: { regex: "^Date:$" }
+}
+
+nodes: {
+  name: "invoice_date"
+  neighbor: { node: "date_label" direction: RIGHT }
+  line_text: {}
+}
+
+nodes: {
+  name: "missing"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 21 }
+    height: { cm: 29.7 }
+  }
+  line_text: { regex: "^Discount (?P<amount>.*)" }
+}
+
+computed: {
+  name: "total_check"
+  expr: "net.amount + vat.amount == gross.amount"
+  tags: "check"
+}
+
+computed: {
+  name: "vat_rate"
+  expr: "round(vat.amount / net.amount * 100, 1)"
+}
+
+computed: {
+  name: "vat_rate_check"
+  expr: "vat_rate == 19"
+}
+
+computed: {
+  name: "due_date"
+  expr: "date(invoice_date, \"02-01-2006\") + 7"
+}
+
+computed: {
+  name: "wrong"
+  expr: "net.amount == gross.amount"
+}
+
+computed: {
+  name: "broken"
+  expr: "number(invoice_date) > 0"
+}
+
+computed: {
+  name: "skipped"
+  expr: "missing.amount > 0"
+}
+
+computed: {
+  name: "guarded"
+  expr: "!has(missing.amount) || missing.amount > 0"
+}
+`, &sketchpb.Sketch{}),
+		},
+		{
+			name:     "trace",
+			document: "corners.xml",
+			opts:     []AnalyzeOption{WithTrace()},
+			sketch: testutil.MustUnmarshalTextproto(t, `
+nodes: {
+  name: "top_right"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 10 }
+    height: { cm: 10 }
+  }
+  line_text: { regex: "^TR$" }
+}
+
+nodes: {
+  name: "partial"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 10 }
+    height: { cm: 1.2 }
+  }
+  line_text: {}
+}
+
+nodes: {
+  name: "excluded"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 10 }
+    height: { cm: 2 }
+  }
+  exclusions {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 3 }
+    height: { cm: 2 }
+  }
+  line_text: {}
+}
+
+nodes: {
+  name: "missing"
+  search_areas {
+    top_left { abs: { left: {} top: {} } }
+    width: { cm: 3 }
+    height: { cm: 3 }
+  }
+  line_text: { regex: "^XYZ$" }
+}
+
+nodes: {
+  name: "dependent"
+  search_areas {
+    top_left { rel: { node: "missing" feature: BOTTOM_LEFT } }
+    width: { cm: 3 }
+    height: { cm: 3 }
+  }
+  line_text: {}
+  alternatives {
+    name: "fallback"
+    search_areas {
+      top_left { abs: { left: {} top: { cm: 5 } } }
+      width: { cm: 3 }
+      height: { cm: 5 }
+    }
+    line_text: { regex: "^BL$" }
+  }
+}
+
+nodes: {
+  name: "below"
+  neighbor: { node: "top_right" direction: DOWN }
+  line_text: {}
+}
 `, &sketchpb.Sketch{}),
 		},
 		{
@@ -143,7 +710,7 @@ nodes: {
 				t.Fatalf("Compile() failed: %v", err)
 			}
 
-			got, err := sketch.AnalyzeDocument(context.Background(), doc, pagerange.All)
+			got, err := sketch.AnalyzeDocument(context.Background(), doc, pagerange.All, tc.opts...)
 			if err != nil {
 				t.Fatalf("FullReport() failed: %v", err)
 			}
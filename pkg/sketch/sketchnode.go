@@ -3,31 +3,83 @@ package sketch
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 
 	"github.com/hansmi/dossier"
 	"github.com/hansmi/dossier/internal/flexrect"
 	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/pkg/content"
 	"github.com/hansmi/dossier/pkg/geometry"
 	"github.com/hansmi/dossier/proto/sketchpb"
 	"go.uber.org/multierr"
 )
 
 type documentPage interface {
+	Size() geometry.Size
 	VisitElementsIntersecting(geometry.Rect, dossier.PageElementVisitorFunc) error
+	ElementsInDirection(geometry.Rect, geometry.Direction, dossier.DirectionalSearchOptions) ([]content.Element, error)
 }
 
 type sketchNodeLocator interface {
-	locate(documentPage, geometry.Rect) (func(*Node), error)
+	// evaluate returns a candidate if the element matches. Nil is returned
+	// otherwise. Elements of the searched kind are recorded in the trace if
+	// it's not nil.
+	evaluate(documentPage, content.Element, *AreaTrace) (*locatorMatch, error)
+
+	// locate returns all candidates located within the given area according
+	// to the containment policy. Considered elements are recorded in the
+	// trace if it's not nil.
+	locate(documentPage, geometry.Rect, containment, *AreaTrace) ([]*locatorMatch, error)
 }
 
 type sketchNode struct {
-	name        string
-	searchAreas []*flexrect.FlexRect
-	locator     sketchNodeLocator
-	tags        []string
+	name         string
+	searchAreas  []*flexrect.FlexRect
+	adjacent     []*flexrect.AdjacentArea
+	neighbor     *neighborSearch
+	selection    *selection
+	containment  containment
+	exclusions   []*flexrect.FlexRect
+	negatives    []*negativeMatch
+	alternatives []*nodeAlternative
+	tags         []string
+
+	// Nil for nodes consisting only of alternatives.
+	locator sketchNodeLocator
+}
+
+// newNodeLocatorFromProto builds the locator for a matcher of a node or node
+// alternative.
+func newNodeLocatorFromProto(matcher any, norm *normalizer) (sketchNodeLocator, error) {
+	switch m := matcher.(type) {
+	case *sketchpb.Node_BlockText:
+		return newTextLocatorFromProto(m.BlockText, false, norm)
+
+	case *sketchpb.Node_LineText:
+		return newTextLocatorFromProto(m.LineText, true, norm)
+
+	case *sketchpb.Node_MultiLine:
+		return newMultiLineLocatorFromProto(m.MultiLine, norm)
+
+	case *sketchpb.Node_Alternative_BlockText:
+		return newTextLocatorFromProto(m.BlockText, false, norm)
+
+	case *sketchpb.Node_Alternative_LineText:
+		return newTextLocatorFromProto(m.LineText, true, norm)
+
+	case *sketchpb.Node_Alternative_MultiLine:
+		return newMultiLineLocatorFromProto(m.MultiLine, norm)
+	}
+
+	return nil, fmt.Errorf("%w: unsupported match type %T", sketcherror.ErrBadConfig, matcher)
 }
 
-func sketchNodeFromProto(pbnode *sketchpb.Node) (*sketchNode, error) {
+// sketchNodeFromProto builds a node from its configuration. The default
+// normalization applies if the node doesn't configure its own. All problems
+// are combined in the returned error, each annotated with its field.
+func sketchNodeFromProto(pbnode *sketchpb.Node, defaultNormalization *sketchpb.Normalization) (*sketchNode, error) {
+	var errs error
 	var err error
 
 	node := &sketchNode{
@@ -35,35 +87,128 @@ func sketchNodeFromProto(pbnode *sketchpb.Node) (*sketchNode, error) {
 	}
 
 	if node.tags, err = validateTags(pbnode.GetTags()); err != nil {
-		return nil, multierr.Combine(sketcherror.ErrBadConfig, err)
+		errs = multierr.Append(errs, atPath(multierr.Combine(sketcherror.ErrBadConfig, err), "tags", 0))
 	}
 
-	switch m := pbnode.GetMatcher().(type) {
-	case *sketchpb.Node_BlockText:
-		node.locator, err = newTextLocatorFromProto(m.BlockText, false)
-
-	case *sketchpb.Node_LineText:
-		node.locator, err = newTextLocatorFromProto(m.LineText, true)
+	norm := normalizerFromProto(defaultNormalization)
 
-	default:
-		err = fmt.Errorf("%w: node %q has unsupported match type %T", sketcherror.ErrBadConfig, node.name, m)
+	if pbnode.Normalization != nil {
+		norm = normalizerFromProto(pbnode.GetNormalization())
 	}
 
-	if err != nil {
-		return nil, err
+	hasLocator := pbnode.Matcher != nil || len(pbnode.GetAlternatives()) == 0 ||
+		len(pbnode.GetSearchAreas()) > 0 || len(pbnode.GetAdjacentAreas()) > 0 ||
+		pbnode.Neighbor != nil
+
+	if hasLocator {
+		if node.locator, err = newNodeLocatorFromProto(pbnode.GetMatcher(), norm); err != nil {
+			err = fmt.Errorf("node %q: %w", node.name, err)
+
+			m := pbnode.ProtoReflect()
+
+			if fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("matcher")); fd != nil {
+				err = atPath(err, string(fd.Name()), 0)
+			}
+
+			errs = multierr.Append(errs, err)
+		}
 	}
 
-	for _, pbArea := range pbnode.GetSearchAreas() {
+	for idx, pbArea := range pbnode.GetSearchAreas() {
 		area, err := flexrect.FromProto(pbArea)
 		if err != nil {
-			return nil, err
+			errs = multierr.Append(errs, atPath(err, "search_areas", idx))
+			continue
 		}
 
 		node.searchAreas = append(node.searchAreas, area)
 	}
 
-	if len(node.searchAreas) < 1 {
-		return nil, fmt.Errorf("%w: node %q requires at least one search area", sketcherror.ErrIncompleteConfig, node.name)
+	for idx, pbArea := range pbnode.GetAdjacentAreas() {
+		area, err := flexrect.AdjacentAreaFromProto(pbArea)
+		if err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("node %q: %w", node.name, err), "adjacent_areas", idx))
+			continue
+		}
+
+		node.adjacent = append(node.adjacent, area)
+	}
+
+	if pbnode.Selection != nil {
+		if node.selection, err = selectionFromProto(pbnode.GetSelection()); err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("node %q: %w", node.name, err), "selection", 0))
+		}
+	}
+
+	if pbnode.Containment != nil {
+		if node.containment, err = containmentFromProto(pbnode.GetContainment()); err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("node %q: %w", node.name, err), "containment", 0))
+		}
+	}
+
+	for idx, pbArea := range pbnode.GetExclusions() {
+		area, err := flexrect.FromProto(pbArea)
+		if err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("node %q exclusion: %w", node.name, err), "exclusions", idx))
+			continue
+		}
+
+		node.exclusions = append(node.exclusions, area)
+	}
+
+	for idx, pbNegative := range pbnode.GetMustNotMatch() {
+		m, err := negativeMatchFromProto(pbNegative, norm)
+		if err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("node %q: %w", node.name, err), "must_not_match", idx))
+			continue
+		}
+
+		node.negatives = append(node.negatives, m)
+	}
+
+	for idx, pbAlternative := range pbnode.GetAlternatives() {
+		alt, err := nodeAlternativeFromProto(pbAlternative, norm)
+		if err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("node %q alternative %d: %w", node.name, idx+1, err), "alternatives", idx))
+			continue
+		}
+
+		if alt.name == "" {
+			alt.name = strconv.Itoa(idx + 1)
+		}
+
+		if slices.ContainsFunc(node.alternatives, func(other *nodeAlternative) bool {
+			return other.name == alt.name
+		}) {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("%w: node %q: duplicate alternative %q", sketcherror.ErrBadConfig, node.name, alt.name), "alternatives", idx))
+			continue
+		}
+
+		node.alternatives = append(node.alternatives, alt)
+	}
+
+	// The configured areas are checked as invalid ones are not part of the
+	// node.
+	hasAreas := len(pbnode.GetSearchAreas()) > 0 || len(pbnode.GetAdjacentAreas()) > 0
+
+	if pbnode.Neighbor != nil {
+		if hasAreas {
+			errs = multierr.Append(errs, fmt.Errorf("%w: node %q: search areas and neighbor search are mutually exclusive", sketcherror.ErrBadConfig, node.name))
+		}
+
+		if pbnode.Containment != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%w: node %q: containment policy is not supported with neighbor search", sketcherror.ErrBadConfig, node.name))
+		}
+
+		if node.neighbor, err = neighborSearchFromProto(pbnode.GetNeighbor()); err != nil {
+			errs = multierr.Append(errs, atPath(fmt.Errorf("node %q: %w", node.name, err), "neighbor", 0))
+		}
+	} else if hasLocator && !hasAreas {
+		errs = multierr.Append(errs, fmt.Errorf("%w: node %q requires at least one search area", sketcherror.ErrIncompleteConfig, node.name))
+	}
+
+	if errs != nil {
+		return nil, errs
 	}
 
 	return node, nil
@@ -84,36 +229,248 @@ func (s *sketchNode) featurePosition(bounds geometry.Rect, feature sketchpb.Node
 	return geometry.Point{}, fmt.Errorf("%w: node %q lacks feature %s", ErrNodeFeatureUnavailable, s.name, feature.String())
 }
 
+// requiredNodeFeatures returns all features of other nodes which must be
+// resolvable before the node can be searched.
+func (s *sketchNode) requiredNodeFeatures() []flexrect.NodeFeature {
+	var result []flexrect.NodeFeature
+
+	for _, area := range s.searchAreas {
+		result = append(result, area.RequiredNodeFeatures()...)
+	}
+
+	for _, area := range s.adjacent {
+		result = append(result, area.RequiredNodeFeatures()...)
+	}
+
+	if s.neighbor != nil {
+		result = append(result, s.neighbor.requiredNodeFeatures()...)
+	}
+
+	result = append(result, s.selection.requiredNodeFeatures()...)
+
+	for _, area := range s.exclusions {
+		result = append(result, area.RequiredNodeFeatures()...)
+	}
+
+	for _, m := range s.negatives {
+		result = append(result, m.requiredNodeFeatures()...)
+	}
+
+	for _, alt := range s.alternatives {
+		result = append(result, alt.requiredNodeFeatures()...)
+	}
+
+	return result
+}
+
 type sketchNodeSearchCallbacks interface {
 	documentPage
 	flexrect.Callbacks
 }
 
-func (s *sketchNode) search(cb sketchNodeSearchCallbacks) (*Node, error) {
-	n := &Node{s: s}
+// searchNeighbor searches next to the reference node. The return value
+// reports whether any candidates were found.
+func (s *sketchNode) searchNeighbor(cb sketchNodeSearchCallbacks, n *Node) (bool, error) {
+	ref, err := s.neighbor.referenceBounds(cb)
+	if err != nil {
+		if errors.Is(err, ErrNodePositionUnknown) {
+			n.trace.unresolved(err)
+			return false, nil
+		}
 
-	// Find candidate areas
-	for _, area := range s.searchAreas {
+		return false, err
+	}
+
+	area, err := s.neighbor.searchArea(ref, cb.Size())
+	if err != nil {
+		return false, err
+	}
+
+	trace := n.trace.addArea("", area, true)
+
+	n.searchAreas = append(n.searchAreas, area)
+
+	candidates, err := cb.ElementsInDirection(ref, s.neighbor.direction, s.neighbor.opts)
+	if err != nil {
+		return false, err
+	}
+
+	var matches []*locatorMatch
+
+	for _, elem := range candidates {
+		if m, err := s.locator.evaluate(cb, elem, trace); err != nil {
+			return false, err
+		} else if m != nil {
+			matches = append(matches, m)
+		}
+	}
+
+	if matches, err = s.filter(cb, n.trace, matches); err != nil {
+		return false, err
+	}
+
+	return len(matches) > 0, s.apply(cb, n, matches, true)
+}
+
+// filter removes candidates located in exclusion areas or rejected by
+// negative matches. Exclusion areas referencing nodes of unknown position are
+// ignored.
+func (s *sketchNode) filter(cb sketchNodeSearchCallbacks, trace *NodeTrace, candidates []*locatorMatch) ([]*locatorMatch, error) {
+	if len(s.exclusions) == 0 && len(s.negatives) == 0 {
+		return candidates, nil
+	}
+
+	var exclusions []geometry.Rect
+
+	for _, area := range s.exclusions {
 		bounds, err := area.Resolve(cb)
 		if err != nil {
 			if errors.Is(err, ErrNodePositionUnknown) {
+				trace.unresolved(err)
 				continue
 			}
 
 			return nil, err
 		}
 
-		n.searchAreas = append(n.searchAreas, bounds)
+		exclusions = append(exclusions, bounds)
+	}
+
+	excluded := func(bounds geometry.Rect) bool {
+		center := bounds.Center()
+
+		for _, area := range exclusions {
+			if pointInside(center, area) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var result []*locatorMatch
+
+nextCandidate:
+	for _, c := range candidates {
+		if excluded(c.bounds) {
+			c.reject(RejectedExcluded)
+			continue
+		}
+
+		for _, m := range s.negatives {
+			if found, err := m.matches(cb, trace, c.bounds, s.containment, excluded); err != nil {
+				return nil, err
+			} else if found {
+				c.reject(RejectedNegativeMatch)
+				continue nextCandidate
+			}
+		}
+
+		result = append(result, c)
+	}
+
+	return result, nil
+}
+
+// apply selects one of the candidates and applies it to the node. Ambiguous
+// matches and unresolvable anchors leave the node invalid.
+func (s *sketchNode) apply(cb sketchNodeSearchCallbacks, n *Node, candidates []*locatorMatch, ordered bool) error {
+	m, err := s.selection.pick(cb, candidates, ordered)
+	if err != nil {
+		if errors.Is(err, ErrNodePositionUnknown) {
+			n.trace.unresolved(err)
+		}
+
+		if errors.Is(err, ErrNodePositionUnknown) || errors.Is(err, ErrAmbiguousMatch) {
+			n.err = err
+			return nil
+		}
+
+		return err
 	}
 
-	// Search within valid areas
-	for _, area := range n.searchAreas {
-		if apply, err := s.locator.locate(cb, area); err != nil {
+	if m != nil {
+		m.apply(n)
+
+		if m.trace != nil {
+			m.trace.Selected = true
+		}
+	}
+
+	return nil
+}
+
+// searchAreasWith searches the areas in order and applies the candidates of the
+// first area with any. The return value reports whether candidates were
+// found. The alternative name is only used for the trace.
+func (s *sketchNode) searchAreasWith(cb sketchNodeSearchCallbacks, n *Node, areas []geometry.Rect, locator sketchNodeLocator, alternative string) (bool, error) {
+	for _, area := range areas {
+		candidates, err := locator.locate(cb, area, s.containment, n.trace.addArea(alternative, area, false))
+		if err != nil {
+			return false, err
+		}
+
+		if candidates, err = s.filter(cb, n.trace, candidates); err != nil {
+			return false, err
+		}
+
+		if len(candidates) > 0 {
+			return true, s.apply(cb, n, candidates, false)
+		}
+	}
+
+	return false, nil
+}
+
+// search looks for the node. How the node was searched is recorded if trace
+// is true.
+func (s *sketchNode) search(cb sketchNodeSearchCallbacks, trace bool) (*Node, error) {
+	var found bool
+
+	n := &Node{s: s}
+
+	if trace {
+		n.trace = &NodeTrace{}
+	}
+
+	if s.neighbor != nil {
+		var err error
+
+		if found, err = s.searchNeighbor(cb, n); err != nil {
+			return nil, err
+		}
+	} else if s.locator != nil {
+		areas, err := resolveSearchAreas(cb, n.trace, s.searchAreas, s.adjacent)
+		if err != nil {
 			return nil, err
-		} else if apply != nil {
-			apply(n)
+		}
+
+		n.searchAreas = append(n.searchAreas, areas...)
+
+		if found, err = s.searchAreasWith(cb, n, areas, s.locator, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, alt := range s.alternatives {
+		if found {
 			break
 		}
+
+		areas, err := resolveSearchAreas(cb, n.trace, alt.searchAreas, alt.adjacent)
+		if err != nil {
+			return nil, err
+		}
+
+		n.searchAreas = append(n.searchAreas, areas...)
+
+		if found, err = s.searchAreasWith(cb, n, areas, alt.locator, alt.name); err != nil {
+			return nil, err
+		}
+
+		if found {
+			n.alternative = alt
+		}
 	}
 
 	return n, nil
@@ -10,6 +10,7 @@ import (
 	"github.com/hansmi/dossier"
 	"github.com/hansmi/dossier/internal/sketcherror"
 	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/pkg/content"
 	"github.com/hansmi/dossier/pkg/geometry"
 	"github.com/hansmi/dossier/pkg/pagerange"
 	"github.com/hansmi/dossier/proto/reportpb"
@@ -66,6 +67,345 @@ line_text {}
 `, &sketchpb.Node{}),
 			wantName: "testline",
 		},
+		{
+			name: "neighbor",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  node: "label"
+  direction: RIGHT
+  max_distance { cm: 5 }
+  min_overlap: 0.5
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantName: "value",
+		},
+		{
+			name: "adjacent area",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+adjacent_areas {
+  node: "label"
+  direction: DOWN
+  lines: 2
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantName: "value",
+		},
+		{
+			name: "bad adjacent area",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+adjacent_areas {
+  node: "label"
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "neighbor and search areas",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+neighbor {
+  node: "label"
+  direction: RIGHT
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "neighbor without direction",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  node: "label"
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "neighbor without node",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  direction: DOWN
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "neighbor with bad overlap",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  node: "label"
+  direction: DOWN
+  min_overlap: 1.5
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "containment",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+containment {
+  mode: INTERSECTING
+  min_overlap: 0.5
+  clip_text: true
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantName: "value",
+		},
+		{
+			name: "containment with neighbor",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  node: "label"
+  direction: RIGHT
+}
+containment {
+  mode: CENTER
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "exclusions and negative match",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+exclusions {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+must_not_match {
+  adjacent_area { direction: UP lines: 1 }
+  line_text { regex: "Subtotal" }
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantName: "value",
+		},
+		{
+			name: "negative match without area",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+must_not_match {
+  line_text { regex: "Subtotal" }
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "negative match referencing node",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+must_not_match {
+  adjacent_area { node: "label" direction: UP }
+  line_text { regex: "Subtotal" }
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "negative match without matcher",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+must_not_match {
+  adjacent_area { direction: UP }
+}
+line_text {}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "multi-line",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "address"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+multi_line {
+  anchor { regex: "^Ship to:" }
+  text { regex: "(?P<name>.+)" }
+  left_tolerance { pt: 5 }
+  max_line_gap { pt: 3 }
+  stop_regex: "^\\s*$"
+  max_lines: 4
+}
+`, &sketchpb.Node{}),
+			wantName: "address",
+		},
+		{
+			name: "multi-line without anchor",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "address"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+multi_line {
+  max_lines: 4
+}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "multi-line with negative gap",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "address"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+multi_line {
+  anchor { regex: "^Ship to:" }
+  max_line_gap { pt: -1 }
+}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "alternatives only",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "invoice"
+alternatives {
+  name: "english"
+  search_areas {
+    top { abs {} }
+    right { abs {} }
+    bottom { abs {} }
+    left { abs {} }
+  }
+  line_text { regex: "^Invoice No\\." }
+}
+alternatives {
+  adjacent_areas { node: "header" direction: DOWN }
+  multi_line { anchor { regex: "^Rechnung" } }
+}
+`, &sketchpb.Node{}),
+			wantName: "invoice",
+		},
+		{
+			name: "alternative without search area",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "invoice"
+alternatives {
+  line_text {}
+}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrIncompleteConfig,
+		},
+		{
+			name: "alternative without matcher",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "invoice"
+alternatives {
+  search_areas {
+    top { abs {} }
+    right { abs {} }
+    bottom { abs {} }
+    left { abs {} }
+  }
+}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "duplicate alternative",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "invoice"
+alternatives {
+  name: "2"
+  search_areas {
+    top { abs {} }
+    right { abs {} }
+    bottom { abs {} }
+    left { abs {} }
+  }
+  line_text {}
+}
+alternatives {
+  search_areas {
+    top { abs {} }
+    right { abs {} }
+    bottom { abs {} }
+    left { abs {} }
+  }
+  line_text {}
+}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
+		{
+			name: "search areas with alternatives",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "invoice"
+search_areas {}
+alternatives {
+  search_areas {}
+  line_text {}
+}
+`, &sketchpb.Node{}),
+			wantErr: sketcherror.ErrBadConfig,
+		},
 		{
 			name: "sorted tags",
 			input: testutil.MustUnmarshalTextproto(t, `
@@ -110,7 +450,7 @@ line_text {}
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := sketchNodeFromProto(tc.input)
+			got, err := sketchNodeFromProto(tc.input, nil)
 
 			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
 				t.Errorf("Error diff (-want +got):\n%s", diff)
@@ -189,7 +529,7 @@ block_text {}
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			n, err := sketchNodeFromProto(tc.input)
+			n, err := sketchNodeFromProto(tc.input, nil)
 			if err != nil {
 				t.Errorf("sketchNodeFromProto() failed: %v", err)
 			}
@@ -216,17 +556,52 @@ type fakeSearchCallbacks struct {
 	nodeFeaturePosition func(string, sketchpb.NodeFeature) (geometry.Point, error)
 }
 
+func (c *fakeSearchCallbacks) page() (*dossier.Page, error) {
+	pages, err := c.doc.ParsePages(context.Background(), pagerange.MustSingle(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return pages[0], nil
+}
+
+func (c *fakeSearchCallbacks) Size() geometry.Size {
+	if c.doc == nil {
+		return geometry.Size{}
+	}
+
+	p, err := c.page()
+	if err != nil {
+		return geometry.Size{}
+	}
+
+	return p.Size()
+}
+
+func (c *fakeSearchCallbacks) ElementsInDirection(from geometry.Rect, dir geometry.Direction, opts dossier.DirectionalSearchOptions) ([]content.Element, error) {
+	if c.doc == nil {
+		return nil, nil
+	}
+
+	p, err := c.page()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.ElementsInDirection(from, dir, opts)
+}
+
 func (c *fakeSearchCallbacks) VisitElementsIntersecting(bounds geometry.Rect, visitor dossier.PageElementVisitorFunc) error {
 	if c.doc == nil {
 		return nil
 	}
 
-	pages, err := c.doc.ParsePages(context.Background(), pagerange.MustSingle(1))
+	p, err := c.page()
 	if err != nil {
 		return err
 	}
 
-	err = pages[0].VisitElementsIntersecting(bounds, visitor)
+	err = p.VisitElementsIntersecting(bounds, visitor)
 
 	if errors.Is(err, dossier.ErrStopVisitation) {
 		err = nil
@@ -390,11 +765,154 @@ text_match_groups {
   end: 16
   text: "sanctus"
 }
+`, &reportpb.Node{}),
+		},
+		{
+			name: "neighbor unknown reference",
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  node: "label"
+  direction: RIGHT
+}
+line_text {}
+`, &sketchpb.Node{}),
+			want: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+`, &reportpb.Node{}),
+		},
+		{
+			name: "neighbor",
+			cb: &fakeSearchCallbacks{
+				doc: readTestDocument(t, "corners.xml"),
+				nodeFeaturePosition: func(name string, feature sketchpb.NodeFeature) (geometry.Point, error) {
+					// Bounds of the "TL" line
+					return (&sketchNode{name: name}).featurePosition(geometry.RectFromPoints(28, 26, 40, 38), feature)
+				},
+			},
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  node: "label"
+  direction: RIGHT
+}
+line_text {
+  regex: "\\w+"
+}
+`, &sketchpb.Node{}),
+			want: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+valid: true
+bounds {
+  top { pt: 26 }
+  right { pt: 148 }
+  bottom { pt: 38 }
+  left { pt: 135 }
+}
+search_areas {
+  top { pt: 26 }
+  right { pt: 176 }
+  bottom { pt: 38 }
+  left { pt: 40 }
+}
+text: {
+  value: "TR"
+}
+text_match_groups {
+  end: 2
+  text: "TR"
+}
+`, &reportpb.Node{}),
+		},
+		{
+			name: "alternative",
+			cb: &fakeSearchCallbacks{
+				doc: readTestDocument(t, "corners.xml"),
+			},
+			input: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+search_areas {
+  top { abs {} }
+  right { abs { cm: 10 } }
+  bottom { abs { cm: 5 } }
+  left { abs {} }
+}
+line_text {
+  regex: "^XX$"
+}
+alternatives {
+  name: "unused"
+  search_areas {
+    top { abs { cm: 20 } }
+    right { abs { cm: 10 } }
+    bottom { abs { cm: 25 } }
+    left { abs {} }
+  }
+  line_text {}
+}
+alternatives {
+  name: "fallback"
+  search_areas {
+    top { abs {} }
+    right { abs { cm: 10 } }
+    bottom { abs { cm: 5 } }
+    left { abs {} }
+  }
+  line_text {
+    regex: "^T\\w$"
+  }
+}
+alternatives {
+  name: "skipped"
+  search_areas {
+    top { abs {} }
+    right { abs { cm: 10 } }
+    bottom { abs { cm: 5 } }
+    left { abs {} }
+  }
+  line_text {}
+}
+`, &sketchpb.Node{}),
+			want: testutil.MustUnmarshalTextproto(t, `
+name: "value"
+valid: true
+alternative { value: "fallback" }
+bounds {
+  top { pt: 26 }
+  right { pt: 40 }
+  bottom { pt: 38 }
+  left { pt: 28 }
+}
+search_areas {
+  top { pt: 0 }
+  right { pt: 283 }
+  bottom { pt: 142 }
+  left { pt: 0 }
+}
+search_areas {
+  top { pt: 567 }
+  right { pt: 283 }
+  bottom { pt: 709 }
+  left { pt: 0 }
+}
+search_areas {
+  top { pt: 0 }
+  right { pt: 283 }
+  bottom { pt: 142 }
+  left { pt: 0 }
+}
+text: {
+  value: "TL"
+}
+text_match_groups {
+  end: 2
+  text: "TL"
+}
 `, &reportpb.Node{}),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			node, err := sketchNodeFromProto(tc.input)
+			node, err := sketchNodeFromProto(tc.input, nil)
 			if err != nil {
 				t.Fatalf("sketchNodeFromProto() failed: %v", err)
 			}
@@ -403,7 +921,7 @@ text_match_groups {
 				tc.cb = &fakeSearchCallbacks{}
 			}
 
-			got, err := node.search(tc.cb)
+			got, err := node.search(tc.cb, false)
 
 			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
 				t.Errorf("Error diff (-want +got):\n%s", diff)
@@ -33,8 +33,8 @@ func determineNodeOrder(nodes []*sketchNode) ([]int, error) {
 
 	for idx, n := range nodes {
 		if first, ok := byName[n.name]; ok {
-			return nil, fmt.Errorf("%w: multiple nodes with name %q, first occurrence at index %d",
-				sketcherror.ErrBadConfig, n.name, first.index)
+			return nil, atPath(fmt.Errorf("%w: multiple nodes with name %q, first occurrence at index %d",
+				sketcherror.ErrBadConfig, n.name, first.index), "nodes", idx)
 		}
 
 		byName[n.name] = indexed{
@@ -63,8 +63,8 @@ func determineNodeOrder(nodes []*sketchNode) ([]int, error) {
 
 		if foundOnStack {
 			names := nodeNames(stack)
-			return fmt.Errorf("%w: recursive node reference: %s",
-				sketcherror.ErrBadConfig, strings.Join(names, " \u2192 "))
+			return atPath(fmt.Errorf("%w: recursive node reference: %s",
+				sketcherror.ErrBadConfig, strings.Join(names, " \u2192 ")), "nodes", cur.index)
 		}
 
 		if visited[cur.index] {
@@ -73,20 +73,18 @@ func determineNodeOrder(nodes []*sketchNode) ([]int, error) {
 
 		visited[cur.index] = true
 
-		for _, area := range cur.node.searchAreas {
-			for _, i := range area.RequiredNodeFeatures() {
-				other, ok := byName[i.NodeName()]
-				if !ok {
-					return fmt.Errorf("%w: node %q: referenced node %q not found", sketcherror.ErrBadConfig, cur.node.name, i.NodeName())
-				}
+		for _, i := range cur.node.requiredNodeFeatures() {
+			other, ok := byName[i.NodeName()]
+			if !ok {
+				return atPath(fmt.Errorf("%w: node %q: referenced node %q not found", sketcherror.ErrBadConfig, cur.node.name, i.NodeName()), "nodes", cur.index)
+			}
 
-				if _, err := other.node.featurePosition(geometry.Rect{}, i.Feature()); err != nil {
-					return fmt.Errorf("%w: node %q: %w", sketcherror.ErrBadConfig, cur.node.name, err)
-				}
+			if _, err := other.node.featurePosition(geometry.Rect{}, i.Feature()); err != nil {
+				return atPath(fmt.Errorf("%w: node %q: %w", sketcherror.ErrBadConfig, cur.node.name, err), "nodes", cur.index)
+			}
 
-				if err := visit(other); err != nil {
-					return err
-				}
+			if err := visit(other); err != nil {
+				return err
 			}
 		}
 
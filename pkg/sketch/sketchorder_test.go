@@ -95,6 +95,44 @@ block_text {}
 			},
 			want: []int{1, 2, 0},
 		},
+		{
+			name: "neighbor",
+			pbnodes: []*sketchpb.Node{
+				testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  node: "label"
+  direction: DOWN
+}
+line_text {}
+`, &sketchpb.Node{}),
+				testutil.MustUnmarshalTextproto(t, `
+name: "label"
+search_areas {
+  top { abs {} }
+  right { abs {} }
+  bottom { abs {} }
+  left { abs {} }
+}
+line_text {}
+`, &sketchpb.Node{}),
+			},
+			want: []int{1, 0},
+		},
+		{
+			name: "neighbor reference not found",
+			pbnodes: []*sketchpb.Node{
+				testutil.MustUnmarshalTextproto(t, `
+name: "value"
+neighbor {
+  node: "missing"
+  direction: UP
+}
+line_text {}
+`, &sketchpb.Node{}),
+			},
+			wantErr: ErrBadConfig,
+		},
 		{
 			name: "recursive reference",
 			pbnodes: []*sketchpb.Node{
@@ -184,7 +222,7 @@ block_text {}
 			var nodes []*sketchNode
 
 			for _, pb := range tc.pbnodes {
-				n, err := sketchNodeFromProto(pb)
+				n, err := sketchNodeFromProto(pb, nil)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -0,0 +1,256 @@
+package sketch
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hansmi/dossier/internal/textproto"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"gopkg.in/yaml.v3"
+)
+
+// sourceFormat is the encoding of a sketch file.
+type sourceFormat int
+
+const (
+	formatTextproto sourceFormat = iota
+	formatJSON
+	formatYAML
+)
+
+// detectSourceFormat determines the format of a sketch file from its name.
+// Files with unknown extensions are assumed to use the text format.
+func detectSourceFormat(name string) sourceFormat {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return formatJSON
+	case ".yaml", ".yml":
+		return formatYAML
+	}
+
+	return formatTextproto
+}
+
+// decodeSource decodes a sketch in the given format. The returned source file
+// is used to locate values for error messages.
+func decodeSource(format sourceFormat, name string, content []byte) (*sketchpb.Sketch, *sourceFile, error) {
+	var pb sketchpb.Sketch
+
+	switch format {
+	case formatJSON:
+		if err := protojson.Unmarshal(content, &pb); err != nil {
+			return nil, nil, err
+		}
+
+		return &pb, newYAMLSourceFile(name, content), nil
+
+	case formatYAML:
+		var root yaml.Node
+
+		if err := yaml.Unmarshal(content, &root); err != nil {
+			return nil, nil, err
+		}
+
+		if err := unmarshalYAML(&root, &pb); err != nil {
+			return nil, nil, err
+		}
+
+		return &pb, &sourceFile{name: name, root: yamlLocator{&root}}, nil
+	}
+
+	if err := prototext.Unmarshal(content, &pb); err != nil {
+		return nil, nil, err
+	}
+
+	return &pb, newSourceFile(name, content), nil
+}
+
+// unmarshalYAML decodes a YAML document using the JSON mapping of Protocol
+// Buffers.
+func unmarshalYAML(root *yaml.Node, pb *sketchpb.Sketch) error {
+	if root.Kind == 0 {
+		// Empty document
+		return nil
+	}
+
+	var value any
+
+	if err := root.Decode(&value); err != nil {
+		return err
+	}
+
+	value, err := yamlToJSON(value)
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return protojson.Unmarshal(buf, pb)
+}
+
+// yamlToJSON converts decoded YAML values to values supported by
+// [json.Marshal].
+func yamlToJSON(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			converted, err := yamlToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+
+			v[key] = converted
+		}
+
+	case map[any]any:
+		result := make(map[string]any, len(v))
+
+		for key, item := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported mapping key %v (%T)", key, key)
+			}
+
+			converted, err := yamlToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+
+			result[name] = converted
+		}
+
+		return result, nil
+
+	case []any:
+		for idx, item := range v {
+			converted, err := yamlToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+
+			v[idx] = converted
+		}
+	}
+
+	return value, nil
+}
+
+// locator finds values by path within a parsed sketch file.
+type locator interface {
+	Locate(textproto.Path) (textproto.Position, bool)
+}
+
+var _ locator = (*textproto.Message)(nil)
+var _ locator = yamlLocator{}
+
+// yamlLocator finds values within a YAML or JSON document. Fields may use
+// their original or JSON names.
+type yamlLocator struct {
+	root *yaml.Node
+}
+
+func newYAMLSourceFile(name string, content []byte) *sourceFile {
+	f := &sourceFile{name: name}
+
+	var root yaml.Node
+
+	if err := yaml.Unmarshal(content, &root); err == nil {
+		f.root = yamlLocator{&root}
+	}
+
+	return f
+}
+
+func yamlPosition(n *yaml.Node) textproto.Position {
+	return textproto.Position{
+		Line:   n.Line,
+		Column: n.Column,
+	}
+}
+
+// jsonFieldName converts a field name to its lowerCamelCase JSON name.
+func jsonFieldName(name string) string {
+	var sb strings.Builder
+
+	upper := false
+
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && 'a' <= r && r <= 'z':
+			sb.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			sb.WriteRune(r)
+			upper = false
+		}
+	}
+
+	return sb.String()
+}
+
+// Locate returns the position of the value at the given path like
+// [textproto.Message.Locate].
+func (l yamlLocator) Locate(path textproto.Path) (textproto.Position, bool) {
+	n := l.root
+
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+
+	pos := yamlPosition(n)
+
+	for _, elem := range path {
+		for n.Kind == yaml.AliasNode {
+			n = n.Alias
+		}
+
+		if n.Kind != yaml.MappingNode {
+			return pos, false
+		}
+
+		var key, value *yaml.Node
+
+		for idx := 0; idx+1 < len(n.Content); idx += 2 {
+			if name := n.Content[idx].Value; name == elem.Name || name == jsonFieldName(elem.Name) {
+				key = n.Content[idx]
+				value = n.Content[idx+1]
+			}
+		}
+
+		if value == nil {
+			return pos, false
+		}
+
+		for value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+
+		if value.Kind == yaml.SequenceNode {
+			if elem.Index >= len(value.Content) {
+				return yamlPosition(key), false
+			}
+
+			n = value.Content[elem.Index]
+			pos = yamlPosition(n)
+		} else {
+			if elem.Index != 0 {
+				return yamlPosition(key), false
+			}
+
+			n = value
+			pos = yamlPosition(key)
+		}
+	}
+
+	return pos, true
+}
@@ -0,0 +1,208 @@
+package sketch
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/internal/sketcherror"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/internal/textproto"
+	"github.com/hansmi/dossier/proto/sketchpb"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDecodeSource(t *testing.T) {
+	want := testutil.MustUnmarshalTextproto(t, `
+tags: "sketch"
+nodes {
+  name: "total"
+  search_areas {
+    top_left { abs { left { mm: 1 } top { cm: 2 } } }
+    width { cm: 3 }
+    height { cm: 1 }
+  }
+  line_text { regex: "^\\s*Total\\b" bounds_from_match: true }
+  selection { strategy: TOPMOST }
+}
+`, &sketchpb.Sketch{})
+
+	for _, tc := range []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "json",
+			file: "sketch.json",
+			content: `{
+  "tags": ["sketch"],
+  "nodes": [{
+    "name": "total",
+    "searchAreas": [{
+      "top_left": {"abs": {"left": {"mm": 1}, "top": {"cm": 2}}},
+      "width": {"cm": 3},
+      "height": {"cm": 1}
+    }],
+    "lineText": {"regex": "^\\s*Total\\b", "boundsFromMatch": true},
+    "selection": {"strategy": "TOPMOST"}
+  }]
+}`,
+		},
+		{
+			name: "yaml",
+			file: "sketch.YML",
+			content: `
+tags: [sketch]
+nodes:
+  - name: total
+    search_areas:
+      - top_left: {abs: {left: {mm: 1}, top: {cm: 2}}}
+        width: {cm: 3}
+        height: {cm: 1}
+    line_text:
+      regex: ^\s*Total\b
+      bounds_from_match: true
+    selection: {strategy: TOPMOST}
+`,
+		},
+		{
+			name: "textproto",
+			file: "sketch.txtpb",
+			content: `
+tags: "sketch"
+nodes {
+  name: "total"
+  search_areas {
+    top_left { abs { left { mm: 1 } top { cm: 2 } } }
+    width { cm: 3 }
+    height { cm: 1 }
+  }
+  line_text { regex: "^\\s*Total\\b" bounds_from_match: true }
+  selection { strategy: TOPMOST }
+}
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := decodeSource(detectSourceFormat(tc.file), tc.file, []byte(tc.content))
+			if err != nil {
+				t.Fatalf("decodeSource() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("decodeSource() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestYAMLLocator(t *testing.T) {
+	var root yaml.Node
+
+	if err := yaml.Unmarshal([]byte(`nodes:
+  - name: a
+  - name: b
+    searchAreas:
+      - width: {cm: 1}
+params: []
+`), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		path   textproto.Path
+		want   textproto.Position
+		wantOK bool
+	}{
+		{
+			name:   "root",
+			want:   textproto.Position{Line: 1, Column: 1},
+			wantOK: true,
+		},
+		{
+			name:   "list item",
+			path:   textproto.Path{{Name: "nodes", Index: 1}},
+			want:   textproto.Position{Line: 3, Column: 5},
+			wantOK: true,
+		},
+		{
+			name:   "json name",
+			path:   textproto.Path{{Name: "nodes", Index: 1}, {Name: "search_areas", Index: 0}, {Name: "width", Index: 0}},
+			want:   textproto.Position{Line: 5, Column: 9},
+			wantOK: true,
+		},
+		{
+			name: "missing index",
+			path: textproto.Path{{Name: "nodes", Index: 2}},
+			want: textproto.Position{Line: 1, Column: 1},
+		},
+		{
+			name: "missing field",
+			path: textproto.Path{{Name: "nodes", Index: 0}, {Name: "tags", Index: 0}},
+			want: textproto.Position{Line: 2, Column: 5},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := yamlLocator{&root}.Locate(tc.path)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Locate() diff (-want +got):\n%s", diff)
+			}
+
+			if ok != tc.wantOK {
+				t.Errorf("Locate() returned %v, want %v", ok, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestCompileFromYAMLErrors(t *testing.T) {
+	_, err := CompileFromYAML([]byte(`
+nodes:
+  - name: a
+    line_text: {}
+`))
+
+	if diff := cmp.Diff([]Position{{Line: 3, Column: 5}}, configErrorPositions(t, err)); diff != "" {
+		t.Errorf("Position diff (-want +got):\n%s", diff)
+	}
+
+	if _, err := CompileFromJSON([]byte(`{"nodes": [{"name": "a", "unknown": 1}]}`)); err == nil {
+		t.Errorf("CompileFromJSON() succeeded with unknown field")
+	}
+}
+
+func TestCompileFileFormats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.yaml": &fstest.MapFile{Data: []byte(`
+imports:
+  - {path: common.json, namespace: common}
+nodes:
+  - name: total
+    adjacentAreas:
+      - {node: common.label, direction: RIGHT}
+    lineText: {regex: '\d+'}
+`)},
+		"common.json": &fstest.MapFile{Data: []byte(`{
+  "nodes": [{
+    "name": "label",
+    "searchAreas": [{"topLeft": {"abs": {"left": {}, "top": {}}}, "width": {"cm": 1}}],
+    "lineText": {}
+  }]
+}`)},
+	}
+
+	_, err := CompileFile(fsys, "main.yaml")
+
+	if want := []Position{{File: "common.json", Line: 4, Column: 21}}; !cmp.Equal(want, configErrorPositions(t, err)) {
+		t.Errorf("CompileFile() failed with %v, want error at %v", err, want)
+	}
+
+	if !errors.Is(err, sketcherror.ErrIncompleteConfig) {
+		t.Errorf("CompileFile() failed with %v, want %v", err, sketcherror.ErrIncompleteConfig)
+	}
+}
@@ -3,25 +3,29 @@ package sketch
 import (
 	"regexp"
 
-	"github.com/hansmi/dossier"
 	"github.com/hansmi/dossier/pkg/content"
 	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/sketchpb"
 )
 
 type textLocator struct {
 	line            bool
 	pattern         *regexp.Regexp
+	fuzzy           *fuzzyMatcher
+	normalizer      *normalizer
 	boundsFromMatch bool
 }
 
 func newTextLocatorFromProto(pbnode interface {
 	GetRegex() string
 	GetBoundsFromMatch() bool
-}, line bool) (*textLocator, error) {
+	GetFuzzy() *sketchpb.Node_Fuzzy
+}, line bool, norm *normalizer) (*textLocator, error) {
 	var err error
 
 	l := &textLocator{
 		line:            line,
+		normalizer:      norm,
 		boundsFromMatch: pbnode.GetBoundsFromMatch(),
 	}
 
@@ -29,50 +33,122 @@ func newTextLocatorFromProto(pbnode interface {
 		return nil, err
 	}
 
+	if pb := pbnode.GetFuzzy(); pb != nil {
+		if l.fuzzy, err = fuzzyMatcherFromProto(pb); err != nil {
+			return nil, err
+		}
+	}
+
 	return l, nil
 }
 
-func (l *textLocator) locate(cb documentPage, bounds geometry.Rect) (func(*Node), error) {
-	var result func(*Node)
-
-	visit := func(elem content.TextElement) error {
-		if !bounds.Contains(elem.Bounds()) {
-			return nil
+// textElement returns the element if it's of the kind searched by the
+// locator.
+func (l *textLocator) textElement(elem content.Element) content.TextElement {
+	if l.line {
+		if line, ok := elem.(content.Line); ok {
+			return line
 		}
+	} else if block, ok := elem.(content.Block); ok {
+		return block
+	}
 
-		text := elem.Text()
+	return nil
+}
 
-		if m := evaluateMatch(l.pattern, text); m != nil {
-			bounds := elem.Bounds()
+func (l *textLocator) evaluate(_ documentPage, elem content.Element, trace *AreaTrace) (*locatorMatch, error) {
+	if telem := l.textElement(elem); telem != nil {
+		return l.evaluateTraced(telem, trace), nil
+	}
 
-			if l.boundsFromMatch {
-				g0 := m.MustGroup(0)
-				bounds = elem.RangeBounds(g0.Start, g0.End)
-			}
+	return nil, nil
+}
 
-			result = func(n *Node) {
-				n.valid = true
-				n.bounds = bounds
-				n.text = &text
-				n.textMatch = m
-			}
+// evaluateTraced evaluates the text and records the outcome in the trace.
+func (l *textLocator) evaluateTraced(telem content.TextElement, trace *AreaTrace) *locatorMatch {
+	m := l.evaluateText(telem)
 
-			return dossier.ErrStopVisitation
-		}
+	if m == nil {
+		trace.add(telem, RejectedNoMatch)
+	} else {
+		m.trace = trace.add(telem, NotRejected)
+	}
 
-		return nil
+	return m
+}
+
+func (l *textLocator) evaluateText(telem content.TextElement) *locatorMatch {
+	text := telem.Text()
+	matchText := text
+
+	var normalized *normalizedText
+
+	if l.normalizer != nil {
+		normalized = l.normalizer.normalize(text)
+		matchText = normalized.text
 	}
 
-	var visitor dossier.PageElementVisitorFunc
+	var m *TextMatch
 
-	if l.line {
-		visitor = dossier.AsPageElementVisitor(func(elem content.Line) error {
-			return visit(elem)
-		})
+	if l.fuzzy != nil {
+		m = evaluateFuzzyMatch(l.fuzzy, l.pattern, matchText)
 	} else {
-		visitor = dossier.AsPageElementVisitor(func(elem content.Block) error {
-			return visit(elem)
-		})
+		m = evaluateMatch(l.pattern, matchText)
+	}
+
+	if m == nil {
+		return nil
+	}
+
+	m.normalized = normalized
+
+	bounds := telem.Bounds()
+
+	if l.boundsFromMatch {
+		g0 := m.MustGroup(0)
+		bounds = telem.RangeBounds(g0.Start, g0.End)
+	}
+
+	return &locatorMatch{
+		bounds: bounds,
+		apply: func(n *Node) {
+			n.valid = true
+			n.bounds = bounds
+			n.text = &text
+			n.textMatch = m
+		},
+	}
+}
+
+func (l *textLocator) locate(cb documentPage, bounds geometry.Rect, policy containment, trace *AreaTrace) ([]*locatorMatch, error) {
+	var result []*locatorMatch
+
+	visitor := func(elem content.Element) error {
+		telem := l.textElement(elem)
+		if telem == nil {
+			return nil
+		}
+
+		if !policy.accepts(bounds, elem.Bounds()) {
+			trace.add(telem, RejectedOutsideArea)
+			return nil
+		}
+
+		if policy.clipText && !bounds.Contains(telem.Bounds()) {
+			clipped := clipTextElement(telem, bounds)
+			if clipped == nil {
+				trace.add(telem, RejectedOutsideArea)
+				return nil
+			}
+
+			telem = clipped
+		}
+
+		if m := l.evaluateTraced(telem, trace); m != nil {
+			result = append(result, m)
+		}
+
+		return nil
 	}
 
 	if err := cb.VisitElementsIntersecting(bounds, visitor); err != nil {
@@ -17,7 +17,8 @@ type TextMatchGroup struct {
 	Start int
 	End   int
 
-	// Text captured by the group.
+	// Text captured by the group. Normalized if text normalization is
+	// enabled.
 	Text string
 }
 
@@ -36,6 +37,13 @@ type TextMatch struct {
 	text    string
 	indexes []int
 	names   []string
+
+	fuzzy    bool
+	distance int
+
+	// Mapping to the original text if the match was performed on normalized
+	// text. Nil otherwise.
+	normalized *normalizedText
 }
 
 // evaluateMatch tries to match a regular expression pattern in a string.
@@ -55,6 +63,38 @@ func evaluateMatch(pat *regexp.Regexp, text string) *TextMatch {
 	}
 }
 
+// evaluateFuzzyMatch looks for an approximate match of literal text followed
+// by a regular expression match in the remainder. The first group spans both.
+// Returns nil if no match is found.
+func evaluateFuzzyMatch(fm *fuzzyMatcher, pat *regexp.Regexp, text string) *TextMatch {
+	start, end, distance, ok := fm.find(text)
+	if !ok {
+		return nil
+	}
+
+	indexes := pat.FindStringSubmatchIndex(text[end:])
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	for idx, value := range indexes {
+		if value >= 0 {
+			indexes[idx] = value + end
+		}
+	}
+
+	indexes[0] = start
+
+	return &TextMatch{
+		expr:     pat,
+		text:     text,
+		indexes:  indexes,
+		names:    pat.SubexpNames(),
+		fuzzy:    true,
+		distance: distance,
+	}
+}
+
 // Pattern returns the pattern source text for the matched regular expression.
 func (m *TextMatch) Pattern() string {
 	return m.expr.String()
@@ -68,6 +108,10 @@ func (m *TextMatch) group(idx int) TextMatchGroup {
 	}
 	if g.Start >= 0 && g.End >= 0 {
 		g.Text = m.text[g.Start:g.End]
+
+		if m.normalized != nil {
+			g.Start, g.End = m.normalized.originalRange(g.Start, g.End)
+		}
 	}
 
 	return g
@@ -126,3 +170,9 @@ func (m *TextMatch) MustNamed(name string) TextMatchGroup {
 
 	return *g
 }
+
+// EditDistance returns the number of edits between the text of a fuzzy match
+// and the expected text. The second return value is false for other matches.
+func (m *TextMatch) EditDistance() (int, bool) {
+	return m.distance, m.fuzzy
+}
@@ -0,0 +1,192 @@
+package sketch
+
+import (
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/proto/reportpb"
+)
+
+// AnalyzeOption configures the analysis of pages.
+type AnalyzeOption func(*analyzeOptions)
+
+type analyzeOptions struct {
+	trace bool
+}
+
+// WithTrace enables recording how nodes were searched. See [Node.Trace].
+func WithTrace() AnalyzeOption {
+	return func(o *analyzeOptions) {
+		o.trace = true
+	}
+}
+
+// RejectReason describes why a candidate element was not used for a node.
+type RejectReason int
+
+const (
+	// The candidate passed all checks.
+	NotRejected RejectReason = iota
+
+	// The element is not located within the search area according to the
+	// containment policy.
+	RejectedOutsideArea
+
+	// The text didn't match.
+	RejectedNoMatch
+
+	// The element is located in an exclusion area.
+	RejectedExcluded
+
+	// Content matching a negative condition was found.
+	RejectedNegativeMatch
+)
+
+func (r RejectReason) asProto() reportpb.Trace_Candidate_Rejection {
+	switch r {
+	case RejectedOutsideArea:
+		return reportpb.Trace_Candidate_OUTSIDE_AREA
+	case RejectedNoMatch:
+		return reportpb.Trace_Candidate_NO_MATCH
+	case RejectedExcluded:
+		return reportpb.Trace_Candidate_EXCLUDED
+	case RejectedNegativeMatch:
+		return reportpb.Trace_Candidate_NEGATIVE_MATCH
+	}
+
+	return reportpb.Trace_Candidate_NOT_REJECTED
+}
+
+func (r RejectReason) String() string {
+	switch r {
+	case RejectedOutsideArea:
+		return "outside area"
+	case RejectedNoMatch:
+		return "no match"
+	case RejectedExcluded:
+		return "excluded"
+	case RejectedNegativeMatch:
+		return "negative match"
+	}
+
+	return "not rejected"
+}
+
+// CandidateTrace is an element considered for a node.
+type CandidateTrace struct {
+	Bounds   geometry.Rect
+	Text     string
+	Rejected RejectReason
+
+	// Whether the candidate was chosen for the node.
+	Selected bool
+}
+
+func (c *CandidateTrace) AsProto(unit geometry.LengthUnit) *reportpb.Trace_Candidate {
+	return &reportpb.Trace_Candidate{
+		Bounds:    c.Bounds.AsProto(unit),
+		Text:      c.Text,
+		Rejection: c.Rejected.asProto(),
+		Selected:  c.Selected,
+	}
+}
+
+// AreaTrace records the candidates considered within a single search area.
+type AreaTrace struct {
+	// Name of the node alternative. Empty for the node's own matcher.
+	Alternative string
+
+	Bounds geometry.Rect
+
+	// Whether the area is the result of a neighbor search.
+	Neighbor bool
+
+	Candidates []*CandidateTrace
+}
+
+// add records an element. Nil is returned if tracing is disabled.
+func (t *AreaTrace) add(elem content.TextElement, reason RejectReason) *CandidateTrace {
+	if t == nil {
+		return nil
+	}
+
+	c := &CandidateTrace{
+		Bounds:   elem.Bounds(),
+		Text:     elem.Text(),
+		Rejected: reason,
+	}
+
+	t.Candidates = append(t.Candidates, c)
+
+	return c
+}
+
+func (t *AreaTrace) AsProto(unit geometry.LengthUnit) *reportpb.Trace_Area {
+	pb := &reportpb.Trace_Area{
+		Alternative: t.Alternative,
+		Bounds:      t.Bounds.AsProto(unit),
+		Neighbor:    t.Neighbor,
+	}
+
+	for _, c := range t.Candidates {
+		pb.Candidates = append(pb.Candidates, c.AsProto(unit))
+	}
+
+	return pb
+}
+
+// NodeTrace records how a node was searched.
+type NodeTrace struct {
+	Areas []*AreaTrace
+
+	// Dependencies on nodes whose position is unknown, usually wrapping
+	// [ErrNodePositionUnknown].
+	Unresolved []error
+}
+
+// addArea starts recording a new area. Nil is returned if tracing is
+// disabled.
+func (t *NodeTrace) addArea(alternative string, bounds geometry.Rect, neighbor bool) *AreaTrace {
+	if t == nil {
+		return nil
+	}
+
+	area := &AreaTrace{
+		Alternative: alternative,
+		Bounds:      bounds,
+		Neighbor:    neighbor,
+	}
+
+	t.Areas = append(t.Areas, area)
+
+	return area
+}
+
+// unresolved records a dependency which could not be resolved. Repeated
+// errors with the same message are recorded only once.
+func (t *NodeTrace) unresolved(err error) {
+	if t == nil {
+		return
+	}
+
+	for _, other := range t.Unresolved {
+		if other.Error() == err.Error() {
+			return
+		}
+	}
+
+	t.Unresolved = append(t.Unresolved, err)
+}
+
+func (t *NodeTrace) AsProto(unit geometry.LengthUnit) *reportpb.Trace {
+	pb := &reportpb.Trace{}
+
+	for _, area := range t.Areas {
+		pb.Areas = append(pb.Areas, area.AsProto(unit))
+	}
+
+	for _, err := range t.Unresolved {
+		pb.Unresolved = append(pb.Unresolved, err.Error())
+	}
+
+	return pb
+}
@@ -0,0 +1,110 @@
+package sketchrender
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/hansmi/dossier/pkg/geometry"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// rasterCanvas draws onto a copy of a page image. Page coordinates are scaled
+// to the image size.
+type rasterCanvas struct {
+	img *image.RGBA
+
+	// Pixels per point.
+	sx, sy float64
+}
+
+var _ canvas = (*rasterCanvas)(nil)
+
+func newRasterCanvas(bg image.Image, size geometry.Size) *rasterCanvas {
+	bounds := bg.Bounds()
+
+	c := &rasterCanvas{
+		img: image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy())),
+		sx:  1,
+		sy:  1,
+	}
+
+	draw.Draw(c.img, c.img.Bounds(), bg, bounds.Min, draw.Src)
+
+	if size.Width > 0 {
+		c.sx = float64(bounds.Dx()) / size.Width.Pt()
+	}
+
+	if size.Height > 0 {
+		c.sy = float64(bounds.Dy()) / size.Height.Pt()
+	}
+
+	return c
+}
+
+func (c *rasterCanvas) toPixels(r geometry.Rect) image.Rectangle {
+	return image.Rect(
+		int(math.Floor(r.Left.Pt()*c.sx)),
+		int(math.Floor(r.Top.Pt()*c.sy)),
+		int(math.Ceil(r.Right.Pt()*c.sx)),
+		int(math.Ceil(r.Bottom.Pt()*c.sy)),
+	)
+}
+
+func (c *rasterCanvas) fill(r image.Rectangle, src image.Image) {
+	draw.Draw(c.img, r.Intersect(c.img.Bounds()), src, image.Point{}, draw.Over)
+}
+
+func (c *rasterCanvas) rect(r geometry.Rect, s style) {
+	pr := c.toPixels(r)
+
+	c.fill(pr, image.NewUniform(s.fill))
+
+	w := max(1, int(math.Round(s.width*c.sx)))
+	stroke := image.NewUniform(s.stroke)
+
+	for _, edge := range []image.Rectangle{
+		image.Rect(pr.Min.X, pr.Min.Y, pr.Max.X, pr.Min.Y+w),
+		image.Rect(pr.Min.X, pr.Max.Y-w, pr.Max.X, pr.Max.Y),
+		image.Rect(pr.Min.X, pr.Min.Y+w, pr.Min.X+w, pr.Max.Y-w),
+		image.Rect(pr.Max.X-w, pr.Min.Y+w, pr.Max.X, pr.Max.Y-w),
+	} {
+		c.fill(edge, stroke)
+	}
+}
+
+func (c *rasterCanvas) label(p geometry.Point, text string, s style) {
+	const padding = 2
+
+	face := basicfont.Face7x13
+	metrics := face.Metrics()
+	height := (metrics.Ascent + metrics.Descent).Ceil() + 2*padding
+	width := font.MeasureString(face, text).Ceil() + 2*padding
+
+	box := image.Rect(0, 0, width, height).Add(image.Pt(
+		int(math.Floor(p.Left.Pt()*c.sx)),
+		int(math.Floor(p.Top.Pt()*c.sy))-height,
+	))
+
+	// Keep the label within the image.
+	if box.Min.Y < 0 {
+		box = box.Add(image.Pt(0, -box.Min.Y))
+	}
+
+	if over := box.Max.X - c.img.Bounds().Max.X; over > 0 {
+		box = box.Sub(image.Pt(min(over, box.Min.X), 0))
+	}
+
+	c.fill(box, image.NewUniform(s.stroke))
+
+	d := font.Drawer{
+		Dst:  c.img,
+		Src:  image.White,
+		Face: face,
+		Dot:  fixed.P(box.Min.X+padding, box.Min.Y+padding+metrics.Ascent.Ceil()),
+	}
+
+	d.DrawString(text)
+}
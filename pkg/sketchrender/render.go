@@ -0,0 +1,105 @@
+// Package sketchrender produces static page images annotated with the results
+// of a sketch analysis.
+package sketchrender
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+
+	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/pkg/geometry"
+	"github.com/hansmi/dossier/pkg/renderformat"
+	"github.com/hansmi/dossier/pkg/sketch"
+)
+
+// canvas is an abstract drawing surface using page coordinates.
+type canvas interface {
+	rect(geometry.Rect, style)
+	label(geometry.Point, string, style)
+}
+
+// annotate draws the search areas and bounds of all nodes in the report. Labels
+// are placed above the top left corner of valid nodes.
+func annotate(c canvas, report *sketch.PageReport) {
+	for _, n := range report.Nodes() {
+		s := searchAreaStyle
+
+		if !n.Valid() {
+			s = invalidSearchAreaStyle
+		}
+
+		for _, area := range n.SearchAreas() {
+			c.rect(area, s)
+		}
+	}
+
+	for _, n := range report.Nodes() {
+		if n.Valid() {
+			c.rect(n.Bounds(), nodeStyle)
+			c.label(n.Bounds().TopLeft(), n.Name(), nodeStyle)
+		}
+	}
+}
+
+func renderPNG(ctx context.Context, p *dossier.Page, report *sketch.PageReport, r *renderformat.PNG) error {
+	var buf bytes.Buffer
+
+	if err := p.RenderUsing(ctx, &renderformat.PNG{
+		Width:  r.Width,
+		Height: r.Height,
+		Output: &buf,
+	}); err != nil {
+		return err
+	}
+
+	bg, err := png.Decode(&buf)
+	if err != nil {
+		return fmt.Errorf("decoding page image: %w", err)
+	}
+
+	img := newRasterCanvas(bg, p.Size())
+
+	annotate(img, report)
+
+	return png.Encode(r.Output, img.img)
+}
+
+func renderSVG(ctx context.Context, p *dossier.Page, report *sketch.PageReport, r *renderformat.SVG) error {
+	var buf bytes.Buffer
+
+	if err := p.RenderUsing(ctx, &renderformat.SVG{
+		Width:  r.Width,
+		Height: r.Height,
+		Output: &buf,
+	}); err != nil {
+		return err
+	}
+
+	c := newVectorCanvas(p.Size(), image.Pt(r.Width, r.Height))
+
+	annotate(c, report)
+
+	_, err := c.writeTo(r.Output, "image/svg+xml", buf.Bytes())
+
+	return err
+}
+
+// RenderPage draws a page with the nodes of an analysis report on top. Search
+// areas and node bounds are drawn as rectangles, valid nodes are labelled with
+// their name. The background is produced by [dossier.Page.RenderUsing].
+// Supported renderers are [renderformat.PNG] and [renderformat.SVG].
+func RenderPage(ctx context.Context, p *dossier.Page, report *sketch.PageReport, r renderformat.Renderer) error {
+	switch r := r.(type) {
+	case *renderformat.PNG:
+		return renderPNG(ctx, p, report, r)
+
+	case *renderformat.SVG:
+		return renderSVG(ctx, p, report, r)
+	}
+
+	return fmt.Errorf("%w: render format %q is not supported", os.ErrInvalid, r.String())
+}
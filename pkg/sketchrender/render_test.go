@@ -0,0 +1,166 @@
+package sketchrender
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hansmi/dossier"
+	"github.com/hansmi/dossier/internal/muparser"
+	"github.com/hansmi/dossier/internal/testfiles"
+	"github.com/hansmi/dossier/internal/testutil"
+	"github.com/hansmi/dossier/pkg/pagerange"
+	"github.com/hansmi/dossier/pkg/parsertest"
+	"github.com/hansmi/dossier/pkg/sketch"
+)
+
+const testSketch = `
+nodes: {
+  name: "tl"
+  search_areas {
+    top_left {
+      abs: { left: { mm: 1 } top: { mm: 1 } }
+    }
+    width: { cm: 3 }
+    height: { cm: 2 }
+  }
+  block_text: {
+    regex: "(?i)^\\s*tl\\b"
+  }
+}
+nodes: {
+  name: "missing"
+  search_areas {
+    top_left {
+      abs: { left: { cm: 3 } top: { cm: 5 } }
+    }
+    width: { cm: 1 }
+    height: { cm: 1 }
+  }
+  line_text: {
+    regex: "does not exist"
+  }
+}
+`
+
+func analyzeTestPage(t *testing.T) (*dossier.Page, *sketch.PageReport) {
+	t.Helper()
+
+	f, err := testfiles.All.Open("corners.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	pages, err := muparser.ReadPagesFromXML(f)
+	if err != nil {
+		t.Fatalf("ReadPagesFromXML() failed: %v", err)
+	}
+
+	doc := dossier.NewDocument(
+		testutil.MustWriteFile(t, filepath.Join(t.TempDir(), "empty"), nil),
+		dossier.WithStaticDocumentParser(&parsertest.SimpleParser{
+			Pages: pages,
+		}))
+
+	parsed, err := doc.ParsePages(context.Background(), pagerange.MustSingle(1))
+	if err != nil {
+		t.Fatalf("ParsePages() failed: %v", err)
+	}
+
+	s, err := sketch.CompileFromTextprotoString(testSketch)
+	if err != nil {
+		t.Fatalf("CompileFromTextprotoString() failed: %v", err)
+	}
+
+	report, err := s.AnalyzePage(parsed[0])
+	if err != nil {
+		t.Fatalf("AnalyzePage() failed: %v", err)
+	}
+
+	return parsed[0], report
+}
+
+func TestRasterCanvas(t *testing.T) {
+	page, report := analyzeTestPage(t)
+
+	size := page.Size()
+	bg := image.NewRGBA(image.Rect(0, 0, int(size.Width.Pt()), int(size.Height.Pt())))
+
+	draw.Draw(bg, bg.Bounds(), image.White, image.Point{}, draw.Src)
+
+	c := newRasterCanvas(bg, size)
+
+	annotate(c, report)
+
+	if diff := cmp.Diff(bg.Bounds(), c.img.Bounds()); diff != "" {
+		t.Errorf("Image bounds diff (-want +got):\n%s", diff)
+	}
+
+	bounds := c.toPixels(report.NodeByName("tl").Bounds())
+
+	got := color.NRGBAModel.Convert(c.img.At(bounds.Min.X, bounds.Max.Y-1))
+
+	if diff := cmp.Diff(color.Color(nodeStyle.stroke), got); diff != "" {
+		t.Errorf("Node border color diff (-want +got):\n%s", diff)
+	}
+
+	if got := c.img.At(bg.Bounds().Max.X-1, bg.Bounds().Max.Y-1); got != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("Background modified: %v", got)
+	}
+}
+
+func TestVectorCanvas(t *testing.T) {
+	_, report := analyzeTestPage(t)
+
+	c := newVectorCanvas(report.Size(), image.Pt(400, 0))
+
+	annotate(c, report)
+
+	var buf bytes.Buffer
+
+	if _, err := c.writeTo(&buf, "image/png", []byte("bg")); err != nil {
+		t.Errorf("writeTo() failed: %v", err)
+	}
+
+	got := buf.String()
+
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="400" height="567"`,
+		`href="data:image/png;base64,Ymc="`,
+		`>tl</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output lacks %q:\n%s", want, got)
+		}
+	}
+
+	if diff := cmp.Diff(3, strings.Count(got, "<rect ")); diff != "" {
+		t.Errorf("Rectangle count diff (-want +got):\n%s", diff)
+	}
+}
+
+type unsupportedRenderer struct{}
+
+func (unsupportedRenderer) String() string {
+	return "unsupported"
+}
+
+func TestRenderPageUnsupported(t *testing.T) {
+	page, report := analyzeTestPage(t)
+
+	err := RenderPage(context.Background(), page, report, unsupportedRenderer{})
+
+	if diff := cmp.Diff(os.ErrInvalid, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Error diff (-want +got):\n%s", diff)
+	}
+}
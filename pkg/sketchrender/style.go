@@ -0,0 +1,41 @@
+package sketchrender
+
+import (
+	"fmt"
+	"image/color"
+)
+
+type style struct {
+	stroke color.NRGBA
+	fill   color.NRGBA
+
+	// Stroke width in points.
+	width float64
+}
+
+var (
+	// Colors are derived from the web viewer.
+	nodeStyle = style{
+		stroke: color.NRGBA{0x66, 0x10, 0xf2, 0xff},
+		fill:   color.NRGBA{0x66, 0x10, 0xf2, 0x20},
+		width:  1.5,
+	}
+	searchAreaStyle = style{
+		stroke: color.NRGBA{0xe0, 0xa8, 0x00, 0xff},
+		fill:   color.NRGBA{0xff, 0xc1, 0x07, 0x30},
+		width:  1,
+	}
+	invalidSearchAreaStyle = style{
+		stroke: color.NRGBA{0xdc, 0x35, 0x45, 0xff},
+		fill:   color.NRGBA{0xdc, 0x35, 0x45, 0x30},
+		width:  1,
+	}
+)
+
+func svgColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func svgOpacity(c color.NRGBA) string {
+	return fmt.Sprintf("%.3g", float64(c.A)/0xff)
+}
@@ -0,0 +1,94 @@
+package sketchrender
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/hansmi/dossier/pkg/geometry"
+)
+
+// vectorCanvas collects SVG elements. The SVG user space uses points.
+type vectorCanvas struct {
+	size   geometry.Size
+	pixels image.Point
+	buf    bytes.Buffer
+}
+
+var _ canvas = (*vectorCanvas)(nil)
+
+// newVectorCanvas creates an SVG canvas. The output dimensions are set in
+// pixels if given (missing dimensions are derived using the page aspect
+// ratio), otherwise the physical page size is used.
+func newVectorCanvas(size geometry.Size, pixels image.Point) *vectorCanvas {
+	if size.Width > 0 && size.Height > 0 {
+		ratio := size.Height.Pt() / size.Width.Pt()
+
+		if pixels.X > 0 && pixels.Y <= 0 {
+			pixels.Y = int(float64(pixels.X) * ratio)
+		} else if pixels.X <= 0 && pixels.Y > 0 {
+			pixels.X = int(float64(pixels.Y) / ratio)
+		}
+	}
+
+	return &vectorCanvas{
+		size:   size,
+		pixels: pixels,
+	}
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+
+	xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
+
+func (c *vectorCanvas) rect(r geometry.Rect, s style) {
+	fmt.Fprintf(&c.buf, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f"`+
+		` fill="%s" fill-opacity="%s" stroke="%s" stroke-opacity="%s" stroke-width="%.2f"/>`+"\n",
+		r.Left.Pt(), r.Top.Pt(), r.Width().Pt(), r.Height().Pt(),
+		svgColor(s.fill), svgOpacity(s.fill),
+		svgColor(s.stroke), svgOpacity(s.stroke), s.width)
+}
+
+func (c *vectorCanvas) label(p geometry.Point, text string, s style) {
+	const fontSize = 8
+
+	top := max(fontSize, p.Top.Pt())
+
+	fmt.Fprintf(&c.buf, `<text x="%.2f" y="%.2f" font-family="sans-serif" font-size="%d"`+
+		` fill="%s" stroke="white" stroke-width="2" paint-order="stroke">%s</text>`+"\n",
+		p.Left.Pt(), top-2, fontSize, svgColor(s.stroke), escapeXML(text))
+}
+
+// writeTo writes a complete SVG document with the given background image and
+// all drawn elements.
+func (c *vectorCanvas) writeTo(w io.Writer, bgType string, bg []byte) (int64, error) {
+	var buf bytes.Buffer
+
+	width := fmt.Sprintf("%.2fpt", c.size.Width.Pt())
+	height := fmt.Sprintf("%.2fpt", c.size.Height.Pt())
+
+	if c.pixels.X > 0 && c.pixels.Y > 0 {
+		width = fmt.Sprint(c.pixels.X)
+		height = fmt.Sprint(c.pixels.Y)
+	}
+
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %.2f %.2f">`+"\n",
+		width, height, c.size.Width.Pt(), c.size.Height.Pt())
+
+	if len(bg) > 0 {
+		fmt.Fprintf(&buf, `<image x="0" y="0" width="%.2f" height="%.2f" preserveAspectRatio="none" href="data:%s;base64,%s"/>`+"\n",
+			c.size.Width.Pt(), c.size.Height.Pt(), bgType, base64.StdEncoding.EncodeToString(bg))
+	}
+
+	buf.Write(c.buf.Bytes())
+	buf.WriteString("</svg>\n")
+
+	return buf.WriteTo(w)
+}
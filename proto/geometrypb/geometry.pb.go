@@ -29,6 +29,7 @@ type Length struct {
 	//	*Length_Mm
 	//	*Length_Cm
 	//	*Length_In
+	//	*Length_Param
 	Value         isLength_Value `protobuf_oneof:"value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -107,6 +108,15 @@ func (x *Length) GetIn() float64 {
 	return 0
 }
 
+func (x *Length) GetParam() string {
+	if x != nil {
+		if x, ok := x.Value.(*Length_Param); ok {
+			return x.Param
+		}
+	}
+	return ""
+}
+
 type isLength_Value interface {
 	isLength_Value()
 }
@@ -127,6 +137,12 @@ type Length_In struct {
 	In float64 `protobuf:"fixed64,4,opt,name=in,proto3,oneof"`
 }
 
+type Length_Param struct {
+	// Name of a sketch parameter providing the length. Only valid within
+	// sketches.
+	Param string `protobuf:"bytes,5,opt,name=param,proto3,oneof"`
+}
+
 func (*Length_Pt) isLength_Value() {}
 
 func (*Length_Mm) isLength_Value() {}
@@ -135,6 +151,8 @@ func (*Length_Cm) isLength_Value() {}
 
 func (*Length_In) isLength_Value() {}
 
+func (*Length_Param) isLength_Value() {}
+
 type Point struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Left          *Length                `protobuf:"bytes,1,opt,name=left,proto3" json:"left,omitempty"`
@@ -311,12 +329,13 @@ var File_geometry_proto protoreflect.FileDescriptor
 
 const file_geometry_proto_rawDesc = "" +
 	"\n" +
-	"\x0egeometry.proto\x12\x10dossier.geometry\"Y\n" +
+	"\x0egeometry.proto\x12\x10dossier.geometry\"q\n" +
 	"\x06Length\x12\x10\n" +
 	"\x02pt\x18\x01 \x01(\x01H\x00R\x02pt\x12\x10\n" +
 	"\x02mm\x18\x02 \x01(\x01H\x00R\x02mm\x12\x10\n" +
 	"\x02cm\x18\x03 \x01(\x01H\x00R\x02cm\x12\x10\n" +
-	"\x02in\x18\x04 \x01(\x01H\x00R\x02inB\a\n" +
+	"\x02in\x18\x04 \x01(\x01H\x00R\x02in\x12\x16\n" +
+	"\x05param\x18\x05 \x01(\tH\x00R\x05paramB\a\n" +
 	"\x05value\"a\n" +
 	"\x05Point\x12,\n" +
 	"\x04left\x18\x01 \x01(\v2\x18.dossier.geometry.LengthR\x04left\x12*\n" +
@@ -375,6 +394,7 @@ func file_geometry_proto_init() {
 		(*Length_Mm)(nil),
 		(*Length_Cm)(nil),
 		(*Length_In)(nil),
+		(*Length_Param)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -23,6 +23,124 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Trace_Candidate_Rejection int32
+
+const (
+	// The candidate passed all checks.
+	Trace_Candidate_NOT_REJECTED Trace_Candidate_Rejection = 0
+	// The element is not located within the search area according to the
+	// containment policy.
+	Trace_Candidate_OUTSIDE_AREA Trace_Candidate_Rejection = 1
+	// The text didn't match.
+	Trace_Candidate_NO_MATCH Trace_Candidate_Rejection = 2
+	// The element is located in an exclusion area.
+	Trace_Candidate_EXCLUDED Trace_Candidate_Rejection = 3
+	// Content matching a negative condition was found.
+	Trace_Candidate_NEGATIVE_MATCH Trace_Candidate_Rejection = 4
+)
+
+// Enum value maps for Trace_Candidate_Rejection.
+var (
+	Trace_Candidate_Rejection_name = map[int32]string{
+		0: "NOT_REJECTED",
+		1: "OUTSIDE_AREA",
+		2: "NO_MATCH",
+		3: "EXCLUDED",
+		4: "NEGATIVE_MATCH",
+	}
+	Trace_Candidate_Rejection_value = map[string]int32{
+		"NOT_REJECTED":   0,
+		"OUTSIDE_AREA":   1,
+		"NO_MATCH":       2,
+		"EXCLUDED":       3,
+		"NEGATIVE_MATCH": 4,
+	}
+)
+
+func (x Trace_Candidate_Rejection) Enum() *Trace_Candidate_Rejection {
+	p := new(Trace_Candidate_Rejection)
+	*p = x
+	return p
+}
+
+func (x Trace_Candidate_Rejection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Trace_Candidate_Rejection) Descriptor() protoreflect.EnumDescriptor {
+	return file_report_proto_enumTypes[0].Descriptor()
+}
+
+func (Trace_Candidate_Rejection) Type() protoreflect.EnumType {
+	return &file_report_proto_enumTypes[0]
+}
+
+func (x Trace_Candidate_Rejection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Trace_Candidate_Rejection.Descriptor instead.
+func (Trace_Candidate_Rejection) EnumDescriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{1, 0, 0}
+}
+
+type Computed_Status int32
+
+const (
+	// A referenced node was not found.
+	Computed_SKIPPED Computed_Status = 0
+	// The expression was evaluated and produced true or a non-bool value.
+	Computed_PASSED Computed_Status = 1
+	// The expression produced false.
+	Computed_FAILED Computed_Status = 2
+	// Evaluating the expression failed, e.g. because a text could not be
+	// converted to a number.
+	Computed_ERROR Computed_Status = 3
+)
+
+// Enum value maps for Computed_Status.
+var (
+	Computed_Status_name = map[int32]string{
+		0: "SKIPPED",
+		1: "PASSED",
+		2: "FAILED",
+		3: "ERROR",
+	}
+	Computed_Status_value = map[string]int32{
+		"SKIPPED": 0,
+		"PASSED":  1,
+		"FAILED":  2,
+		"ERROR":   3,
+	}
+)
+
+func (x Computed_Status) Enum() *Computed_Status {
+	p := new(Computed_Status)
+	*p = x
+	return p
+}
+
+func (x Computed_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Computed_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_report_proto_enumTypes[1].Descriptor()
+}
+
+func (Computed_Status) Type() protoreflect.EnumType {
+	return &file_report_proto_enumTypes[1]
+}
+
+func (x Computed_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Computed_Status.Descriptor instead.
+func (Computed_Status) EnumDescriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{3, 0}
+}
+
 type TextMatchGroup struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -92,6 +210,61 @@ func (x *TextMatchGroup) GetText() string {
 	return ""
 }
 
+// Record of how a node was searched. Only available if tracing was enabled.
+type Trace struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Areas []*Trace_Area          `protobuf:"bytes,1,rep,name=areas,proto3" json:"areas,omitempty"`
+	// Dependencies on nodes whose position is unknown, e.g. search areas
+	// relative to an invalid node.
+	Unresolved    []string `protobuf:"bytes,2,rep,name=unresolved,proto3" json:"unresolved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trace) Reset() {
+	*x = Trace{}
+	mi := &file_report_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trace) ProtoMessage() {}
+
+func (x *Trace) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trace.ProtoReflect.Descriptor instead.
+func (*Trace) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Trace) GetAreas() []*Trace_Area {
+	if x != nil {
+		return x.Areas
+	}
+	return nil
+}
+
+func (x *Trace) GetUnresolved() []string {
+	if x != nil {
+		return x.Unresolved
+	}
+	return nil
+}
+
 type Node struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Sketch node name
@@ -102,19 +275,32 @@ type Node struct {
 	Bounds *geometrypb.Rect `protobuf:"bytes,3,opt,name=bounds,proto3" json:"bounds,omitempty"`
 	// Searched rectangles until the node was found to be valid or invalid.
 	SearchAreas []*geometrypb.Rect `protobuf:"bytes,4,rep,name=search_areas,json=searchAreas,proto3" json:"search_areas,omitempty"`
+	// Reason for a node being invalid beyond not finding a match, e.g. an
+	// ambiguous match.
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
 	// Complete node text.
 	Text *wrapperspb.StringValue `protobuf:"bytes,10,opt,name=text,proto3" json:"text,omitempty"`
 	// Regular expression match groups.
 	TextMatchGroups []*TextMatchGroup `protobuf:"bytes,11,rep,name=text_match_groups,json=textMatchGroups,proto3" json:"text_match_groups,omitempty"`
+	// Text of individual lines for multi-line matches.
+	Lines []string `protobuf:"bytes,13,rep,name=lines,proto3" json:"lines,omitempty"`
+	// Number of edits between the text of a fuzzy match and the expected text.
+	// Not set for other matches.
+	EditDistance *wrapperspb.UInt32Value `protobuf:"bytes,12,opt,name=edit_distance,json=editDistance,proto3" json:"edit_distance,omitempty"`
+	// Name of the node alternative whose candidates were used. Not set if the
+	// node's own matcher was used.
+	Alternative *wrapperspb.StringValue `protobuf:"bytes,14,opt,name=alternative,proto3" json:"alternative,omitempty"`
 	// Sketch node tags.
-	Tags          []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	Tags []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Search trace.
+	Trace         *Trace `protobuf:"bytes,16,opt,name=trace,proto3" json:"trace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Node) Reset() {
 	*x = Node{}
-	mi := &file_report_proto_msgTypes[1]
+	mi := &file_report_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -126,7 +312,7 @@ func (x *Node) String() string {
 func (*Node) ProtoMessage() {}
 
 func (x *Node) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[1]
+	mi := &file_report_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -139,7 +325,7 @@ func (x *Node) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Node.ProtoReflect.Descriptor instead.
 func (*Node) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{1}
+	return file_report_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Node) GetName() string {
@@ -170,6 +356,13 @@ func (x *Node) GetSearchAreas() []*geometrypb.Rect {
 	return nil
 }
 
+func (x *Node) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 func (x *Node) GetText() *wrapperspb.StringValue {
 	if x != nil {
 		return x.Text
@@ -184,6 +377,27 @@ func (x *Node) GetTextMatchGroups() []*TextMatchGroup {
 	return nil
 }
 
+func (x *Node) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *Node) GetEditDistance() *wrapperspb.UInt32Value {
+	if x != nil {
+		return x.EditDistance
+	}
+	return nil
+}
+
+func (x *Node) GetAlternative() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Alternative
+	}
+	return nil
+}
+
 func (x *Node) GetTags() []string {
 	if x != nil {
 		return x.Tags
@@ -191,19 +405,180 @@ func (x *Node) GetTags() []string {
 	return nil
 }
 
+func (x *Node) GetTrace() *Trace {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+type Computed struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Computed node name.
+	Name   string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status Computed_Status `protobuf:"varint,2,opt,name=status,proto3,enum=dossier.sketch.report.Computed_Status" json:"status,omitempty"`
+	// Result of a successful evaluation.
+	//
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Computed_BoolValue
+	//	*Computed_NumberValue
+	//	*Computed_TextValue
+	//	*Computed_DateValue
+	Value isComputed_Value `protobuf_oneof:"value"`
+	// Reason for skipping or failing evaluation.
+	Error string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	// Computed node tags.
+	Tags          []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Computed) Reset() {
+	*x = Computed{}
+	mi := &file_report_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Computed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Computed) ProtoMessage() {}
+
+func (x *Computed) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Computed.ProtoReflect.Descriptor instead.
+func (*Computed) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Computed) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Computed) GetStatus() Computed_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Computed_SKIPPED
+}
+
+func (x *Computed) GetValue() isComputed_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Computed) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.Value.(*Computed_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+func (x *Computed) GetNumberValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*Computed_NumberValue); ok {
+			return x.NumberValue
+		}
+	}
+	return ""
+}
+
+func (x *Computed) GetTextValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*Computed_TextValue); ok {
+			return x.TextValue
+		}
+	}
+	return ""
+}
+
+func (x *Computed) GetDateValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*Computed_DateValue); ok {
+			return x.DateValue
+		}
+	}
+	return ""
+}
+
+func (x *Computed) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Computed) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type isComputed_Value interface {
+	isComputed_Value()
+}
+
+type Computed_BoolValue struct {
+	BoolValue bool `protobuf:"varint,3,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type Computed_NumberValue struct {
+	// Number in decimal notation.
+	NumberValue string `protobuf:"bytes,4,opt,name=number_value,json=numberValue,proto3,oneof"`
+}
+
+type Computed_TextValue struct {
+	TextValue string `protobuf:"bytes,5,opt,name=text_value,json=textValue,proto3,oneof"`
+}
+
+type Computed_DateValue struct {
+	// Date in the "YYYY-MM-DD" format.
+	DateValue string `protobuf:"bytes,6,opt,name=date_value,json=dateValue,proto3,oneof"`
+}
+
+func (*Computed_BoolValue) isComputed_Value() {}
+
+func (*Computed_NumberValue) isComputed_Value() {}
+
+func (*Computed_TextValue) isComputed_Value() {}
+
+func (*Computed_DateValue) isComputed_Value() {}
+
 type Page struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// 1-based page number.
 	Number        int32            `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
 	Size          *geometrypb.Size `protobuf:"bytes,2,opt,name=size,proto3" json:"size,omitempty"`
 	Nodes         []*Node          `protobuf:"bytes,10,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Computed      []*Computed      `protobuf:"bytes,11,rep,name=computed,proto3" json:"computed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Page) Reset() {
 	*x = Page{}
-	mi := &file_report_proto_msgTypes[2]
+	mi := &file_report_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -215,7 +590,7 @@ func (x *Page) String() string {
 func (*Page) ProtoMessage() {}
 
 func (x *Page) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[2]
+	mi := &file_report_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -228,7 +603,7 @@ func (x *Page) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Page.ProtoReflect.Descriptor instead.
 func (*Page) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{2}
+	return file_report_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Page) GetNumber() int32 {
@@ -252,9 +627,18 @@ func (x *Page) GetNodes() []*Node {
 	return nil
 }
 
+func (x *Page) GetComputed() []*Computed {
+	if x != nil {
+		return x.Computed
+	}
+	return nil
+}
+
 type Document struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	Pages []*Page                `protobuf:"bytes,1,rep,name=pages,proto3" json:"pages,omitempty"`
+	// Whether any computed node failed or produced an error on any page.
+	ChecksFailed bool `protobuf:"varint,2,opt,name=checks_failed,json=checksFailed,proto3" json:"checks_failed,omitempty"`
 	// Sketch tags.
 	Tags          []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -263,7 +647,7 @@ type Document struct {
 
 func (x *Document) Reset() {
 	*x = Document{}
-	mi := &file_report_proto_msgTypes[3]
+	mi := &file_report_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -275,7 +659,7 @@ func (x *Document) String() string {
 func (*Document) ProtoMessage() {}
 
 func (x *Document) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[3]
+	mi := &file_report_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -288,7 +672,7 @@ func (x *Document) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Document.ProtoReflect.Descriptor instead.
 func (*Document) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{3}
+	return file_report_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Document) GetPages() []*Page {
@@ -298,6 +682,13 @@ func (x *Document) GetPages() []*Page {
 	return nil
 }
 
+func (x *Document) GetChecksFailed() bool {
+	if x != nil {
+		return x.ChecksFailed
+	}
+	return false
+}
+
 func (x *Document) GetTags() []string {
 	if x != nil {
 		return x.Tags
@@ -305,6 +696,148 @@ func (x *Document) GetTags() []string {
 	return nil
 }
 
+type Trace_Candidate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Element bounds.
+	Bounds *geometrypb.Rect `protobuf:"bytes,1,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	// Element text.
+	Text      string                    `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Rejection Trace_Candidate_Rejection `protobuf:"varint,3,opt,name=rejection,proto3,enum=dossier.sketch.report.Trace_Candidate_Rejection" json:"rejection,omitempty"`
+	// Whether the candidate was chosen for the node.
+	Selected      bool `protobuf:"varint,4,opt,name=selected,proto3" json:"selected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trace_Candidate) Reset() {
+	*x = Trace_Candidate{}
+	mi := &file_report_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trace_Candidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trace_Candidate) ProtoMessage() {}
+
+func (x *Trace_Candidate) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trace_Candidate.ProtoReflect.Descriptor instead.
+func (*Trace_Candidate) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *Trace_Candidate) GetBounds() *geometrypb.Rect {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *Trace_Candidate) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Trace_Candidate) GetRejection() Trace_Candidate_Rejection {
+	if x != nil {
+		return x.Rejection
+	}
+	return Trace_Candidate_NOT_REJECTED
+}
+
+func (x *Trace_Candidate) GetSelected() bool {
+	if x != nil {
+		return x.Selected
+	}
+	return false
+}
+
+type Trace_Area struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the node alternative. Empty for the node's own matcher.
+	Alternative string           `protobuf:"bytes,1,opt,name=alternative,proto3" json:"alternative,omitempty"`
+	Bounds      *geometrypb.Rect `protobuf:"bytes,2,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	// Whether the area is the result of a neighbor search.
+	Neighbor bool `protobuf:"varint,3,opt,name=neighbor,proto3" json:"neighbor,omitempty"`
+	// Elements considered in the area.
+	Candidates    []*Trace_Candidate `protobuf:"bytes,4,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trace_Area) Reset() {
+	*x = Trace_Area{}
+	mi := &file_report_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trace_Area) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trace_Area) ProtoMessage() {}
+
+func (x *Trace_Area) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trace_Area.ProtoReflect.Descriptor instead.
+func (*Trace_Area) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *Trace_Area) GetAlternative() string {
+	if x != nil {
+		return x.Alternative
+	}
+	return ""
+}
+
+func (x *Trace_Area) GetBounds() *geometrypb.Rect {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *Trace_Area) GetNeighbor() bool {
+	if x != nil {
+		return x.Neighbor
+	}
+	return false
+}
+
+func (x *Trace_Area) GetCandidates() []*Trace_Candidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
 var File_report_proto protoreflect.FileDescriptor
 
 const file_report_proto_rawDesc = "" +
@@ -314,23 +847,73 @@ const file_report_proto_rawDesc = "" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05start\x18\x02 \x01(\x05R\x05start\x12\x10\n" +
 	"\x03end\x18\x03 \x01(\x05R\x03end\x12\x12\n" +
-	"\x04text\x18\x04 \x01(\tR\x04text\"\xb4\x02\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\"\xbe\x04\n" +
+	"\x05Trace\x127\n" +
+	"\x05areas\x18\x01 \x03(\v2!.dossier.sketch.report.Trace.AreaR\x05areas\x12\x1e\n" +
+	"\n" +
+	"unresolved\x18\x02 \x03(\tR\n" +
+	"unresolved\x1a\x9c\x02\n" +
+	"\tCandidate\x12.\n" +
+	"\x06bounds\x18\x01 \x01(\v2\x16.dossier.geometry.RectR\x06bounds\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12N\n" +
+	"\trejection\x18\x03 \x01(\x0e20.dossier.sketch.report.Trace.Candidate.RejectionR\trejection\x12\x1a\n" +
+	"\bselected\x18\x04 \x01(\bR\bselected\"_\n" +
+	"\tRejection\x12\x10\n" +
+	"\fNOT_REJECTED\x10\x00\x12\x10\n" +
+	"\fOUTSIDE_AREA\x10\x01\x12\f\n" +
+	"\bNO_MATCH\x10\x02\x12\f\n" +
+	"\bEXCLUDED\x10\x03\x12\x12\n" +
+	"\x0eNEGATIVE_MATCH\x10\x04\x1a\xbc\x01\n" +
+	"\x04Area\x12 \n" +
+	"\valternative\x18\x01 \x01(\tR\valternative\x12.\n" +
+	"\x06bounds\x18\x02 \x01(\v2\x16.dossier.geometry.RectR\x06bounds\x12\x1a\n" +
+	"\bneighbor\x18\x03 \x01(\bR\bneighbor\x12F\n" +
+	"\n" +
+	"candidates\x18\x04 \x03(\v2&.dossier.sketch.report.Trace.CandidateR\n" +
+	"candidates\"\x97\x04\n" +
 	"\x04Node\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05valid\x18\x02 \x01(\bR\x05valid\x12.\n" +
 	"\x06bounds\x18\x03 \x01(\v2\x16.dossier.geometry.RectR\x06bounds\x129\n" +
-	"\fsearch_areas\x18\x04 \x03(\v2\x16.dossier.geometry.RectR\vsearchAreas\x120\n" +
+	"\fsearch_areas\x18\x04 \x03(\v2\x16.dossier.geometry.RectR\vsearchAreas\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x120\n" +
 	"\x04text\x18\n" +
 	" \x01(\v2\x1c.google.protobuf.StringValueR\x04text\x12Q\n" +
-	"\x11text_match_groups\x18\v \x03(\v2%.dossier.sketch.report.TextMatchGroupR\x0ftextMatchGroups\x12\x12\n" +
-	"\x04tags\x18\x0f \x03(\tR\x04tags\"}\n" +
+	"\x11text_match_groups\x18\v \x03(\v2%.dossier.sketch.report.TextMatchGroupR\x0ftextMatchGroups\x12\x14\n" +
+	"\x05lines\x18\r \x03(\tR\x05lines\x12A\n" +
+	"\redit_distance\x18\f \x01(\v2\x1c.google.protobuf.UInt32ValueR\feditDistance\x12>\n" +
+	"\valternative\x18\x0e \x01(\v2\x1c.google.protobuf.StringValueR\valternative\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\x122\n" +
+	"\x05trace\x18\x10 \x01(\v2\x1c.dossier.sketch.report.TraceR\x05trace\"\xd3\x02\n" +
+	"\bComputed\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12>\n" +
+	"\x06status\x18\x02 \x01(\x0e2&.dossier.sketch.report.Computed.StatusR\x06status\x12\x1f\n" +
+	"\n" +
+	"bool_value\x18\x03 \x01(\bH\x00R\tboolValue\x12#\n" +
+	"\fnumber_value\x18\x04 \x01(\tH\x00R\vnumberValue\x12\x1f\n" +
+	"\n" +
+	"text_value\x18\x05 \x01(\tH\x00R\ttextValue\x12\x1f\n" +
+	"\n" +
+	"date_value\x18\x06 \x01(\tH\x00R\tdateValue\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\"8\n" +
+	"\x06Status\x12\v\n" +
+	"\aSKIPPED\x10\x00\x12\n" +
+	"\n" +
+	"\x06PASSED\x10\x01\x12\n" +
+	"\n" +
+	"\x06FAILED\x10\x02\x12\t\n" +
+	"\x05ERROR\x10\x03B\a\n" +
+	"\x05value\"\xba\x01\n" +
 	"\x04Page\x12\x16\n" +
 	"\x06number\x18\x01 \x01(\x05R\x06number\x12*\n" +
 	"\x04size\x18\x02 \x01(\v2\x16.dossier.geometry.SizeR\x04size\x121\n" +
 	"\x05nodes\x18\n" +
-	" \x03(\v2\x1b.dossier.sketch.report.NodeR\x05nodes\"Q\n" +
+	" \x03(\v2\x1b.dossier.sketch.report.NodeR\x05nodes\x12;\n" +
+	"\bcomputed\x18\v \x03(\v2\x1f.dossier.sketch.report.ComputedR\bcomputed\"v\n" +
 	"\bDocument\x121\n" +
-	"\x05pages\x18\x01 \x03(\v2\x1b.dossier.sketch.report.PageR\x05pages\x12\x12\n" +
+	"\x05pages\x18\x01 \x03(\v2\x1b.dossier.sketch.report.PageR\x05pages\x12#\n" +
+	"\rchecks_failed\x18\x02 \x01(\bR\fchecksFailed\x12\x12\n" +
 	"\x04tags\x18\x0f \x03(\tR\x04tagsB*Z(github.com/hansmi/dossier/proto/reportpbb\x06proto3"
 
 var (
@@ -345,29 +928,47 @@ func file_report_proto_rawDescGZIP() []byte {
 	return file_report_proto_rawDescData
 }
 
-var file_report_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_report_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_report_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_report_proto_goTypes = []any{
-	(*TextMatchGroup)(nil),         // 0: dossier.sketch.report.TextMatchGroup
-	(*Node)(nil),                   // 1: dossier.sketch.report.Node
-	(*Page)(nil),                   // 2: dossier.sketch.report.Page
-	(*Document)(nil),               // 3: dossier.sketch.report.Document
-	(*geometrypb.Rect)(nil),        // 4: dossier.geometry.Rect
-	(*wrapperspb.StringValue)(nil), // 5: google.protobuf.StringValue
-	(*geometrypb.Size)(nil),        // 6: dossier.geometry.Size
+	(Trace_Candidate_Rejection)(0), // 0: dossier.sketch.report.Trace.Candidate.Rejection
+	(Computed_Status)(0),           // 1: dossier.sketch.report.Computed.Status
+	(*TextMatchGroup)(nil),         // 2: dossier.sketch.report.TextMatchGroup
+	(*Trace)(nil),                  // 3: dossier.sketch.report.Trace
+	(*Node)(nil),                   // 4: dossier.sketch.report.Node
+	(*Computed)(nil),               // 5: dossier.sketch.report.Computed
+	(*Page)(nil),                   // 6: dossier.sketch.report.Page
+	(*Document)(nil),               // 7: dossier.sketch.report.Document
+	(*Trace_Candidate)(nil),        // 8: dossier.sketch.report.Trace.Candidate
+	(*Trace_Area)(nil),             // 9: dossier.sketch.report.Trace.Area
+	(*geometrypb.Rect)(nil),        // 10: dossier.geometry.Rect
+	(*wrapperspb.StringValue)(nil), // 11: google.protobuf.StringValue
+	(*wrapperspb.UInt32Value)(nil), // 12: google.protobuf.UInt32Value
+	(*geometrypb.Size)(nil),        // 13: dossier.geometry.Size
 }
 var file_report_proto_depIdxs = []int32{
-	4, // 0: dossier.sketch.report.Node.bounds:type_name -> dossier.geometry.Rect
-	4, // 1: dossier.sketch.report.Node.search_areas:type_name -> dossier.geometry.Rect
-	5, // 2: dossier.sketch.report.Node.text:type_name -> google.protobuf.StringValue
-	0, // 3: dossier.sketch.report.Node.text_match_groups:type_name -> dossier.sketch.report.TextMatchGroup
-	6, // 4: dossier.sketch.report.Page.size:type_name -> dossier.geometry.Size
-	1, // 5: dossier.sketch.report.Page.nodes:type_name -> dossier.sketch.report.Node
-	2, // 6: dossier.sketch.report.Document.pages:type_name -> dossier.sketch.report.Page
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	9,  // 0: dossier.sketch.report.Trace.areas:type_name -> dossier.sketch.report.Trace.Area
+	10, // 1: dossier.sketch.report.Node.bounds:type_name -> dossier.geometry.Rect
+	10, // 2: dossier.sketch.report.Node.search_areas:type_name -> dossier.geometry.Rect
+	11, // 3: dossier.sketch.report.Node.text:type_name -> google.protobuf.StringValue
+	2,  // 4: dossier.sketch.report.Node.text_match_groups:type_name -> dossier.sketch.report.TextMatchGroup
+	12, // 5: dossier.sketch.report.Node.edit_distance:type_name -> google.protobuf.UInt32Value
+	11, // 6: dossier.sketch.report.Node.alternative:type_name -> google.protobuf.StringValue
+	3,  // 7: dossier.sketch.report.Node.trace:type_name -> dossier.sketch.report.Trace
+	1,  // 8: dossier.sketch.report.Computed.status:type_name -> dossier.sketch.report.Computed.Status
+	13, // 9: dossier.sketch.report.Page.size:type_name -> dossier.geometry.Size
+	4,  // 10: dossier.sketch.report.Page.nodes:type_name -> dossier.sketch.report.Node
+	5,  // 11: dossier.sketch.report.Page.computed:type_name -> dossier.sketch.report.Computed
+	6,  // 12: dossier.sketch.report.Document.pages:type_name -> dossier.sketch.report.Page
+	10, // 13: dossier.sketch.report.Trace.Candidate.bounds:type_name -> dossier.geometry.Rect
+	0,  // 14: dossier.sketch.report.Trace.Candidate.rejection:type_name -> dossier.sketch.report.Trace.Candidate.Rejection
+	10, // 15: dossier.sketch.report.Trace.Area.bounds:type_name -> dossier.geometry.Rect
+	8,  // 16: dossier.sketch.report.Trace.Area.candidates:type_name -> dossier.sketch.report.Trace.Candidate
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_report_proto_init() }
@@ -375,18 +976,25 @@ func file_report_proto_init() {
 	if File_report_proto != nil {
 		return
 	}
+	file_report_proto_msgTypes[3].OneofWrappers = []any{
+		(*Computed_BoolValue)(nil),
+		(*Computed_NumberValue)(nil),
+		(*Computed_TextValue)(nil),
+		(*Computed_DateValue)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_report_proto_rawDesc), len(file_report_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      2,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_report_proto_goTypes,
 		DependencyIndexes: file_report_proto_depIdxs,
+		EnumInfos:         file_report_proto_enumTypes,
 		MessageInfos:      file_report_proto_msgTypes,
 	}.Build()
 	File_report_proto = out.File
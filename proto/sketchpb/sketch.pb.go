@@ -79,6 +79,186 @@ func (NodeFeature) EnumDescriptor() ([]byte, []int) {
 	return file_sketch_proto_rawDescGZIP(), []int{0}
 }
 
+// Directions on a page.
+type Direction int32
+
+const (
+	Direction_DIRECTION_UNSPECIFIED Direction = 0
+	Direction_UP                    Direction = 1
+	Direction_DOWN                  Direction = 2
+	Direction_LEFT                  Direction = 3
+	Direction_RIGHT                 Direction = 4
+)
+
+// Enum value maps for Direction.
+var (
+	Direction_name = map[int32]string{
+		0: "DIRECTION_UNSPECIFIED",
+		1: "UP",
+		2: "DOWN",
+		3: "LEFT",
+		4: "RIGHT",
+	}
+	Direction_value = map[string]int32{
+		"DIRECTION_UNSPECIFIED": 0,
+		"UP":                    1,
+		"DOWN":                  2,
+		"LEFT":                  3,
+		"RIGHT":                 4,
+	}
+)
+
+func (x Direction) Enum() *Direction {
+	p := new(Direction)
+	*p = x
+	return p
+}
+
+func (x Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_sketch_proto_enumTypes[1].Descriptor()
+}
+
+func (Direction) Type() protoreflect.EnumType {
+	return &file_sketch_proto_enumTypes[1]
+}
+
+func (x Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Direction.Descriptor instead.
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{1}
+}
+
+// Strategies for choosing among multiple candidates matching within the same
+// search area.
+type SelectionStrategy int32
+
+const (
+	// First candidate in reading order (top to bottom, left to right). Neighbor
+	// searches use the closest candidate instead.
+	SelectionStrategy_READING_ORDER SelectionStrategy = 0
+	SelectionStrategy_TOPMOST       SelectionStrategy = 1
+	SelectionStrategy_BOTTOMMOST    SelectionStrategy = 2
+	SelectionStrategy_LEFTMOST      SelectionStrategy = 3
+	SelectionStrategy_RIGHTMOST     SelectionStrategy = 4
+	// Candidate closest to the anchor point.
+	SelectionStrategy_CLOSEST SelectionStrategy = 5
+	// Candidate with the largest area.
+	SelectionStrategy_LARGEST SelectionStrategy = 6
+	// Fail if more than one candidate matches. The node is then invalid.
+	SelectionStrategy_UNIQUE SelectionStrategy = 7
+)
+
+// Enum value maps for SelectionStrategy.
+var (
+	SelectionStrategy_name = map[int32]string{
+		0: "READING_ORDER",
+		1: "TOPMOST",
+		2: "BOTTOMMOST",
+		3: "LEFTMOST",
+		4: "RIGHTMOST",
+		5: "CLOSEST",
+		6: "LARGEST",
+		7: "UNIQUE",
+	}
+	SelectionStrategy_value = map[string]int32{
+		"READING_ORDER": 0,
+		"TOPMOST":       1,
+		"BOTTOMMOST":    2,
+		"LEFTMOST":      3,
+		"RIGHTMOST":     4,
+		"CLOSEST":       5,
+		"LARGEST":       6,
+		"UNIQUE":        7,
+	}
+)
+
+func (x SelectionStrategy) Enum() *SelectionStrategy {
+	p := new(SelectionStrategy)
+	*p = x
+	return p
+}
+
+func (x SelectionStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SelectionStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_sketch_proto_enumTypes[2].Descriptor()
+}
+
+func (SelectionStrategy) Type() protoreflect.EnumType {
+	return &file_sketch_proto_enumTypes[2]
+}
+
+func (x SelectionStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SelectionStrategy.Descriptor instead.
+func (SelectionStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{2}
+}
+
+type Containment_Mode int32
+
+const (
+	// Content must be fully contained within the search area.
+	Containment_CONTAINED Containment_Mode = 0
+	// Content must intersect with the search area.
+	Containment_INTERSECTING Containment_Mode = 1
+	// The center point of the content must be located within the search
+	// area.
+	Containment_CENTER Containment_Mode = 2
+)
+
+// Enum value maps for Containment_Mode.
+var (
+	Containment_Mode_name = map[int32]string{
+		0: "CONTAINED",
+		1: "INTERSECTING",
+		2: "CENTER",
+	}
+	Containment_Mode_value = map[string]int32{
+		"CONTAINED":    0,
+		"INTERSECTING": 1,
+		"CENTER":       2,
+	}
+)
+
+func (x Containment_Mode) Enum() *Containment_Mode {
+	p := new(Containment_Mode)
+	*p = x
+	return p
+}
+
+func (x Containment_Mode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Containment_Mode) Descriptor() protoreflect.EnumDescriptor {
+	return file_sketch_proto_enumTypes[3].Descriptor()
+}
+
+func (Containment_Mode) Type() protoreflect.EnumType {
+	return &file_sketch_proto_enumTypes[3]
+}
+
+func (x Containment_Mode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Containment_Mode.Descriptor instead.
+func (Containment_Mode) EnumDescriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{6, 0}
+}
+
 // A one-dimensional position relative to a feature on another node.
 type RelativePosition1D struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -346,37 +526,30 @@ func (x *FlexRect) GetHeight() *geometrypb.Length {
 	return nil
 }
 
-type Node struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Unique node identifier.
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	// Rectangles in which content should be matched. Multiple may be specified.
-	SearchAreas []*FlexRect `protobuf:"bytes,100,rep,name=search_areas,json=searchAreas,proto3" json:"search_areas,omitempty"`
-	// Types that are valid to be assigned to Matcher:
-	//
-	//	*Node_BlockText
-	//	*Node_LineText
-	Matcher isNode_Matcher `protobuf_oneof:"matcher"`
-	// Tags are arbitrary non-empty, unique strings.
-	Tags          []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+type Selection struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Strategy SelectionStrategy      `protobuf:"varint,1,opt,name=strategy,proto3,enum=dossier.sketch.SelectionStrategy" json:"strategy,omitempty"`
+	// Reference point for the "CLOSEST" strategy. Distances are measured to the
+	// closest point of a candidate's bounds.
+	Anchor        *FlexRect_Vertex `protobuf:"bytes,2,opt,name=anchor,proto3" json:"anchor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Node) Reset() {
-	*x = Node{}
+func (x *Selection) Reset() {
+	*x = Selection{}
 	mi := &file_sketch_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Node) String() string {
+func (x *Selection) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Node) ProtoMessage() {}
+func (*Selection) ProtoMessage() {}
 
-func (x *Node) ProtoReflect() protoreflect.Message {
+func (x *Selection) ProtoReflect() protoreflect.Message {
 	mi := &file_sketch_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -388,108 +561,206 @@ func (x *Node) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Node.ProtoReflect.Descriptor instead.
-func (*Node) Descriptor() ([]byte, []int) {
+// Deprecated: Use Selection.ProtoReflect.Descriptor instead.
+func (*Selection) Descriptor() ([]byte, []int) {
 	return file_sketch_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Node) GetName() string {
+func (x *Selection) GetStrategy() SelectionStrategy {
 	if x != nil {
-		return x.Name
+		return x.Strategy
 	}
-	return ""
+	return SelectionStrategy_READING_ORDER
 }
 
-func (x *Node) GetSearchAreas() []*FlexRect {
+func (x *Selection) GetAnchor() *FlexRect_Vertex {
 	if x != nil {
-		return x.SearchAreas
+		return x.Anchor
 	}
 	return nil
 }
 
-func (x *Node) GetMatcher() isNode_Matcher {
+// Shorthand for a search area next to another node. The area starts at the
+// edge of the referenced node in the given direction and covers the extent of
+// the referenced node on the orthogonal axis.
+//
+// Examples:
+//
+//	Right of node "label" on the same line, up to the page edge:
+//	  { node: "label" direction: RIGHT }
+//
+//	Within three lines below node "label":
+//	  { node: "label" direction: DOWN lines: 3 }
+//
+// For the first element in a direction use "NeighborSearch".
+type AdjacentArea struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Referenced node identifier.
+	Node      string    `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Direction Direction `protobuf:"varint,2,opt,name=direction,proto3,enum=dossier.sketch.Direction" json:"direction,omitempty"`
+	// Size of the area in the search direction. The area extends to the page
+	// edge if neither is set.
+	//
+	// Types that are valid to be assigned to Extent:
+	//
+	//	*AdjacentArea_Distance
+	//	*AdjacentArea_Lines
+	Extent isAdjacentArea_Extent `protobuf_oneof:"extent"`
+	// Height of a text line when using "lines". Defaults to the height of the
+	// referenced node.
+	LineHeight *geometrypb.Length `protobuf:"bytes,5,opt,name=line_height,json=lineHeight,proto3" json:"line_height,omitempty"`
+	// Extend the area to the page edges on the orthogonal axis.
+	SpanPage bool `protobuf:"varint,6,opt,name=span_page,json=spanPage,proto3" json:"span_page,omitempty"`
+	// Grow the area by the given margin on both sides of the orthogonal axis.
+	Margin        *geometrypb.Length `protobuf:"bytes,7,opt,name=margin,proto3" json:"margin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjacentArea) Reset() {
+	*x = AdjacentArea{}
+	mi := &file_sketch_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjacentArea) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjacentArea) ProtoMessage() {}
+
+func (x *AdjacentArea) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[4]
 	if x != nil {
-		return x.Matcher
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjacentArea.ProtoReflect.Descriptor instead.
+func (*AdjacentArea) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AdjacentArea) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *AdjacentArea) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+func (x *AdjacentArea) GetExtent() isAdjacentArea_Extent {
+	if x != nil {
+		return x.Extent
 	}
 	return nil
 }
 
-func (x *Node) GetBlockText() *Node_TextMatch {
+func (x *AdjacentArea) GetDistance() *geometrypb.Length {
 	if x != nil {
-		if x, ok := x.Matcher.(*Node_BlockText); ok {
-			return x.BlockText
+		if x, ok := x.Extent.(*AdjacentArea_Distance); ok {
+			return x.Distance
 		}
 	}
 	return nil
 }
 
-func (x *Node) GetLineText() *Node_TextMatch {
+func (x *AdjacentArea) GetLines() uint32 {
 	if x != nil {
-		if x, ok := x.Matcher.(*Node_LineText); ok {
-			return x.LineText
+		if x, ok := x.Extent.(*AdjacentArea_Lines); ok {
+			return x.Lines
 		}
 	}
+	return 0
+}
+
+func (x *AdjacentArea) GetLineHeight() *geometrypb.Length {
+	if x != nil {
+		return x.LineHeight
+	}
 	return nil
 }
 
-func (x *Node) GetTags() []string {
+func (x *AdjacentArea) GetSpanPage() bool {
 	if x != nil {
-		return x.Tags
+		return x.SpanPage
+	}
+	return false
+}
+
+func (x *AdjacentArea) GetMargin() *geometrypb.Length {
+	if x != nil {
+		return x.Margin
 	}
 	return nil
 }
 
-type isNode_Matcher interface {
-	isNode_Matcher()
+type isAdjacentArea_Extent interface {
+	isAdjacentArea_Extent()
 }
 
-type Node_BlockText struct {
-	// Match over blocks of text. A block contains one or more lines.
-	BlockText *Node_TextMatch `protobuf:"bytes,10,opt,name=block_text,json=blockText,proto3,oneof"`
+type AdjacentArea_Distance struct {
+	// Fixed distance from the edge of the referenced node.
+	Distance *geometrypb.Length `protobuf:"bytes,3,opt,name=distance,proto3,oneof"`
 }
 
-type Node_LineText struct {
-	// Match over single lines of text.
-	LineText *Node_TextMatch `protobuf:"bytes,11,opt,name=line_text,json=lineText,proto3,oneof"`
+type AdjacentArea_Lines struct {
+	// Number of text lines. Only supported for vertical directions.
+	Lines uint32 `protobuf:"varint,4,opt,name=lines,proto3,oneof"`
 }
 
-func (*Node_BlockText) isNode_Matcher() {}
+func (*AdjacentArea_Distance) isAdjacentArea_Extent() {}
 
-func (*Node_LineText) isNode_Matcher() {}
+func (*AdjacentArea_Lines) isAdjacentArea_Extent() {}
 
-// A sketch is an abstract description of where information on a page is to be
-// found. Sketches have no concept of multiple pages. If code needs to make
-// a distinction between pages the following approaches may be useful:
-//
-//  1. Define nodes for all pages in a single sketch. Look up nodes in the
-//     analysis results depending on the current page number and ignore all
-//     other nodes.
-//
-// 2) Define one sketch per page type and apply them as necessary.
-type Sketch struct {
+// Transformations applied to text before matching. Reported offsets of
+// matches always refer to the original text.
+type Normalization struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	Nodes []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
-	// Tags are arbitrary non-empty, unique strings.
-	Tags          []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *Sketch) Reset() {
-	*x = Sketch{}
-	mi := &file_sketch_proto_msgTypes[4]
+	// Apply Unicode compatibility normalization (NFKC), e.g. replacing the "\ufb01"
+	// ligature with "fi" and non-breaking or thin spaces with regular spaces,
+	// and compose decomposed characters such as umlauts.
+	Nfkc bool `protobuf:"varint,1,opt,name=nfkc,proto3" json:"nfkc,omitempty"`
+	// Replace dash and minus characters (e.g. U+2010 to U+2015 and U+2212)
+	// with a hyphen-minus ("-").
+	UnifyDashes bool `protobuf:"varint,2,opt,name=unify_dashes,json=unifyDashes,proto3" json:"unify_dashes,omitempty"`
+	// Remove soft hyphens and join words hyphenated at the end of a line if the
+	// next line continues with a lowercase letter.
+	JoinHyphenation bool `protobuf:"varint,3,opt,name=join_hyphenation,json=joinHyphenation,proto3" json:"join_hyphenation,omitempty"`
+	// Replace runs of whitespace with a single space or, if the run contains
+	// a line break, a single line break.
+	CollapseWhitespace bool `protobuf:"varint,4,opt,name=collapse_whitespace,json=collapseWhitespace,proto3" json:"collapse_whitespace,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Normalization) Reset() {
+	*x = Normalization{}
+	mi := &file_sketch_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Sketch) String() string {
+func (x *Normalization) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Sketch) ProtoMessage() {}
+func (*Normalization) ProtoMessage() {}
 
-func (x *Sketch) ProtoReflect() protoreflect.Message {
-	mi := &file_sketch_proto_msgTypes[4]
+func (x *Normalization) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -500,51 +771,69 @@ func (x *Sketch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Sketch.ProtoReflect.Descriptor instead.
-func (*Sketch) Descriptor() ([]byte, []int) {
-	return file_sketch_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Normalization.ProtoReflect.Descriptor instead.
+func (*Normalization) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Sketch) GetNodes() []*Node {
+func (x *Normalization) GetNfkc() bool {
 	if x != nil {
-		return x.Nodes
+		return x.Nfkc
 	}
-	return nil
+	return false
 }
 
-func (x *Sketch) GetTags() []string {
+func (x *Normalization) GetUnifyDashes() bool {
 	if x != nil {
-		return x.Tags
+		return x.UnifyDashes
 	}
-	return nil
+	return false
 }
 
-type FlexRect_Vertex struct {
+func (x *Normalization) GetJoinHyphenation() bool {
+	if x != nil {
+		return x.JoinHyphenation
+	}
+	return false
+}
+
+func (x *Normalization) GetCollapseWhitespace() bool {
+	if x != nil {
+		return x.CollapseWhitespace
+	}
+	return false
+}
+
+// Policy deciding whether content is located within a search area.
+type Containment struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Method:
-	//
-	//	*FlexRect_Vertex_Abs
-	//	*FlexRect_Vertex_Rel
-	Method        isFlexRect_Vertex_Method `protobuf_oneof:"method"`
+	Mode  Containment_Mode       `protobuf:"varint,1,opt,name=mode,proto3,enum=dossier.sketch.Containment_Mode" json:"mode,omitempty"`
+	// Minimum fraction of the content's area located within the search area,
+	// in the range [0, 1]. Only supported with "INTERSECTING".
+	MinOverlap float64 `protobuf:"fixed64,2,opt,name=min_overlap,json=minOverlap,proto3" json:"min_overlap,omitempty"`
+	// Restrict the text of partially contained content to the characters
+	// whose center point is located within the search area. Content without
+	// such characters is skipped. Not supported with "CONTAINED".
+	ClipText      bool `protobuf:"varint,3,opt,name=clip_text,json=clipText,proto3" json:"clip_text,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FlexRect_Vertex) Reset() {
-	*x = FlexRect_Vertex{}
-	mi := &file_sketch_proto_msgTypes[5]
+func (x *Containment) Reset() {
+	*x = Containment{}
+	mi := &file_sketch_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FlexRect_Vertex) String() string {
+func (x *Containment) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FlexRect_Vertex) ProtoMessage() {}
+func (*Containment) ProtoMessage() {}
 
-func (x *FlexRect_Vertex) ProtoReflect() protoreflect.Message {
-	mi := &file_sketch_proto_msgTypes[5]
+func (x *Containment) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -555,78 +844,1239 @@ func (x *FlexRect_Vertex) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FlexRect_Vertex.ProtoReflect.Descriptor instead.
-func (*FlexRect_Vertex) Descriptor() ([]byte, []int) {
-	return file_sketch_proto_rawDescGZIP(), []int{2, 0}
+// Deprecated: Use Containment.ProtoReflect.Descriptor instead.
+func (*Containment) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *FlexRect_Vertex) GetMethod() isFlexRect_Vertex_Method {
+func (x *Containment) GetMode() Containment_Mode {
 	if x != nil {
-		return x.Method
+		return x.Mode
 	}
-	return nil
+	return Containment_CONTAINED
 }
 
-func (x *FlexRect_Vertex) GetAbs() *geometrypb.Point {
+func (x *Containment) GetMinOverlap() float64 {
 	if x != nil {
-		if x, ok := x.Method.(*FlexRect_Vertex_Abs); ok {
-			return x.Abs
-		}
+		return x.MinOverlap
 	}
-	return nil
+	return 0
 }
 
-func (x *FlexRect_Vertex) GetRel() *RelativePosition2D {
+func (x *Containment) GetClipText() bool {
 	if x != nil {
-		if x, ok := x.Method.(*FlexRect_Vertex_Rel); ok {
-			return x.Rel
-		}
+		return x.ClipText
 	}
-	return nil
+	return false
 }
 
-type isFlexRect_Vertex_Method interface {
-	isFlexRect_Vertex_Method()
+// Search for content next to another node without an explicit search area.
+// Candidates must be located beyond the edge of the referenced node in the
+// given direction and overlap with it on the orthogonal axis. They are
+// evaluated in order of increasing distance and the first match is used.
+type NeighborSearch struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Referenced node identifier.
+	Node      string    `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Direction Direction `protobuf:"varint,2,opt,name=direction,proto3,enum=dossier.sketch.Direction" json:"direction,omitempty"`
+	// Maximum distance between the referenced node and the content. Unlimited
+	// if not set.
+	MaxDistance *geometrypb.Length `protobuf:"bytes,3,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"`
+	// Minimum overlap on the axis orthogonal to the search direction relative
+	// to the smaller of the two extents, in the range [0, 1]. Candidates must
+	// always overlap at least partially.
+	MinOverlap    float64 `protobuf:"fixed64,4,opt,name=min_overlap,json=minOverlap,proto3" json:"min_overlap,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NeighborSearch) Reset() {
+	*x = NeighborSearch{}
+	mi := &file_sketch_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NeighborSearch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NeighborSearch) ProtoMessage() {}
+
+func (x *NeighborSearch) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NeighborSearch.ProtoReflect.Descriptor instead.
+func (*NeighborSearch) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NeighborSearch) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *NeighborSearch) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+func (x *NeighborSearch) GetMaxDistance() *geometrypb.Length {
+	if x != nil {
+		return x.MaxDistance
+	}
+	return nil
+}
+
+func (x *NeighborSearch) GetMinOverlap() float64 {
+	if x != nil {
+		return x.MinOverlap
+	}
+	return 0
+}
+
+type Node struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique node identifier.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Name of a template to base the node on, e.g. "footer.iban" for the
+	// template "iban" imported with the namespace "footer". Fields set on the
+	// node take precedence over those of the template. Message fields are
+	// merged recursively while repeated fields replace the template's values.
+	// Fields can't be reset to their default value.
+	Template string `protobuf:"bytes,2,opt,name=template,proto3" json:"template,omitempty"`
+	// Rectangles in which content should be matched. Multiple may be specified.
+	SearchAreas []*FlexRect `protobuf:"bytes,100,rep,name=search_areas,json=searchAreas,proto3" json:"search_areas,omitempty"`
+	// Search areas next to other nodes. Searched after "search_areas".
+	AdjacentAreas []*AdjacentArea `protobuf:"bytes,102,rep,name=adjacent_areas,json=adjacentAreas,proto3" json:"adjacent_areas,omitempty"`
+	// Search next to another node. Mutually exclusive with "search_areas" and
+	// "adjacent_areas".
+	Neighbor *NeighborSearch `protobuf:"bytes,101,opt,name=neighbor,proto3" json:"neighbor,omitempty"`
+	// How to choose among multiple matching candidates.
+	Selection *Selection `protobuf:"bytes,103,opt,name=selection,proto3" json:"selection,omitempty"`
+	// How content is matched against search areas. Defaults to content fully
+	// contained within an area. Not used by neighbor searches.
+	Containment *Containment `protobuf:"bytes,104,opt,name=containment,proto3" json:"containment,omitempty"`
+	// Areas in which content is ignored. Content whose center point is located
+	// within any of the areas is neither used as a candidate nor considered by
+	// "must_not_match" conditions.
+	Exclusions []*FlexRect `protobuf:"bytes,105,rep,name=exclusions,proto3" json:"exclusions,omitempty"`
+	// Candidates are rejected if any of the conditions matches.
+	MustNotMatch []*Node_NegativeMatch `protobuf:"bytes,106,rep,name=must_not_match,json=mustNotMatch,proto3" json:"must_not_match,omitempty"`
+	// Text normalization before matching. Overrides the sketch-wide setting.
+	Normalization *Normalization `protobuf:"bytes,107,opt,name=normalization,proto3" json:"normalization,omitempty"`
+	// Types that are valid to be assigned to Matcher:
+	//
+	//	*Node_BlockText
+	//	*Node_LineText
+	//	*Node_MultiLine
+	Matcher isNode_Matcher `protobuf_oneof:"matcher"`
+	// Nodes consisting only of alternatives don't need a matcher of their own.
+	Alternatives []*Node_Alternative `protobuf:"bytes,108,rep,name=alternatives,proto3" json:"alternatives,omitempty"`
+	// Tags are arbitrary non-empty, unique strings.
+	Tags          []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	mi := &file_sketch_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Node) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Node) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *Node) GetSearchAreas() []*FlexRect {
+	if x != nil {
+		return x.SearchAreas
+	}
+	return nil
+}
+
+func (x *Node) GetAdjacentAreas() []*AdjacentArea {
+	if x != nil {
+		return x.AdjacentAreas
+	}
+	return nil
+}
+
+func (x *Node) GetNeighbor() *NeighborSearch {
+	if x != nil {
+		return x.Neighbor
+	}
+	return nil
+}
+
+func (x *Node) GetSelection() *Selection {
+	if x != nil {
+		return x.Selection
+	}
+	return nil
+}
+
+func (x *Node) GetContainment() *Containment {
+	if x != nil {
+		return x.Containment
+	}
+	return nil
+}
+
+func (x *Node) GetExclusions() []*FlexRect {
+	if x != nil {
+		return x.Exclusions
+	}
+	return nil
+}
+
+func (x *Node) GetMustNotMatch() []*Node_NegativeMatch {
+	if x != nil {
+		return x.MustNotMatch
+	}
+	return nil
+}
+
+func (x *Node) GetNormalization() *Normalization {
+	if x != nil {
+		return x.Normalization
+	}
+	return nil
+}
+
+func (x *Node) GetMatcher() isNode_Matcher {
+	if x != nil {
+		return x.Matcher
+	}
+	return nil
+}
+
+func (x *Node) GetBlockText() *Node_TextMatch {
+	if x != nil {
+		if x, ok := x.Matcher.(*Node_BlockText); ok {
+			return x.BlockText
+		}
+	}
+	return nil
+}
+
+func (x *Node) GetLineText() *Node_TextMatch {
+	if x != nil {
+		if x, ok := x.Matcher.(*Node_LineText); ok {
+			return x.LineText
+		}
+	}
+	return nil
+}
+
+func (x *Node) GetMultiLine() *Node_MultiLineMatch {
+	if x != nil {
+		if x, ok := x.Matcher.(*Node_MultiLine); ok {
+			return x.MultiLine
+		}
+	}
+	return nil
+}
+
+func (x *Node) GetAlternatives() []*Node_Alternative {
+	if x != nil {
+		return x.Alternatives
+	}
+	return nil
+}
+
+func (x *Node) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type isNode_Matcher interface {
+	isNode_Matcher()
+}
+
+type Node_BlockText struct {
+	// Match over blocks of text. A block contains one or more lines.
+	BlockText *Node_TextMatch `protobuf:"bytes,10,opt,name=block_text,json=blockText,proto3,oneof"`
+}
+
+type Node_LineText struct {
+	// Match over single lines of text.
+	LineText *Node_TextMatch `protobuf:"bytes,11,opt,name=line_text,json=lineText,proto3,oneof"`
+}
+
+type Node_MultiLine struct {
+	// Match over consecutive lines.
+	MultiLine *Node_MultiLineMatch `protobuf:"bytes,12,opt,name=multi_line,json=multiLine,proto3,oneof"`
+}
+
+func (*Node_BlockText) isNode_Matcher() {}
+
+func (*Node_LineText) isNode_Matcher() {}
+
+func (*Node_MultiLine) isNode_Matcher() {}
+
+// Value derived from other nodes using an expression. Computed nodes are
+// evaluated after all other nodes of a page have been searched.
+//
+// Example verifying invoice totals:
+//
+//	computed {
+//	  name: "total_check"
+//	  expr: "net.amount + vat.amount == gross.amount"
+//	}
+//
+// References are resolved as follows:
+//
+//   - "<node>": Text of a node or the value of an earlier computed node.
+//   - "<node>.<group>": Named capture group of a node's text match.
+//
+// Nodes referenced in expressions must have names consisting of letters,
+// digits and underscores, optionally separated by dots.
+//
+// Texts are converted to numbers where necessary, e.g. "1'234.50". Commas are
+// only accepted as thousands separators, e.g. "1,234.50". Use number() for
+// other formats such as "1.234,50". Built-in functions:
+//
+//   - number(value[, decimal_separator]): Conversion to number with "." or ","
+//     as the decimal separator.
+//   - date(value, layout): Parse a date using a Go time layout, e.g.
+//     "02.01.2006". Dates can be compared and days added or subtracted. The
+//     difference between two dates is the number of days.
+//   - sum(values...), min(values...), max(values...), abs(value),
+//     round(value, digits): Arithmetic.
+//   - len(text), matches(text, regex): Text inspection.
+//   - has(reference): Whether a reference is available, i.e. the node has
+//     been found.
+//
+// Evaluation is skipped if a referenced node wasn't found and has() isn't
+// used. Expressions producing a bool value are checks which pass if the value
+// is true. All other expressions pass if they can be evaluated.
+type ComputedNode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique identifier shared with other nodes.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Expression to evaluate. Required.
+	Expr string `protobuf:"bytes,2,opt,name=expr,proto3" json:"expr,omitempty"`
+	// Tags are arbitrary non-empty, unique strings.
+	Tags          []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputedNode) Reset() {
+	*x = ComputedNode{}
+	mi := &file_sketch_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputedNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputedNode) ProtoMessage() {}
+
+func (x *ComputedNode) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputedNode.ProtoReflect.Descriptor instead.
+func (*ComputedNode) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ComputedNode) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ComputedNode) GetExpr() string {
+	if x != nil {
+		return x.Expr
+	}
+	return ""
+}
+
+func (x *ComputedNode) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// A sketch is an abstract description of where information on a page is to be
+// found. Sketches have no concept of multiple pages. If code needs to make
+// a distinction between pages the following approaches may be useful:
+//
+//  1. Define nodes for all pages in a single sketch. Look up nodes in the
+//     analysis results depending on the current page number and ignore all
+//     other nodes.
+//
+// 2) Define one sketch per page type and apply them as necessary.
+type Sketch struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Nodes []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	// Imports require a loader, e.g. as used by "CompileFile".
+	Imports []*Sketch_Import `protobuf:"bytes,3,rep,name=imports,proto3" json:"imports,omitempty"`
+	// Reusable node definitions referenced via "Node.template". Templates are
+	// not searched by themselves. Node references within templates are
+	// resolved where the template is used.
+	Templates []*Node `protobuf:"bytes,4,rep,name=templates,proto3" json:"templates,omitempty"`
+	// Text normalization before matching applied to all nodes without their
+	// own setting.
+	Normalization *Normalization `protobuf:"bytes,2,opt,name=normalization,proto3" json:"normalization,omitempty"`
+	// Parameters of imported sketches are overridden using their namespaced
+	// name, e.g. "footer.customer".
+	Params []*Sketch_Param `protobuf:"bytes,5,rep,name=params,proto3" json:"params,omitempty"`
+	// Values derived from other nodes. Evaluated in order after all other
+	// nodes.
+	Computed []*ComputedNode `protobuf:"bytes,6,rep,name=computed,proto3" json:"computed,omitempty"`
+	// Tags are arbitrary non-empty, unique strings.
+	Tags          []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sketch) Reset() {
+	*x = Sketch{}
+	mi := &file_sketch_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sketch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sketch) ProtoMessage() {}
+
+func (x *Sketch) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sketch.ProtoReflect.Descriptor instead.
+func (*Sketch) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Sketch) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *Sketch) GetImports() []*Sketch_Import {
+	if x != nil {
+		return x.Imports
+	}
+	return nil
+}
+
+func (x *Sketch) GetTemplates() []*Node {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+func (x *Sketch) GetNormalization() *Normalization {
+	if x != nil {
+		return x.Normalization
+	}
+	return nil
+}
+
+func (x *Sketch) GetParams() []*Sketch_Param {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *Sketch) GetComputed() []*ComputedNode {
+	if x != nil {
+		return x.Computed
+	}
+	return nil
+}
+
+func (x *Sketch) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type FlexRect_Vertex struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Method:
+	//
+	//	*FlexRect_Vertex_Abs
+	//	*FlexRect_Vertex_Rel
+	Method        isFlexRect_Vertex_Method `protobuf_oneof:"method"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlexRect_Vertex) Reset() {
+	*x = FlexRect_Vertex{}
+	mi := &file_sketch_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlexRect_Vertex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlexRect_Vertex) ProtoMessage() {}
+
+func (x *FlexRect_Vertex) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlexRect_Vertex.ProtoReflect.Descriptor instead.
+func (*FlexRect_Vertex) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *FlexRect_Vertex) GetMethod() isFlexRect_Vertex_Method {
+	if x != nil {
+		return x.Method
+	}
+	return nil
+}
+
+func (x *FlexRect_Vertex) GetAbs() *geometrypb.Point {
+	if x != nil {
+		if x, ok := x.Method.(*FlexRect_Vertex_Abs); ok {
+			return x.Abs
+		}
+	}
+	return nil
+}
+
+func (x *FlexRect_Vertex) GetRel() *RelativePosition2D {
+	if x != nil {
+		if x, ok := x.Method.(*FlexRect_Vertex_Rel); ok {
+			return x.Rel
+		}
+	}
+	return nil
+}
+
+type isFlexRect_Vertex_Method interface {
+	isFlexRect_Vertex_Method()
+}
+
+type FlexRect_Vertex_Abs struct {
+	Abs *geometrypb.Point `protobuf:"bytes,1,opt,name=abs,proto3,oneof"`
+}
+
+type FlexRect_Vertex_Rel struct {
+	Rel *RelativePosition2D `protobuf:"bytes,2,opt,name=rel,proto3,oneof"`
+}
+
+func (*FlexRect_Vertex_Abs) isFlexRect_Vertex_Method() {}
+
+func (*FlexRect_Vertex_Rel) isFlexRect_Vertex_Method() {}
+
+type FlexRect_Edge struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Method:
+	//
+	//	*FlexRect_Edge_Abs
+	//	*FlexRect_Edge_Rel
+	Method        isFlexRect_Edge_Method `protobuf_oneof:"method"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlexRect_Edge) Reset() {
+	*x = FlexRect_Edge{}
+	mi := &file_sketch_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlexRect_Edge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlexRect_Edge) ProtoMessage() {}
+
+func (x *FlexRect_Edge) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlexRect_Edge.ProtoReflect.Descriptor instead.
+func (*FlexRect_Edge) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{2, 1}
+}
+
+func (x *FlexRect_Edge) GetMethod() isFlexRect_Edge_Method {
+	if x != nil {
+		return x.Method
+	}
+	return nil
+}
+
+func (x *FlexRect_Edge) GetAbs() *geometrypb.Length {
+	if x != nil {
+		if x, ok := x.Method.(*FlexRect_Edge_Abs); ok {
+			return x.Abs
+		}
+	}
+	return nil
+}
+
+func (x *FlexRect_Edge) GetRel() *RelativePosition1D {
+	if x != nil {
+		if x, ok := x.Method.(*FlexRect_Edge_Rel); ok {
+			return x.Rel
+		}
+	}
+	return nil
+}
+
+type isFlexRect_Edge_Method interface {
+	isFlexRect_Edge_Method()
+}
+
+type FlexRect_Edge_Abs struct {
+	Abs *geometrypb.Length `protobuf:"bytes,1,opt,name=abs,proto3,oneof"`
+}
+
+type FlexRect_Edge_Rel struct {
+	Rel *RelativePosition1D `protobuf:"bytes,2,opt,name=rel,proto3,oneof"`
+}
+
+func (*FlexRect_Edge_Abs) isFlexRect_Edge_Method() {}
+
+func (*FlexRect_Edge_Rel) isFlexRect_Edge_Method() {}
+
+type Node_TextMatch struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Regular expression to look for. If a source document has been processed
+	// via OCR the expression may have to be written in a more flexible form to
+	// accept lookalike-characters (e.g. German umlauts may be recognized as A,
+	// O and U without diacritic). Alternatively "fuzzy" can be used.
+	//
+	// When combined with "fuzzy" the expression is matched against the text
+	// following the fuzzy match. Anchor it using "^" to require the remainder
+	// to start immediately.
+	//
+	// Example: "(?i)^\\s*Destination\\s+address\\s*:"
+	//
+	// Syntax: https://pkg.go.dev/regexp/syntax
+	Regex string `protobuf:"bytes,1,opt,name=regex,proto3" json:"regex,omitempty"`
+	// By default the reported bounds of valid matches are those of the block
+	// or line containing the matched text. By setting "bounds_from_match" the
+	// exact bounds of the matched text are used instead.
+	BoundsFromMatch bool `protobuf:"varint,2,opt,name=bounds_from_match,json=boundsFromMatch,proto3" json:"bounds_from_match,omitempty"`
+	// Approximate match of literal text tolerating recognition errors.
+	Fuzzy         *Node_Fuzzy `protobuf:"bytes,3,opt,name=fuzzy,proto3" json:"fuzzy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node_TextMatch) Reset() {
+	*x = Node_TextMatch{}
+	mi := &file_sketch_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node_TextMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node_TextMatch) ProtoMessage() {}
+
+func (x *Node_TextMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node_TextMatch.ProtoReflect.Descriptor instead.
+func (*Node_TextMatch) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *Node_TextMatch) GetRegex() string {
+	if x != nil {
+		return x.Regex
+	}
+	return ""
+}
+
+func (x *Node_TextMatch) GetBoundsFromMatch() bool {
+	if x != nil {
+		return x.BoundsFromMatch
+	}
+	return false
+}
+
+func (x *Node_TextMatch) GetFuzzy() *Node_Fuzzy {
+	if x != nil {
+		return x.Fuzzy
+	}
+	return nil
+}
+
+// Set of character sequences considered equal by fuzzy matching, e.g. "rn"
+// and "m".
+type Node_Confusable struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variants      []string               `protobuf:"bytes,1,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node_Confusable) Reset() {
+	*x = Node_Confusable{}
+	mi := &file_sketch_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node_Confusable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node_Confusable) ProtoMessage() {}
+
+func (x *Node_Confusable) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node_Confusable.ProtoReflect.Descriptor instead.
+func (*Node_Confusable) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{8, 1}
+}
+
+func (x *Node_Confusable) GetVariants() []string {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+// Approximate match of literal text. The text is searched anywhere within
+// the content and the closest occurrence is used.
+//
+// Example for "Invoice number", also matching "lnvoice nurnber":
+//
+//	fuzzy {
+//	  text: "Invoice number"
+//	  max_distance: 2
+//	  ignore_case: true
+//	  default_confusables: true
+//	}
+type Node_Fuzzy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Literal text to look for. Required.
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Maximum number of inserted, deleted or substituted characters.
+	MaxDistance uint32 `protobuf:"varint,2,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"`
+	// Compare letters without regard to case.
+	IgnoreCase bool `protobuf:"varint,3,opt,name=ignore_case,json=ignoreCase,proto3" json:"ignore_case,omitempty"`
+	// Compare letters without diacritics, e.g. "ä" and "a" are equal.
+	IgnoreDiacritics bool `protobuf:"varint,4,opt,name=ignore_diacritics,json=ignoreDiacritics,proto3" json:"ignore_diacritics,omitempty"`
+	// Treat runs of whitespace as a single space.
+	FoldWhitespace bool `protobuf:"varint,5,opt,name=fold_whitespace,json=foldWhitespace,proto3" json:"fold_whitespace,omitempty"`
+	// Character sequences considered equal.
+	Confusables []*Node_Confusable `protobuf:"bytes,6,rep,name=confusables,proto3" json:"confusables,omitempty"`
+	// Include a built-in table of common OCR confusions, e.g. "0" and "O",
+	// "1", "l" and "I" as well as "rn" and "m".
+	DefaultConfusables bool `protobuf:"varint,7,opt,name=default_confusables,json=defaultConfusables,proto3" json:"default_confusables,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Node_Fuzzy) Reset() {
+	*x = Node_Fuzzy{}
+	mi := &file_sketch_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node_Fuzzy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node_Fuzzy) ProtoMessage() {}
+
+func (x *Node_Fuzzy) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node_Fuzzy.ProtoReflect.Descriptor instead.
+func (*Node_Fuzzy) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{8, 2}
+}
+
+func (x *Node_Fuzzy) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Node_Fuzzy) GetMaxDistance() uint32 {
+	if x != nil {
+		return x.MaxDistance
+	}
+	return 0
+}
+
+func (x *Node_Fuzzy) GetIgnoreCase() bool {
+	if x != nil {
+		return x.IgnoreCase
+	}
+	return false
+}
+
+func (x *Node_Fuzzy) GetIgnoreDiacritics() bool {
+	if x != nil {
+		return x.IgnoreDiacritics
+	}
+	return false
+}
+
+func (x *Node_Fuzzy) GetFoldWhitespace() bool {
+	if x != nil {
+		return x.FoldWhitespace
+	}
+	return false
+}
+
+func (x *Node_Fuzzy) GetConfusables() []*Node_Confusable {
+	if x != nil {
+		return x.Confusables
+	}
+	return nil
+}
+
+func (x *Node_Fuzzy) GetDefaultConfusables() bool {
+	if x != nil {
+		return x.DefaultConfusables
+	}
+	return false
+}
+
+// Condition rejecting a candidate if text matching the expression is found
+// in a related area.
+//
+// Example for "Total" without "Subtotal" on the line above:
+//
+//	must_not_match {
+//	  adjacent_area { direction: UP lines: 1 }
+//	  line_text { regex: "Subtotal" }
+//	}
+type Node_NegativeMatch struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Area:
+	//
+	//	*Node_NegativeMatch_SearchArea
+	//	*Node_NegativeMatch_AdjacentArea
+	Area isNode_NegativeMatch_Area `protobuf_oneof:"area"`
+	// Content is matched against the area using the node's containment
+	// policy.
+	//
+	// Types that are valid to be assigned to Matcher:
+	//
+	//	*Node_NegativeMatch_BlockText
+	//	*Node_NegativeMatch_LineText
+	Matcher       isNode_NegativeMatch_Matcher `protobuf_oneof:"matcher"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node_NegativeMatch) Reset() {
+	*x = Node_NegativeMatch{}
+	mi := &file_sketch_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node_NegativeMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node_NegativeMatch) ProtoMessage() {}
+
+func (x *Node_NegativeMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node_NegativeMatch.ProtoReflect.Descriptor instead.
+func (*Node_NegativeMatch) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{8, 3}
+}
+
+func (x *Node_NegativeMatch) GetArea() isNode_NegativeMatch_Area {
+	if x != nil {
+		return x.Area
+	}
+	return nil
+}
+
+func (x *Node_NegativeMatch) GetSearchArea() *FlexRect {
+	if x != nil {
+		if x, ok := x.Area.(*Node_NegativeMatch_SearchArea); ok {
+			return x.SearchArea
+		}
+	}
+	return nil
+}
+
+func (x *Node_NegativeMatch) GetAdjacentArea() *AdjacentArea {
+	if x != nil {
+		if x, ok := x.Area.(*Node_NegativeMatch_AdjacentArea); ok {
+			return x.AdjacentArea
+		}
+	}
+	return nil
+}
+
+func (x *Node_NegativeMatch) GetMatcher() isNode_NegativeMatch_Matcher {
+	if x != nil {
+		return x.Matcher
+	}
+	return nil
+}
+
+func (x *Node_NegativeMatch) GetBlockText() *Node_TextMatch {
+	if x != nil {
+		if x, ok := x.Matcher.(*Node_NegativeMatch_BlockText); ok {
+			return x.BlockText
+		}
+	}
+	return nil
+}
+
+func (x *Node_NegativeMatch) GetLineText() *Node_TextMatch {
+	if x != nil {
+		if x, ok := x.Matcher.(*Node_NegativeMatch_LineText); ok {
+			return x.LineText
+		}
+	}
+	return nil
 }
 
-type FlexRect_Vertex_Abs struct {
-	Abs *geometrypb.Point `protobuf:"bytes,1,opt,name=abs,proto3,oneof"`
+type isNode_NegativeMatch_Area interface {
+	isNode_NegativeMatch_Area()
 }
 
-type FlexRect_Vertex_Rel struct {
-	Rel *RelativePosition2D `protobuf:"bytes,2,opt,name=rel,proto3,oneof"`
+type Node_NegativeMatch_SearchArea struct {
+	// Fixed area, optionally relative to other nodes.
+	SearchArea *FlexRect `protobuf:"bytes,1,opt,name=search_area,json=searchArea,proto3,oneof"`
 }
 
-func (*FlexRect_Vertex_Abs) isFlexRect_Vertex_Method() {}
+type Node_NegativeMatch_AdjacentArea struct {
+	// Area next to the candidate. The "node" field must not be set.
+	AdjacentArea *AdjacentArea `protobuf:"bytes,2,opt,name=adjacent_area,json=adjacentArea,proto3,oneof"`
+}
 
-func (*FlexRect_Vertex_Rel) isFlexRect_Vertex_Method() {}
+func (*Node_NegativeMatch_SearchArea) isNode_NegativeMatch_Area() {}
 
-type FlexRect_Edge struct {
+func (*Node_NegativeMatch_AdjacentArea) isNode_NegativeMatch_Area() {}
+
+type isNode_NegativeMatch_Matcher interface {
+	isNode_NegativeMatch_Matcher()
+}
+
+type Node_NegativeMatch_BlockText struct {
+	BlockText *Node_TextMatch `protobuf:"bytes,10,opt,name=block_text,json=blockText,proto3,oneof"`
+}
+
+type Node_NegativeMatch_LineText struct {
+	LineText *Node_TextMatch `protobuf:"bytes,11,opt,name=line_text,json=lineText,proto3,oneof"`
+}
+
+func (*Node_NegativeMatch_BlockText) isNode_NegativeMatch_Matcher() {}
+
+func (*Node_NegativeMatch_LineText) isNode_NegativeMatch_Matcher() {}
+
+// Text spanning consecutive lines starting at an anchor line. Lines are
+// collected from top to bottom while they're aligned with the anchor on the
+// left edge, the gap to the previous line is small enough and the stop
+// expression hasn't matched.
+//
+// Example for an address below a label:
+//
+//	multi_line {
+//	  anchor { regex: "^Ship to:" }
+//	  stop_regex: "^\\s*$"
+//	  max_lines: 5
+//	}
+type Node_MultiLineMatch struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Method:
+	// Line at which collection starts. Required.
+	Anchor *Node_TextMatch `protobuf:"bytes,1,opt,name=anchor,proto3" json:"anchor,omitempty"`
+	// Match over the merged text of all collected lines. Lines are separated
+	// using newline characters ("\n"). Accepts any text when not set.
+	Text *Node_TextMatch `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	// Include the anchor line in the collected lines.
+	IncludeAnchor bool `protobuf:"varint,3,opt,name=include_anchor,json=includeAnchor,proto3" json:"include_anchor,omitempty"`
+	// Maximum horizontal distance between the left edge of the anchor and
+	// the left edge of a collected line. Defaults to 2pt.
+	LeftTolerance *geometrypb.Length `protobuf:"bytes,4,opt,name=left_tolerance,json=leftTolerance,proto3" json:"left_tolerance,omitempty"`
+	// Maximum vertical gap between consecutive lines. Defaults to half the
+	// height of the previous line.
+	MaxLineGap *geometrypb.Length `protobuf:"bytes,5,opt,name=max_line_gap,json=maxLineGap,proto3" json:"max_line_gap,omitempty"`
+	// Collection stops before a line matching the expression.
+	StopRegex string `protobuf:"bytes,6,opt,name=stop_regex,json=stopRegex,proto3" json:"stop_regex,omitempty"`
+	// Maximum number of collected lines, including the anchor if
+	// "include_anchor" is set. Unlimited if zero.
+	MaxLines      uint32 `protobuf:"varint,7,opt,name=max_lines,json=maxLines,proto3" json:"max_lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node_MultiLineMatch) Reset() {
+	*x = Node_MultiLineMatch{}
+	mi := &file_sketch_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node_MultiLineMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node_MultiLineMatch) ProtoMessage() {}
+
+func (x *Node_MultiLineMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node_MultiLineMatch.ProtoReflect.Descriptor instead.
+func (*Node_MultiLineMatch) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{8, 4}
+}
+
+func (x *Node_MultiLineMatch) GetAnchor() *Node_TextMatch {
+	if x != nil {
+		return x.Anchor
+	}
+	return nil
+}
+
+func (x *Node_MultiLineMatch) GetText() *Node_TextMatch {
+	if x != nil {
+		return x.Text
+	}
+	return nil
+}
+
+func (x *Node_MultiLineMatch) GetIncludeAnchor() bool {
+	if x != nil {
+		return x.IncludeAnchor
+	}
+	return false
+}
+
+func (x *Node_MultiLineMatch) GetLeftTolerance() *geometrypb.Length {
+	if x != nil {
+		return x.LeftTolerance
+	}
+	return nil
+}
+
+func (x *Node_MultiLineMatch) GetMaxLineGap() *geometrypb.Length {
+	if x != nil {
+		return x.MaxLineGap
+	}
+	return nil
+}
+
+func (x *Node_MultiLineMatch) GetStopRegex() string {
+	if x != nil {
+		return x.StopRegex
+	}
+	return ""
+}
+
+func (x *Node_MultiLineMatch) GetMaxLines() uint32 {
+	if x != nil {
+		return x.MaxLines
+	}
+	return 0
+}
+
+// Fallback pairing search areas with a matcher. Alternatives are tried in
+// order once the node's own search areas, adjacent areas or neighbor search
+// didn't produce any candidates. All other node settings, e.g. selection
+// and exclusions, apply to alternatives as well.
+//
+// Example for a German layout variant:
+//
+//	alternatives {
+//	  name: "german"
+//	  search_areas { ... }
+//	  line_text { regex: "^Rechnungsnummer:" }
+//	}
+type Node_Alternative struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identifier reported when candidates of the alternative were used.
+	// Defaults to the 1-based position within the list of alternatives. Must
+	// be unique within a node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Areas searched using the alternative's matcher. At least one search or
+	// adjacent area is required.
+	SearchAreas   []*FlexRect     `protobuf:"bytes,2,rep,name=search_areas,json=searchAreas,proto3" json:"search_areas,omitempty"`
+	AdjacentAreas []*AdjacentArea `protobuf:"bytes,3,rep,name=adjacent_areas,json=adjacentAreas,proto3" json:"adjacent_areas,omitempty"`
+	// Types that are valid to be assigned to Matcher:
 	//
-	//	*FlexRect_Edge_Abs
-	//	*FlexRect_Edge_Rel
-	Method        isFlexRect_Edge_Method `protobuf_oneof:"method"`
+	//	*Node_Alternative_BlockText
+	//	*Node_Alternative_LineText
+	//	*Node_Alternative_MultiLine
+	Matcher       isNode_Alternative_Matcher `protobuf_oneof:"matcher"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FlexRect_Edge) Reset() {
-	*x = FlexRect_Edge{}
-	mi := &file_sketch_proto_msgTypes[6]
+func (x *Node_Alternative) Reset() {
+	*x = Node_Alternative{}
+	mi := &file_sketch_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FlexRect_Edge) String() string {
+func (x *Node_Alternative) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FlexRect_Edge) ProtoMessage() {}
+func (*Node_Alternative) ProtoMessage() {}
 
-func (x *FlexRect_Edge) ProtoReflect() protoreflect.Message {
-	mi := &file_sketch_proto_msgTypes[6]
+func (x *Node_Alternative) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -637,86 +2087,186 @@ func (x *FlexRect_Edge) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FlexRect_Edge.ProtoReflect.Descriptor instead.
-func (*FlexRect_Edge) Descriptor() ([]byte, []int) {
-	return file_sketch_proto_rawDescGZIP(), []int{2, 1}
+// Deprecated: Use Node_Alternative.ProtoReflect.Descriptor instead.
+func (*Node_Alternative) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{8, 5}
 }
 
-func (x *FlexRect_Edge) GetMethod() isFlexRect_Edge_Method {
+func (x *Node_Alternative) GetName() string {
 	if x != nil {
-		return x.Method
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Node_Alternative) GetSearchAreas() []*FlexRect {
+	if x != nil {
+		return x.SearchAreas
 	}
 	return nil
 }
 
-func (x *FlexRect_Edge) GetAbs() *geometrypb.Length {
+func (x *Node_Alternative) GetAdjacentAreas() []*AdjacentArea {
 	if x != nil {
-		if x, ok := x.Method.(*FlexRect_Edge_Abs); ok {
-			return x.Abs
+		return x.AdjacentAreas
+	}
+	return nil
+}
+
+func (x *Node_Alternative) GetMatcher() isNode_Alternative_Matcher {
+	if x != nil {
+		return x.Matcher
+	}
+	return nil
+}
+
+func (x *Node_Alternative) GetBlockText() *Node_TextMatch {
+	if x != nil {
+		if x, ok := x.Matcher.(*Node_Alternative_BlockText); ok {
+			return x.BlockText
 		}
 	}
 	return nil
 }
 
-func (x *FlexRect_Edge) GetRel() *RelativePosition1D {
+func (x *Node_Alternative) GetLineText() *Node_TextMatch {
 	if x != nil {
-		if x, ok := x.Method.(*FlexRect_Edge_Rel); ok {
-			return x.Rel
+		if x, ok := x.Matcher.(*Node_Alternative_LineText); ok {
+			return x.LineText
 		}
 	}
 	return nil
 }
 
-type isFlexRect_Edge_Method interface {
-	isFlexRect_Edge_Method()
+func (x *Node_Alternative) GetMultiLine() *Node_MultiLineMatch {
+	if x != nil {
+		if x, ok := x.Matcher.(*Node_Alternative_MultiLine); ok {
+			return x.MultiLine
+		}
+	}
+	return nil
 }
 
-type FlexRect_Edge_Abs struct {
-	Abs *geometrypb.Length `protobuf:"bytes,1,opt,name=abs,proto3,oneof"`
+type isNode_Alternative_Matcher interface {
+	isNode_Alternative_Matcher()
 }
 
-type FlexRect_Edge_Rel struct {
-	Rel *RelativePosition1D `protobuf:"bytes,2,opt,name=rel,proto3,oneof"`
+type Node_Alternative_BlockText struct {
+	BlockText *Node_TextMatch `protobuf:"bytes,10,opt,name=block_text,json=blockText,proto3,oneof"`
 }
 
-func (*FlexRect_Edge_Abs) isFlexRect_Edge_Method() {}
+type Node_Alternative_LineText struct {
+	LineText *Node_TextMatch `protobuf:"bytes,11,opt,name=line_text,json=lineText,proto3,oneof"`
+}
 
-func (*FlexRect_Edge_Rel) isFlexRect_Edge_Method() {}
+type Node_Alternative_MultiLine struct {
+	MultiLine *Node_MultiLineMatch `protobuf:"bytes,12,opt,name=multi_line,json=multiLine,proto3,oneof"`
+}
 
-type Node_TextMatch struct {
+func (*Node_Alternative_BlockText) isNode_Alternative_Matcher() {}
+
+func (*Node_Alternative_LineText) isNode_Alternative_Matcher() {}
+
+func (*Node_Alternative_MultiLine) isNode_Alternative_Matcher() {}
+
+// Another sketch file whose nodes and templates are included.
+type Sketch_Import struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Regular expression to look for. If a source document has been processed
-	// via OCR the expression may have to be written in a more flexible form to
-	// accept lookalike-characters (e.g. German umlauts may be recognized as A,
-	// O and U without diacritic).
-	//
-	// Example: "(?i)^\\s*Destination\\s+address\\s*:"
+	// Slash-separated path relative to the importing file. Imports can't
+	// leave the root directory of the loader, e.g. the directory given by the
+	// "-sketch_root" flag of the command line tools.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Prefix for the names of imported nodes and templates. Names are
+	// separated from the prefix by a dot, e.g. "footer.iban". References
+	// between imported nodes are adjusted accordingly. Required.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sketch_Import) Reset() {
+	*x = Sketch_Import{}
+	mi := &file_sketch_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sketch_Import) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sketch_Import) ProtoMessage() {}
+
+func (x *Sketch_Import) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sketch_Import.ProtoReflect.Descriptor instead.
+func (*Sketch_Import) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{10, 0}
+}
+
+func (x *Sketch_Import) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Sketch_Import) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+// Named value which can be overridden when compiling a sketch, e.g. with
+// the "-param" flag of the command line tools. The type of a parameter is
+// given by its default value.
+//
+// String parameters are referenced as "${name}" in regular expressions and
+// fuzzy texts. Their value is inserted verbatim, i.e. it can itself be
+// a regular expression. Length parameters are used via "Length.param".
+//
+// Example:
+//
+//	params { name: "customer" string_value: "C-\\d{5}" }
+//	params { name: "shift" length_value { mm: 0 } }
+type Sketch_Param struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Types that are valid to be assigned to Value:
 	//
-	// Syntax: https://pkg.go.dev/regexp/syntax
-	Regex string `protobuf:"bytes,1,opt,name=regex,proto3" json:"regex,omitempty"`
-	// By default the reported bounds of valid matches are those of the block
-	// or line containing the matched text. By setting "bounds_from_match" the
-	// exact bounds of the matched text are used instead.
-	BoundsFromMatch bool `protobuf:"varint,2,opt,name=bounds_from_match,json=boundsFromMatch,proto3" json:"bounds_from_match,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	//	*Sketch_Param_StringValue
+	//	*Sketch_Param_LengthValue
+	Value         isSketch_Param_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Node_TextMatch) Reset() {
-	*x = Node_TextMatch{}
-	mi := &file_sketch_proto_msgTypes[7]
+func (x *Sketch_Param) Reset() {
+	*x = Sketch_Param{}
+	mi := &file_sketch_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Node_TextMatch) String() string {
+func (x *Sketch_Param) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Node_TextMatch) ProtoMessage() {}
+func (*Sketch_Param) ProtoMessage() {}
 
-func (x *Node_TextMatch) ProtoReflect() protoreflect.Message {
-	mi := &file_sketch_proto_msgTypes[7]
+func (x *Sketch_Param) ProtoReflect() protoreflect.Message {
+	mi := &file_sketch_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -727,25 +2277,59 @@ func (x *Node_TextMatch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Node_TextMatch.ProtoReflect.Descriptor instead.
-func (*Node_TextMatch) Descriptor() ([]byte, []int) {
-	return file_sketch_proto_rawDescGZIP(), []int{3, 0}
+// Deprecated: Use Sketch_Param.ProtoReflect.Descriptor instead.
+func (*Sketch_Param) Descriptor() ([]byte, []int) {
+	return file_sketch_proto_rawDescGZIP(), []int{10, 1}
 }
 
-func (x *Node_TextMatch) GetRegex() string {
+func (x *Sketch_Param) GetName() string {
 	if x != nil {
-		return x.Regex
+		return x.Name
 	}
 	return ""
 }
 
-func (x *Node_TextMatch) GetBoundsFromMatch() bool {
+func (x *Sketch_Param) GetValue() isSketch_Param_Value {
 	if x != nil {
-		return x.BoundsFromMatch
+		return x.Value
 	}
-	return false
+	return nil
+}
+
+func (x *Sketch_Param) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*Sketch_Param_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+func (x *Sketch_Param) GetLengthValue() *geometrypb.Length {
+	if x != nil {
+		if x, ok := x.Value.(*Sketch_Param_LengthValue); ok {
+			return x.LengthValue
+		}
+	}
+	return nil
+}
+
+type isSketch_Param_Value interface {
+	isSketch_Param_Value()
+}
+
+type Sketch_Param_StringValue struct {
+	StringValue string `protobuf:"bytes,10,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type Sketch_Param_LengthValue struct {
+	LengthValue *geometrypb.Length `protobuf:"bytes,11,opt,name=length_value,json=lengthValue,proto3,oneof"`
 }
 
+func (*Sketch_Param_StringValue) isSketch_Param_Value() {}
+
+func (*Sketch_Param_LengthValue) isSketch_Param_Value() {}
+
 var File_sketch_proto protoreflect.FileDescriptor
 
 const file_sketch_proto_rawDesc = "" +
@@ -779,28 +2363,154 @@ const file_sketch_proto_rawDesc = "" +
 	"\x04Edge\x12,\n" +
 	"\x03abs\x18\x01 \x01(\v2\x18.dossier.geometry.LengthH\x00R\x03abs\x126\n" +
 	"\x03rel\x18\x02 \x01(\v2\".dossier.sketch.RelativePosition1DH\x00R\x03relB\b\n" +
-	"\x06method\"\xc5\x02\n" +
+	"\x06method\"\x83\x01\n" +
+	"\tSelection\x12=\n" +
+	"\bstrategy\x18\x01 \x01(\x0e2!.dossier.sketch.SelectionStrategyR\bstrategy\x127\n" +
+	"\x06anchor\x18\x02 \x01(\v2\x1f.dossier.sketch.FlexRect.VertexR\x06anchor\"\xbf\x02\n" +
+	"\fAdjacentArea\x12\x12\n" +
+	"\x04node\x18\x01 \x01(\tR\x04node\x127\n" +
+	"\tdirection\x18\x02 \x01(\x0e2\x19.dossier.sketch.DirectionR\tdirection\x126\n" +
+	"\bdistance\x18\x03 \x01(\v2\x18.dossier.geometry.LengthH\x00R\bdistance\x12\x16\n" +
+	"\x05lines\x18\x04 \x01(\rH\x00R\x05lines\x129\n" +
+	"\vline_height\x18\x05 \x01(\v2\x18.dossier.geometry.LengthR\n" +
+	"lineHeight\x12\x1b\n" +
+	"\tspan_page\x18\x06 \x01(\bR\bspanPage\x120\n" +
+	"\x06margin\x18\a \x01(\v2\x18.dossier.geometry.LengthR\x06marginB\b\n" +
+	"\x06extent\"\xa2\x01\n" +
+	"\rNormalization\x12\x12\n" +
+	"\x04nfkc\x18\x01 \x01(\bR\x04nfkc\x12!\n" +
+	"\funify_dashes\x18\x02 \x01(\bR\vunifyDashes\x12)\n" +
+	"\x10join_hyphenation\x18\x03 \x01(\bR\x0fjoinHyphenation\x12/\n" +
+	"\x13collapse_whitespace\x18\x04 \x01(\bR\x12collapseWhitespace\"\xb6\x01\n" +
+	"\vContainment\x124\n" +
+	"\x04mode\x18\x01 \x01(\x0e2 .dossier.sketch.Containment.ModeR\x04mode\x12\x1f\n" +
+	"\vmin_overlap\x18\x02 \x01(\x01R\n" +
+	"minOverlap\x12\x1b\n" +
+	"\tclip_text\x18\x03 \x01(\bR\bclipText\"3\n" +
+	"\x04Mode\x12\r\n" +
+	"\tCONTAINED\x10\x00\x12\x10\n" +
+	"\fINTERSECTING\x10\x01\x12\n" +
+	"\n" +
+	"\x06CENTER\x10\x02\"\xbb\x01\n" +
+	"\x0eNeighborSearch\x12\x12\n" +
+	"\x04node\x18\x01 \x01(\tR\x04node\x127\n" +
+	"\tdirection\x18\x02 \x01(\x0e2\x19.dossier.sketch.DirectionR\tdirection\x12;\n" +
+	"\fmax_distance\x18\x03 \x01(\v2\x18.dossier.geometry.LengthR\vmaxDistance\x12\x1f\n" +
+	"\vmin_overlap\x18\x04 \x01(\x01R\n" +
+	"minOverlap\"\xb4\x12\n" +
 	"\x04Node\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12;\n" +
-	"\fsearch_areas\x18d \x03(\v2\x18.dossier.sketch.FlexRectR\vsearchAreas\x12?\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\btemplate\x18\x02 \x01(\tR\btemplate\x12;\n" +
+	"\fsearch_areas\x18d \x03(\v2\x18.dossier.sketch.FlexRectR\vsearchAreas\x12C\n" +
+	"\x0eadjacent_areas\x18f \x03(\v2\x1c.dossier.sketch.AdjacentAreaR\radjacentAreas\x12:\n" +
+	"\bneighbor\x18e \x01(\v2\x1e.dossier.sketch.NeighborSearchR\bneighbor\x127\n" +
+	"\tselection\x18g \x01(\v2\x19.dossier.sketch.SelectionR\tselection\x12=\n" +
+	"\vcontainment\x18h \x01(\v2\x1b.dossier.sketch.ContainmentR\vcontainment\x128\n" +
+	"\n" +
+	"exclusions\x18i \x03(\v2\x18.dossier.sketch.FlexRectR\n" +
+	"exclusions\x12H\n" +
+	"\x0emust_not_match\x18j \x03(\v2\".dossier.sketch.Node.NegativeMatchR\fmustNotMatch\x12C\n" +
+	"\rnormalization\x18k \x01(\v2\x1d.dossier.sketch.NormalizationR\rnormalization\x12?\n" +
 	"\n" +
 	"block_text\x18\n" +
 	" \x01(\v2\x1e.dossier.sketch.Node.TextMatchH\x00R\tblockText\x12=\n" +
-	"\tline_text\x18\v \x01(\v2\x1e.dossier.sketch.Node.TextMatchH\x00R\blineText\x12\x12\n" +
-	"\x04tags\x18\x0f \x03(\tR\x04tags\x1aM\n" +
+	"\tline_text\x18\v \x01(\v2\x1e.dossier.sketch.Node.TextMatchH\x00R\blineText\x12D\n" +
+	"\n" +
+	"multi_line\x18\f \x01(\v2#.dossier.sketch.Node.MultiLineMatchH\x00R\tmultiLine\x12D\n" +
+	"\falternatives\x18l \x03(\v2 .dossier.sketch.Node.AlternativeR\falternatives\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\x1a\x7f\n" +
 	"\tTextMatch\x12\x14\n" +
 	"\x05regex\x18\x01 \x01(\tR\x05regex\x12*\n" +
-	"\x11bounds_from_match\x18\x02 \x01(\bR\x0fboundsFromMatchB\t\n" +
-	"\amatcher\"H\n" +
+	"\x11bounds_from_match\x18\x02 \x01(\bR\x0fboundsFromMatch\x120\n" +
+	"\x05fuzzy\x18\x03 \x01(\v2\x1a.dossier.sketch.Node.FuzzyR\x05fuzzy\x1a(\n" +
+	"\n" +
+	"Confusable\x12\x1a\n" +
+	"\bvariants\x18\x01 \x03(\tR\bvariants\x1a\xa9\x02\n" +
+	"\x05Fuzzy\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12!\n" +
+	"\fmax_distance\x18\x02 \x01(\rR\vmaxDistance\x12\x1f\n" +
+	"\vignore_case\x18\x03 \x01(\bR\n" +
+	"ignoreCase\x12+\n" +
+	"\x11ignore_diacritics\x18\x04 \x01(\bR\x10ignoreDiacritics\x12'\n" +
+	"\x0ffold_whitespace\x18\x05 \x01(\bR\x0efoldWhitespace\x12A\n" +
+	"\vconfusables\x18\x06 \x03(\v2\x1f.dossier.sketch.Node.ConfusableR\vconfusables\x12/\n" +
+	"\x13default_confusables\x18\a \x01(\bR\x12defaultConfusables\x1a\xa4\x02\n" +
+	"\rNegativeMatch\x12;\n" +
+	"\vsearch_area\x18\x01 \x01(\v2\x18.dossier.sketch.FlexRectH\x00R\n" +
+	"searchArea\x12C\n" +
+	"\radjacent_area\x18\x02 \x01(\v2\x1c.dossier.sketch.AdjacentAreaH\x00R\fadjacentArea\x12?\n" +
+	"\n" +
+	"block_text\x18\n" +
+	" \x01(\v2\x1e.dossier.sketch.Node.TextMatchH\x01R\tblockText\x12=\n" +
+	"\tline_text\x18\v \x01(\v2\x1e.dossier.sketch.Node.TextMatchH\x01R\blineTextB\x06\n" +
+	"\x04areaB\t\n" +
+	"\amatcher\x1a\xdc\x02\n" +
+	"\x0eMultiLineMatch\x126\n" +
+	"\x06anchor\x18\x01 \x01(\v2\x1e.dossier.sketch.Node.TextMatchR\x06anchor\x122\n" +
+	"\x04text\x18\x02 \x01(\v2\x1e.dossier.sketch.Node.TextMatchR\x04text\x12%\n" +
+	"\x0einclude_anchor\x18\x03 \x01(\bR\rincludeAnchor\x12?\n" +
+	"\x0eleft_tolerance\x18\x04 \x01(\v2\x18.dossier.geometry.LengthR\rleftTolerance\x12:\n" +
+	"\fmax_line_gap\x18\x05 \x01(\v2\x18.dossier.geometry.LengthR\n" +
+	"maxLineGap\x12\x1d\n" +
+	"\n" +
+	"stop_regex\x18\x06 \x01(\tR\tstopRegex\x12\x1b\n" +
+	"\tmax_lines\x18\a \x01(\rR\bmaxLines\x1a\xf4\x02\n" +
+	"\vAlternative\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12;\n" +
+	"\fsearch_areas\x18\x02 \x03(\v2\x18.dossier.sketch.FlexRectR\vsearchAreas\x12C\n" +
+	"\x0eadjacent_areas\x18\x03 \x03(\v2\x1c.dossier.sketch.AdjacentAreaR\radjacentAreas\x12?\n" +
+	"\n" +
+	"block_text\x18\n" +
+	" \x01(\v2\x1e.dossier.sketch.Node.TextMatchH\x00R\tblockText\x12=\n" +
+	"\tline_text\x18\v \x01(\v2\x1e.dossier.sketch.Node.TextMatchH\x00R\blineText\x12D\n" +
+	"\n" +
+	"multi_line\x18\f \x01(\v2#.dossier.sketch.Node.MultiLineMatchH\x00R\tmultiLineB\t\n" +
+	"\amatcherB\t\n" +
+	"\amatcher\"J\n" +
+	"\fComputedNode\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04expr\x18\x02 \x01(\tR\x04expr\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\"\xb1\x04\n" +
 	"\x06Sketch\x12*\n" +
-	"\x05nodes\x18\x01 \x03(\v2\x14.dossier.sketch.NodeR\x05nodes\x12\x12\n" +
-	"\x04tags\x18\x0f \x03(\tR\x04tags*k\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x14.dossier.sketch.NodeR\x05nodes\x127\n" +
+	"\aimports\x18\x03 \x03(\v2\x1d.dossier.sketch.Sketch.ImportR\aimports\x122\n" +
+	"\ttemplates\x18\x04 \x03(\v2\x14.dossier.sketch.NodeR\ttemplates\x12C\n" +
+	"\rnormalization\x18\x02 \x01(\v2\x1d.dossier.sketch.NormalizationR\rnormalization\x124\n" +
+	"\x06params\x18\x05 \x03(\v2\x1c.dossier.sketch.Sketch.ParamR\x06params\x128\n" +
+	"\bcomputed\x18\x06 \x03(\v2\x1c.dossier.sketch.ComputedNodeR\bcomputed\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\x1a:\n" +
+	"\x06Import\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x1a\x88\x01\n" +
+	"\x05Param\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12#\n" +
+	"\fstring_value\x18\n" +
+	" \x01(\tH\x00R\vstringValue\x12=\n" +
+	"\flength_value\x18\v \x01(\v2\x18.dossier.geometry.LengthH\x00R\vlengthValueB\a\n" +
+	"\x05value*k\n" +
 	"\vNodeFeature\x12\x1c\n" +
 	"\x18NODE_FEATURE_UNSPECIFIED\x10\x00\x12\f\n" +
 	"\bTOP_LEFT\x10\x01\x12\r\n" +
 	"\tTOP_RIGHT\x10\x02\x12\x0f\n" +
 	"\vBOTTOM_LEFT\x10\x03\x12\x10\n" +
-	"\fBOTTOM_RIGHT\x10\x04B*Z(github.com/hansmi/dossier/proto/sketchpbb\x06proto3"
+	"\fBOTTOM_RIGHT\x10\x04*M\n" +
+	"\tDirection\x12\x19\n" +
+	"\x15DIRECTION_UNSPECIFIED\x10\x00\x12\x06\n" +
+	"\x02UP\x10\x01\x12\b\n" +
+	"\x04DOWN\x10\x02\x12\b\n" +
+	"\x04LEFT\x10\x03\x12\t\n" +
+	"\x05RIGHT\x10\x04*\x86\x01\n" +
+	"\x11SelectionStrategy\x12\x11\n" +
+	"\rREADING_ORDER\x10\x00\x12\v\n" +
+	"\aTOPMOST\x10\x01\x12\x0e\n" +
+	"\n" +
+	"BOTTOMMOST\x10\x02\x12\f\n" +
+	"\bLEFTMOST\x10\x03\x12\r\n" +
+	"\tRIGHTMOST\x10\x04\x12\v\n" +
+	"\aCLOSEST\x10\x05\x12\v\n" +
+	"\aLARGEST\x10\x06\x12\n" +
+	"\n" +
+	"\x06UNIQUE\x10\aB*Z(github.com/hansmi/dossier/proto/sketchpbb\x06proto3"
 
 var (
 	file_sketch_proto_rawDescOnce sync.Once
@@ -814,50 +2524,105 @@ func file_sketch_proto_rawDescGZIP() []byte {
 	return file_sketch_proto_rawDescData
 }
 
-var file_sketch_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_sketch_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_sketch_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_sketch_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_sketch_proto_goTypes = []any{
-	(NodeFeature)(0),           // 0: dossier.sketch.NodeFeature
-	(*RelativePosition1D)(nil), // 1: dossier.sketch.RelativePosition1D
-	(*RelativePosition2D)(nil), // 2: dossier.sketch.RelativePosition2D
-	(*FlexRect)(nil),           // 3: dossier.sketch.FlexRect
-	(*Node)(nil),               // 4: dossier.sketch.Node
-	(*Sketch)(nil),             // 5: dossier.sketch.Sketch
-	(*FlexRect_Vertex)(nil),    // 6: dossier.sketch.FlexRect.Vertex
-	(*FlexRect_Edge)(nil),      // 7: dossier.sketch.FlexRect.Edge
-	(*Node_TextMatch)(nil),     // 8: dossier.sketch.Node.TextMatch
-	(*geometrypb.Length)(nil),  // 9: dossier.geometry.Length
-	(*geometrypb.Size)(nil),    // 10: dossier.geometry.Size
-	(*geometrypb.Point)(nil),   // 11: dossier.geometry.Point
+	(NodeFeature)(0),            // 0: dossier.sketch.NodeFeature
+	(Direction)(0),              // 1: dossier.sketch.Direction
+	(SelectionStrategy)(0),      // 2: dossier.sketch.SelectionStrategy
+	(Containment_Mode)(0),       // 3: dossier.sketch.Containment.Mode
+	(*RelativePosition1D)(nil),  // 4: dossier.sketch.RelativePosition1D
+	(*RelativePosition2D)(nil),  // 5: dossier.sketch.RelativePosition2D
+	(*FlexRect)(nil),            // 6: dossier.sketch.FlexRect
+	(*Selection)(nil),           // 7: dossier.sketch.Selection
+	(*AdjacentArea)(nil),        // 8: dossier.sketch.AdjacentArea
+	(*Normalization)(nil),       // 9: dossier.sketch.Normalization
+	(*Containment)(nil),         // 10: dossier.sketch.Containment
+	(*NeighborSearch)(nil),      // 11: dossier.sketch.NeighborSearch
+	(*Node)(nil),                // 12: dossier.sketch.Node
+	(*ComputedNode)(nil),        // 13: dossier.sketch.ComputedNode
+	(*Sketch)(nil),              // 14: dossier.sketch.Sketch
+	(*FlexRect_Vertex)(nil),     // 15: dossier.sketch.FlexRect.Vertex
+	(*FlexRect_Edge)(nil),       // 16: dossier.sketch.FlexRect.Edge
+	(*Node_TextMatch)(nil),      // 17: dossier.sketch.Node.TextMatch
+	(*Node_Confusable)(nil),     // 18: dossier.sketch.Node.Confusable
+	(*Node_Fuzzy)(nil),          // 19: dossier.sketch.Node.Fuzzy
+	(*Node_NegativeMatch)(nil),  // 20: dossier.sketch.Node.NegativeMatch
+	(*Node_MultiLineMatch)(nil), // 21: dossier.sketch.Node.MultiLineMatch
+	(*Node_Alternative)(nil),    // 22: dossier.sketch.Node.Alternative
+	(*Sketch_Import)(nil),       // 23: dossier.sketch.Sketch.Import
+	(*Sketch_Param)(nil),        // 24: dossier.sketch.Sketch.Param
+	(*geometrypb.Length)(nil),   // 25: dossier.geometry.Length
+	(*geometrypb.Size)(nil),     // 26: dossier.geometry.Size
+	(*geometrypb.Point)(nil),    // 27: dossier.geometry.Point
 }
 var file_sketch_proto_depIdxs = []int32{
 	0,  // 0: dossier.sketch.RelativePosition1D.feature:type_name -> dossier.sketch.NodeFeature
-	9,  // 1: dossier.sketch.RelativePosition1D.offset:type_name -> dossier.geometry.Length
+	25, // 1: dossier.sketch.RelativePosition1D.offset:type_name -> dossier.geometry.Length
 	0,  // 2: dossier.sketch.RelativePosition2D.feature:type_name -> dossier.sketch.NodeFeature
-	10, // 3: dossier.sketch.RelativePosition2D.offset:type_name -> dossier.geometry.Size
-	6,  // 4: dossier.sketch.FlexRect.top_left:type_name -> dossier.sketch.FlexRect.Vertex
-	6,  // 5: dossier.sketch.FlexRect.top_right:type_name -> dossier.sketch.FlexRect.Vertex
-	6,  // 6: dossier.sketch.FlexRect.bottom_left:type_name -> dossier.sketch.FlexRect.Vertex
-	6,  // 7: dossier.sketch.FlexRect.bottom_right:type_name -> dossier.sketch.FlexRect.Vertex
-	7,  // 8: dossier.sketch.FlexRect.top:type_name -> dossier.sketch.FlexRect.Edge
-	7,  // 9: dossier.sketch.FlexRect.right:type_name -> dossier.sketch.FlexRect.Edge
-	7,  // 10: dossier.sketch.FlexRect.bottom:type_name -> dossier.sketch.FlexRect.Edge
-	7,  // 11: dossier.sketch.FlexRect.left:type_name -> dossier.sketch.FlexRect.Edge
-	9,  // 12: dossier.sketch.FlexRect.width:type_name -> dossier.geometry.Length
-	9,  // 13: dossier.sketch.FlexRect.height:type_name -> dossier.geometry.Length
-	3,  // 14: dossier.sketch.Node.search_areas:type_name -> dossier.sketch.FlexRect
-	8,  // 15: dossier.sketch.Node.block_text:type_name -> dossier.sketch.Node.TextMatch
-	8,  // 16: dossier.sketch.Node.line_text:type_name -> dossier.sketch.Node.TextMatch
-	4,  // 17: dossier.sketch.Sketch.nodes:type_name -> dossier.sketch.Node
-	11, // 18: dossier.sketch.FlexRect.Vertex.abs:type_name -> dossier.geometry.Point
-	2,  // 19: dossier.sketch.FlexRect.Vertex.rel:type_name -> dossier.sketch.RelativePosition2D
-	9,  // 20: dossier.sketch.FlexRect.Edge.abs:type_name -> dossier.geometry.Length
-	1,  // 21: dossier.sketch.FlexRect.Edge.rel:type_name -> dossier.sketch.RelativePosition1D
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	26, // 3: dossier.sketch.RelativePosition2D.offset:type_name -> dossier.geometry.Size
+	15, // 4: dossier.sketch.FlexRect.top_left:type_name -> dossier.sketch.FlexRect.Vertex
+	15, // 5: dossier.sketch.FlexRect.top_right:type_name -> dossier.sketch.FlexRect.Vertex
+	15, // 6: dossier.sketch.FlexRect.bottom_left:type_name -> dossier.sketch.FlexRect.Vertex
+	15, // 7: dossier.sketch.FlexRect.bottom_right:type_name -> dossier.sketch.FlexRect.Vertex
+	16, // 8: dossier.sketch.FlexRect.top:type_name -> dossier.sketch.FlexRect.Edge
+	16, // 9: dossier.sketch.FlexRect.right:type_name -> dossier.sketch.FlexRect.Edge
+	16, // 10: dossier.sketch.FlexRect.bottom:type_name -> dossier.sketch.FlexRect.Edge
+	16, // 11: dossier.sketch.FlexRect.left:type_name -> dossier.sketch.FlexRect.Edge
+	25, // 12: dossier.sketch.FlexRect.width:type_name -> dossier.geometry.Length
+	25, // 13: dossier.sketch.FlexRect.height:type_name -> dossier.geometry.Length
+	2,  // 14: dossier.sketch.Selection.strategy:type_name -> dossier.sketch.SelectionStrategy
+	15, // 15: dossier.sketch.Selection.anchor:type_name -> dossier.sketch.FlexRect.Vertex
+	1,  // 16: dossier.sketch.AdjacentArea.direction:type_name -> dossier.sketch.Direction
+	25, // 17: dossier.sketch.AdjacentArea.distance:type_name -> dossier.geometry.Length
+	25, // 18: dossier.sketch.AdjacentArea.line_height:type_name -> dossier.geometry.Length
+	25, // 19: dossier.sketch.AdjacentArea.margin:type_name -> dossier.geometry.Length
+	3,  // 20: dossier.sketch.Containment.mode:type_name -> dossier.sketch.Containment.Mode
+	1,  // 21: dossier.sketch.NeighborSearch.direction:type_name -> dossier.sketch.Direction
+	25, // 22: dossier.sketch.NeighborSearch.max_distance:type_name -> dossier.geometry.Length
+	6,  // 23: dossier.sketch.Node.search_areas:type_name -> dossier.sketch.FlexRect
+	8,  // 24: dossier.sketch.Node.adjacent_areas:type_name -> dossier.sketch.AdjacentArea
+	11, // 25: dossier.sketch.Node.neighbor:type_name -> dossier.sketch.NeighborSearch
+	7,  // 26: dossier.sketch.Node.selection:type_name -> dossier.sketch.Selection
+	10, // 27: dossier.sketch.Node.containment:type_name -> dossier.sketch.Containment
+	6,  // 28: dossier.sketch.Node.exclusions:type_name -> dossier.sketch.FlexRect
+	20, // 29: dossier.sketch.Node.must_not_match:type_name -> dossier.sketch.Node.NegativeMatch
+	9,  // 30: dossier.sketch.Node.normalization:type_name -> dossier.sketch.Normalization
+	17, // 31: dossier.sketch.Node.block_text:type_name -> dossier.sketch.Node.TextMatch
+	17, // 32: dossier.sketch.Node.line_text:type_name -> dossier.sketch.Node.TextMatch
+	21, // 33: dossier.sketch.Node.multi_line:type_name -> dossier.sketch.Node.MultiLineMatch
+	22, // 34: dossier.sketch.Node.alternatives:type_name -> dossier.sketch.Node.Alternative
+	12, // 35: dossier.sketch.Sketch.nodes:type_name -> dossier.sketch.Node
+	23, // 36: dossier.sketch.Sketch.imports:type_name -> dossier.sketch.Sketch.Import
+	12, // 37: dossier.sketch.Sketch.templates:type_name -> dossier.sketch.Node
+	9,  // 38: dossier.sketch.Sketch.normalization:type_name -> dossier.sketch.Normalization
+	24, // 39: dossier.sketch.Sketch.params:type_name -> dossier.sketch.Sketch.Param
+	13, // 40: dossier.sketch.Sketch.computed:type_name -> dossier.sketch.ComputedNode
+	27, // 41: dossier.sketch.FlexRect.Vertex.abs:type_name -> dossier.geometry.Point
+	5,  // 42: dossier.sketch.FlexRect.Vertex.rel:type_name -> dossier.sketch.RelativePosition2D
+	25, // 43: dossier.sketch.FlexRect.Edge.abs:type_name -> dossier.geometry.Length
+	4,  // 44: dossier.sketch.FlexRect.Edge.rel:type_name -> dossier.sketch.RelativePosition1D
+	19, // 45: dossier.sketch.Node.TextMatch.fuzzy:type_name -> dossier.sketch.Node.Fuzzy
+	18, // 46: dossier.sketch.Node.Fuzzy.confusables:type_name -> dossier.sketch.Node.Confusable
+	6,  // 47: dossier.sketch.Node.NegativeMatch.search_area:type_name -> dossier.sketch.FlexRect
+	8,  // 48: dossier.sketch.Node.NegativeMatch.adjacent_area:type_name -> dossier.sketch.AdjacentArea
+	17, // 49: dossier.sketch.Node.NegativeMatch.block_text:type_name -> dossier.sketch.Node.TextMatch
+	17, // 50: dossier.sketch.Node.NegativeMatch.line_text:type_name -> dossier.sketch.Node.TextMatch
+	17, // 51: dossier.sketch.Node.MultiLineMatch.anchor:type_name -> dossier.sketch.Node.TextMatch
+	17, // 52: dossier.sketch.Node.MultiLineMatch.text:type_name -> dossier.sketch.Node.TextMatch
+	25, // 53: dossier.sketch.Node.MultiLineMatch.left_tolerance:type_name -> dossier.geometry.Length
+	25, // 54: dossier.sketch.Node.MultiLineMatch.max_line_gap:type_name -> dossier.geometry.Length
+	6,  // 55: dossier.sketch.Node.Alternative.search_areas:type_name -> dossier.sketch.FlexRect
+	8,  // 56: dossier.sketch.Node.Alternative.adjacent_areas:type_name -> dossier.sketch.AdjacentArea
+	17, // 57: dossier.sketch.Node.Alternative.block_text:type_name -> dossier.sketch.Node.TextMatch
+	17, // 58: dossier.sketch.Node.Alternative.line_text:type_name -> dossier.sketch.Node.TextMatch
+	21, // 59: dossier.sketch.Node.Alternative.multi_line:type_name -> dossier.sketch.Node.MultiLineMatch
+	25, // 60: dossier.sketch.Sketch.Param.length_value:type_name -> dossier.geometry.Length
+	61, // [61:61] is the sub-list for method output_type
+	61, // [61:61] is the sub-list for method input_type
+	61, // [61:61] is the sub-list for extension type_name
+	61, // [61:61] is the sub-list for extension extendee
+	0,  // [0:61] is the sub-list for field type_name
 }
 
 func init() { file_sketch_proto_init() }
@@ -865,25 +2630,45 @@ func file_sketch_proto_init() {
 	if File_sketch_proto != nil {
 		return
 	}
-	file_sketch_proto_msgTypes[3].OneofWrappers = []any{
+	file_sketch_proto_msgTypes[4].OneofWrappers = []any{
+		(*AdjacentArea_Distance)(nil),
+		(*AdjacentArea_Lines)(nil),
+	}
+	file_sketch_proto_msgTypes[8].OneofWrappers = []any{
 		(*Node_BlockText)(nil),
 		(*Node_LineText)(nil),
+		(*Node_MultiLine)(nil),
 	}
-	file_sketch_proto_msgTypes[5].OneofWrappers = []any{
+	file_sketch_proto_msgTypes[11].OneofWrappers = []any{
 		(*FlexRect_Vertex_Abs)(nil),
 		(*FlexRect_Vertex_Rel)(nil),
 	}
-	file_sketch_proto_msgTypes[6].OneofWrappers = []any{
+	file_sketch_proto_msgTypes[12].OneofWrappers = []any{
 		(*FlexRect_Edge_Abs)(nil),
 		(*FlexRect_Edge_Rel)(nil),
 	}
+	file_sketch_proto_msgTypes[16].OneofWrappers = []any{
+		(*Node_NegativeMatch_SearchArea)(nil),
+		(*Node_NegativeMatch_AdjacentArea)(nil),
+		(*Node_NegativeMatch_BlockText)(nil),
+		(*Node_NegativeMatch_LineText)(nil),
+	}
+	file_sketch_proto_msgTypes[18].OneofWrappers = []any{
+		(*Node_Alternative_BlockText)(nil),
+		(*Node_Alternative_LineText)(nil),
+		(*Node_Alternative_MultiLine)(nil),
+	}
+	file_sketch_proto_msgTypes[20].OneofWrappers = []any{
+		(*Sketch_Param_StringValue)(nil),
+		(*Sketch_Param_LengthValue)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sketch_proto_rawDesc), len(file_sketch_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   8,
+			NumEnums:      4,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
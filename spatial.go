@@ -0,0 +1,198 @@
+package dossier
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	rtree "github.com/dhconnelly/rtreego"
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+)
+
+// PageElementFilterFunc reports whether an element should be included in
+// a result.
+type PageElementFilterFunc func(content.Element) bool
+
+func (f PageElementFilterFunc) accept(elem content.Element) bool {
+	return f == nil || f(elem)
+}
+
+// NearestElements returns up to k elements closest to the given point ordered
+// by increasing distance. The distance is measured to the closest point of an
+// element's bounds, i.e. elements containing the point have a distance of
+// zero. Elements for which the filter returns false are skipped. A nil filter
+// accepts all elements.
+func (p *Page) NearestElements(pt geometry.Point, k int, filter PageElementFilterFunc) []content.Element {
+	if k < 1 || len(p.elems) == 0 {
+		return nil
+	}
+
+	objects := p.tree.NearestNeighbors(k, rtree.Point{pt.Top.Pt(), pt.Left.Pt()},
+		func(_ []rtree.Spatial, obj rtree.Spatial) (refuse, abort bool) {
+			return !filter.accept(obj.(*spatialAdapter).elem), false
+		})
+
+	result := make([]content.Element, 0, len(objects))
+
+	for _, obj := range objects {
+		if obj != nil {
+			result = append(result, obj.(*spatialAdapter).elem)
+		}
+	}
+
+	return result
+}
+
+type DirectionalSearchOptions struct {
+	// Maximum distance between the reference rectangle and an element.
+	// Unlimited when zero.
+	MaxDistance geometry.Length
+
+	// Minimum overlap on the axis orthogonal to the search direction relative
+	// to the smaller of the two extents. Values are in the range [0, 1].
+	// Elements must always overlap at least partially.
+	MinOverlap float64
+
+	// Elements for which the filter returns false are skipped. Optional.
+	Filter PageElementFilterFunc
+}
+
+// directionalBand returns the area beyond the given edge of a rectangle in
+// which elements need to be located.
+func directionalBand(from geometry.Rect, dir geometry.Direction, maxDistance geometry.Length) geometry.Rect {
+	const unlimited = geometry.Length(math.MaxFloat64 / 4)
+
+	if maxDistance <= 0 {
+		maxDistance = unlimited
+	}
+
+	band := from
+
+	switch dir {
+	case geometry.Up:
+		band.Bottom = from.Top
+		band.Top = from.Top - maxDistance
+	case geometry.Down:
+		band.Top = from.Bottom
+		band.Bottom = from.Bottom + maxDistance
+	case geometry.Left:
+		band.Right = from.Left
+		band.Left = from.Left - maxDistance
+	case geometry.Right:
+		band.Left = from.Right
+		band.Right = from.Right + maxDistance
+	}
+
+	return band
+}
+
+type directionalCandidate struct {
+	elem     content.Element
+	distance geometry.Length
+	offset   geometry.Length
+}
+
+// directionalMetrics computes the distance from the reference rectangle to
+// the element in the given direction, the offset between their centers on the
+// orthogonal axis and the relative overlap on the orthogonal axis. The
+// returned flag is false if the element isn't located in the direction.
+func directionalMetrics(from, bounds geometry.Rect, dir geometry.Direction) (distance, offset geometry.Length, overlap float64, ok bool) {
+	var start, end, otherStart, otherEnd geometry.Length
+
+	switch dir {
+	case geometry.Up:
+		distance = from.Top - bounds.Bottom
+	case geometry.Down:
+		distance = bounds.Top - from.Bottom
+	case geometry.Left:
+		distance = from.Left - bounds.Right
+	case geometry.Right:
+		distance = bounds.Left - from.Right
+	}
+
+	if dir.Horizontal() {
+		start, end = from.Top, from.Bottom
+		otherStart, otherEnd = bounds.Top, bounds.Bottom
+		offset = (bounds.Center().Top - from.Center().Top).Abs()
+	} else {
+		start, end = from.Left, from.Right
+		otherStart, otherEnd = bounds.Left, bounds.Right
+		offset = (bounds.Center().Left - from.Center().Left).Abs()
+	}
+
+	common := end.Min(otherEnd) - start.Max(otherStart)
+	extent := (end - start).Min(otherEnd - otherStart)
+
+	switch {
+	case extent <= 0 && common >= 0:
+		// Degenerate rectangles
+		overlap = 1
+	case common <= 0:
+		overlap = -1
+	default:
+		overlap = float64(common / extent)
+	}
+
+	return distance, offset, overlap, distance >= 0 && overlap >= 0
+}
+
+// ElementsInDirection returns all elements located beyond the edge of the
+// reference rectangle in the given direction, e.g. all elements below
+// a rectangle for [geometry.Down]. Elements must overlap with the reference
+// rectangle on the orthogonal axis. The result is ordered by increasing
+// distance. Elements at the same distance are ordered by the offset between
+// their centers on the orthogonal axis and then in reading order.
+func (p *Page) ElementsInDirection(from geometry.Rect, dir geometry.Direction, opts DirectionalSearchOptions) ([]content.Element, error) {
+	var candidates []directionalCandidate
+
+	if err := p.VisitElementsIntersecting(directionalBand(from, dir, opts.MaxDistance), func(elem content.Element) error {
+		if !opts.Filter.accept(elem) {
+			return nil
+		}
+
+		distance, offset, overlap, ok := directionalMetrics(from, elem.Bounds(), dir)
+
+		if ok && overlap >= opts.MinOverlap && (opts.MaxDistance <= 0 || distance <= opts.MaxDistance) {
+			candidates = append(candidates, directionalCandidate{
+				elem:     elem,
+				distance: distance,
+				offset:   offset,
+			})
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	compareRect := geometry.MakeRectRowColumnCompare(geometry.TopToBottom, geometry.LeftToRight)
+
+	slices.SortStableFunc(candidates, func(a, b directionalCandidate) int {
+		return cmp.Or(
+			cmp.Compare(a.distance, b.distance),
+			cmp.Compare(a.offset, b.offset),
+			compareRect(a.elem.Bounds(), b.elem.Bounds()),
+		)
+	})
+
+	result := make([]content.Element, 0, len(candidates))
+
+	for _, c := range candidates {
+		result = append(result, c.elem)
+	}
+
+	return result, nil
+}
+
+// NearestElementInDirection returns the closest element in the given
+// direction as determined by [Page.ElementsInDirection]. Nil is returned if
+// there is no such element.
+func (p *Page) NearestElementInDirection(from geometry.Rect, dir geometry.Direction, opts DirectionalSearchOptions) (content.Element, error) {
+	elems, err := p.ElementsInDirection(from, dir, opts)
+	if err != nil || len(elems) == 0 {
+		return nil, err
+	}
+
+	return elems[0], nil
+}
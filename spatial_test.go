@@ -0,0 +1,199 @@
+package dossier
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+)
+
+func newSpatialTestPage(t *testing.T) *Page {
+	t.Helper()
+
+	p, err := newPage(nil, &fakePage{
+		elems: []content.Element{
+			&fakeLine{
+				text:   "label",
+				bounds: geometry.RectFromCentimeters(1, 1, 3, 1.5),
+			},
+			&fakeLine{
+				text:   "value",
+				bounds: geometry.RectFromCentimeters(5, 1.1, 7, 1.6),
+			},
+			&fakeLine{
+				text:   "far right",
+				bounds: geometry.RectFromCentimeters(15, 1, 17, 1.5),
+			},
+			&fakeLine{
+				text:   "below",
+				bounds: geometry.RectFromCentimeters(1.5, 3, 4, 3.5),
+			},
+			&fakeLine{
+				text:   "below offset",
+				bounds: geometry.RectFromCentimeters(2.9, 5, 8, 5.5),
+			},
+			&fakeLine{
+				text:   "above",
+				bounds: geometry.RectFromCentimeters(0, 0, 1, 0.5),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newPage() failed: %v", err)
+	}
+
+	return p
+}
+
+func elementTexts(elems []content.Element) []string {
+	var result []string
+
+	for _, elem := range elems {
+		result = append(result, elem.(content.TextElement).Text())
+	}
+
+	return result
+}
+
+func TestNearestElements(t *testing.T) {
+	p := newSpatialTestPage(t)
+
+	for _, tc := range []struct {
+		name   string
+		pt     geometry.Point
+		k      int
+		filter PageElementFilterFunc
+		want   []string
+	}{
+		{
+			name: "zero",
+			pt:   geometry.Point{Left: 2 * geometry.Cm, Top: 1 * geometry.Cm},
+		},
+		{
+			name: "inside",
+			pt:   geometry.Point{Left: 2 * geometry.Cm, Top: 1.2 * geometry.Cm},
+			k:    1,
+			want: []string{"label"},
+		},
+		{
+			name: "several",
+			pt:   geometry.Point{Left: 4 * geometry.Cm, Top: 2 * geometry.Cm},
+			k:    3,
+			want: []string{"below", "value", "label"},
+		},
+		{
+			name: "filter",
+			pt:   geometry.Point{Left: 4 * geometry.Cm, Top: 2 * geometry.Cm},
+			k:    2,
+			filter: func(elem content.Element) bool {
+				return elem.(content.TextElement).Text() != "below"
+			},
+			want: []string{"value", "label"},
+		},
+		{
+			name: "all",
+			pt:   geometry.Point{},
+			k:    100,
+			want: []string{"above", "label", "below", "value", "below offset", "far right"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := elementTexts(p.NearestElements(tc.pt, tc.k, tc.filter))
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NearestElements() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestElementsInDirection(t *testing.T) {
+	p := newSpatialTestPage(t)
+
+	label := geometry.RectFromCentimeters(1, 1, 3, 1.5)
+
+	for _, tc := range []struct {
+		name        string
+		dir         geometry.Direction
+		opts        DirectionalSearchOptions
+		want        []string
+		wantNearest string
+	}{
+		{
+			name:        "right",
+			dir:         geometry.Right,
+			want:        []string{"value", "far right"},
+			wantNearest: "value",
+		},
+		{
+			name: "right max distance",
+			dir:  geometry.Right,
+			opts: DirectionalSearchOptions{
+				MaxDistance: 5 * geometry.Cm,
+			},
+			want:        []string{"value"},
+			wantNearest: "value",
+		},
+		{
+			name: "right full overlap",
+			dir:  geometry.Right,
+			opts: DirectionalSearchOptions{
+				MinOverlap: 1,
+			},
+			want:        []string{"far right"},
+			wantNearest: "far right",
+		},
+		{
+			name:        "down",
+			dir:         geometry.Down,
+			want:        []string{"below", "below offset"},
+			wantNearest: "below",
+		},
+		{
+			name: "down filtered",
+			dir:  geometry.Down,
+			opts: DirectionalSearchOptions{
+				Filter: func(elem content.Element) bool {
+					return elem.(content.TextElement).Text() != "below"
+				},
+			},
+			want:        []string{"below offset"},
+			wantNearest: "below offset",
+		},
+		{
+			name: "left",
+			dir:  geometry.Left,
+		},
+		{
+			name: "up",
+			dir:  geometry.Up,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			elems, err := p.ElementsInDirection(label, tc.dir, tc.opts)
+			if err != nil {
+				t.Errorf("ElementsInDirection() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, elementTexts(elems)); diff != "" {
+				t.Errorf("ElementsInDirection() diff (-want +got):\n%s", diff)
+			}
+
+			nearest, err := p.NearestElementInDirection(label, tc.dir, tc.opts)
+			if err != nil {
+				t.Errorf("NearestElementInDirection() failed: %v", err)
+			}
+
+			var got string
+
+			if nearest != nil {
+				got = nearest.(content.TextElement).Text()
+			}
+
+			if diff := cmp.Diff(tc.wantNearest, got); diff != "" {
+				t.Errorf("NearestElementInDirection() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
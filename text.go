@@ -0,0 +1,263 @@
+package dossier
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+)
+
+// textParagraphs returns the blocks of the page and all lines not contained
+// in any block in the order given by the parser.
+func (p *Page) textParagraphs() []content.TextElement {
+	inBlock := map[content.Line]struct{}{}
+
+	for _, elem := range p.elems {
+		if b, ok := elem.(content.Block); ok {
+			for _, l := range b.Lines() {
+				inBlock[l] = struct{}{}
+			}
+		}
+	}
+
+	var result []content.TextElement
+
+	for _, elem := range p.elems {
+		switch elem := elem.(type) {
+		case content.Block:
+			result = append(result, elem)
+
+		case content.Line:
+			if _, ok := inBlock[elem]; !ok {
+				result = append(result, elem)
+			}
+		}
+	}
+
+	return result
+}
+
+// orderedTextParagraphs returns the paragraphs of the page ordered in rows and
+// columns.
+func (p *Page) orderedTextParagraphs() []content.TextElement {
+	paragraphs := p.textParagraphs()
+
+	compareRect := geometry.MakeRectRowColumnCompare(geometry.TopToBottom, geometry.LeftToRight)
+
+	slices.SortStableFunc(paragraphs, func(a, b content.TextElement) int {
+		return cmp.Or(
+			compareRect(a.Bounds(), b.Bounds()),
+			cmp.Compare(a.Bounds().Left, b.Bounds().Left),
+		)
+	})
+
+	return paragraphs
+}
+
+// textLines returns all lines of the page, including those contained in
+// blocks, in the order given by the parser.
+func (p *Page) textLines() []content.Line {
+	seen := map[content.Line]struct{}{}
+
+	var result []content.Line
+
+	add := func(l content.Line) {
+		if _, ok := seen[l]; !ok {
+			seen[l] = struct{}{}
+			result = append(result, l)
+		}
+	}
+
+	for _, elem := range p.elems {
+		switch elem := elem.(type) {
+		case content.Block:
+			for _, l := range elem.Lines() {
+				add(l)
+			}
+
+		case content.Line:
+			add(elem)
+		}
+	}
+
+	return result
+}
+
+// Text returns the plain text of all blocks on the page. Blocks are ordered in
+// rows and columns using [geometry.MakeRectRowColumnCompare] like
+// [Page.LayoutText]. Lines are separated by newlines, blocks by an empty line.
+// See [Page.LayoutText] for a variant preserving the physical layout.
+func (p *Page) Text() string {
+	var buf strings.Builder
+
+	for _, elem := range p.orderedTextParagraphs() {
+		text := strings.TrimRightFunc(elem.Text(), unicode.IsSpace)
+
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteString("\n\n")
+		}
+
+		buf.WriteString(text)
+	}
+
+	if buf.Len() > 0 {
+		buf.WriteByte('\n')
+	}
+
+	return buf.String()
+}
+
+type LayoutTextOptions struct {
+	// Width of a single character column. Derived from the average character
+	// width of all lines on the page when zero.
+	CharWidth geometry.Length
+
+	// Height of a single text row used to determine the number of empty rows
+	// between lines. Derived from the median line height when zero.
+	RowHeight geometry.Length
+}
+
+func (o *LayoutTextOptions) fillDefaults(lines []content.Line) {
+	if o.CharWidth <= 0 {
+		var width geometry.Length
+		var count int
+
+		for _, l := range lines {
+			width += l.Bounds().Width()
+			count += utf8.RuneCountInString(l.Text())
+		}
+
+		if count > 0 {
+			o.CharWidth = width / geometry.Length(count)
+		}
+	}
+
+	if o.RowHeight <= 0 && len(lines) > 0 {
+		heights := make([]geometry.Length, 0, len(lines))
+
+		for _, l := range lines {
+			heights = append(heights, l.Bounds().Height())
+		}
+
+		slices.Sort(heights)
+
+		o.RowHeight = heights[len(heights)/2]
+	}
+}
+
+type layoutTextRow struct {
+	bounds geometry.Rect
+	lines  []content.Line
+}
+
+// sameRow reports whether the vertical center of either rectangle lies within
+// the vertical extent of the other.
+func (r *layoutTextRow) sameRow(other geometry.Rect) bool {
+	rc := r.bounds.Center()
+	oc := other.Center()
+
+	return (r.bounds.Top <= oc.Top && oc.Top <= r.bounds.Bottom) ||
+		(other.Top <= rc.Top && rc.Top <= other.Bottom)
+}
+
+// LayoutText returns the text of the page with lines placed on a grid of
+// characters approximating their physical position, similar to "pdftotext
+// -layout". Lines are ordered using [geometry.MakeRectRowColumnCompare] and
+// padded with spaces. Vertical gaps are reproduced using empty rows.
+func (p *Page) LayoutText(opts LayoutTextOptions) string {
+	var lines []content.Line
+
+	for _, l := range p.textLines() {
+		if strings.TrimSpace(l.Text()) != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	opts.fillDefaults(lines)
+
+	compareRect := geometry.MakeRectRowColumnCompare(geometry.TopToBottom, geometry.LeftToRight)
+
+	slices.SortStableFunc(lines, func(a, b content.Line) int {
+		return cmp.Or(
+			compareRect(a.Bounds(), b.Bounds()),
+			cmp.Compare(a.Bounds().Left, b.Bounds().Left),
+		)
+	})
+
+	var rows []*layoutTextRow
+
+	origin := lines[0].Bounds().Left
+
+	for _, l := range lines {
+		bounds := l.Bounds()
+		origin = origin.Min(bounds.Left)
+
+		if len(rows) > 0 {
+			if last := rows[len(rows)-1]; last.sameRow(bounds) {
+				last.bounds = last.bounds.Union(bounds)
+				last.lines = append(last.lines, l)
+				continue
+			}
+		}
+
+		rows = append(rows, &layoutTextRow{
+			bounds: bounds,
+			lines:  []content.Line{l},
+		})
+	}
+
+	var buf strings.Builder
+
+	for idx, row := range rows {
+		if idx > 0 && opts.RowHeight > 0 {
+			gap := row.bounds.Top - rows[idx-1].bounds.Bottom
+
+			for range int(math.Floor(float64(gap / opts.RowHeight))) {
+				buf.WriteByte('\n')
+			}
+		}
+
+		slices.SortStableFunc(row.lines, func(a, b content.Line) int {
+			return cmp.Compare(a.Bounds().Left, b.Bounds().Left)
+		})
+
+		var rowBuf []rune
+
+		for _, l := range row.lines {
+			col := 0
+
+			if opts.CharWidth > 0 {
+				col = int(math.Round(float64((l.Bounds().Left - origin) / opts.CharWidth)))
+			}
+
+			if len(rowBuf) > 0 {
+				// Keep at least one space between lines.
+				col = max(col, len(rowBuf)+1)
+			}
+
+			for len(rowBuf) < col {
+				rowBuf = append(rowBuf, ' ')
+			}
+
+			rowBuf = append(rowBuf, []rune(strings.TrimRightFunc(l.Text(), unicode.IsSpace))...)
+		}
+
+		buf.WriteString(string(rowBuf))
+		buf.WriteByte('\n')
+	}
+
+	return buf.String()
+}
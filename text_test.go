@@ -0,0 +1,90 @@
+package dossier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hansmi/dossier/pkg/content"
+	"github.com/hansmi/dossier/pkg/geometry"
+)
+
+func TestPageText(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		page       content.Page
+		opts       LayoutTextOptions
+		want       string
+		wantLayout string
+	}{
+		{
+			name: "empty",
+			page: &fakePage{},
+		},
+		{
+			name: "lines",
+			page: &fakePage{
+				elems: []content.Element{
+					&fakeLine{
+						text:   "right",
+						bounds: geometry.RectFromCentimeters(5, 1, 7, 1.5),
+					},
+					&fakeLine{
+						text:   "left  ",
+						bounds: geometry.RectFromCentimeters(1, 1, 3, 1.5),
+					},
+					&fakeLine{
+						text:   " \t",
+						bounds: geometry.RectFromCentimeters(1, 2, 3, 2.5),
+					},
+					&fakeLine{
+						text:   "below",
+						bounds: geometry.RectFromCentimeters(2, 2.5, 4, 3),
+					},
+				},
+			},
+			opts: LayoutTextOptions{
+				CharWidth: 0.5 * geometry.Cm,
+			},
+			want:       "left\n\nright\n\nbelow\n",
+			wantLayout: "left    right\n\n\n  below\n",
+		},
+		{
+			name: "corners",
+			page: mustReadPages(t, "corners.xml")[0],
+			want: "TL\n\nTR\n\nBL\n\nBR\n",
+			wantLayout: "TL               TR\n" +
+				strings.Repeat("\n", 14) +
+				"BL               BR\n",
+		},
+		{
+			name: "lorem-mixed",
+			page: mustReadPages(t, "lorem-mixed.xml")[0],
+			want: "Lorem ipsum dolor sit amet, consectetur adipisici elit, sed eiusmod tempor incidunt \n" +
+				"ut labore et dolore magna aliqua.\n\n" +
+				"At vero eos et accusam et justo duo dolores et ea\n" +
+				"rebum. Stet clita kasd gubergren, no sea\n\n" +
+				"takimata sanctus est Lorem ipsum dolor sit \n" +
+				"amet.\n",
+			wantLayout: "Lorem ipsum dolor sit amet, consectetur adipisici elit, sed eiusmod tempor incidunt\n" +
+				"ut labore et dolore magna aliqua.\n\n\n\n" +
+				"At vero eos et accusam et justo duo dolores et ea takimata sanctus est Lorem ipsum dolor sit\n" +
+				"rebum. Stet clita kasd gubergren, no sea      amet.\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newPage(nil, tc.page)
+			if err != nil {
+				t.Fatalf("newPage() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, p.Text()); diff != "" {
+				t.Errorf("Text() diff (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.wantLayout, p.LayoutText(tc.opts)); diff != "" {
+				t.Errorf("LayoutText() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}